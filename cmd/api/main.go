@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 
+	"timesheet-api/internal/auth"
 	"timesheet-api/internal/config"
 	appdb "timesheet-api/internal/db"
+	"timesheet-api/internal/payroll"
+	"timesheet-api/internal/repository"
 	"timesheet-api/internal/repository/postgres"   // ← BENAR (tanpa alias 'http')
 	"timesheet-api/internal/resp"
 	transport "timesheet-api/internal/transport/http"
@@ -21,16 +26,104 @@ import (
 func main() {
 	cfg := config.Load()
 
-	dbx := openPG(cfg.DB_DSN)
+	// DB_DRIVER=pgxpool: repository timesheet memakai pool pgx langsung; repository
+	// lain tetap database/sql, di atas pool yang sama.
+	var dbx *sql.DB
+	var pool *pgxpool.Pool
+	switch cfg.DBDriver {
+	case "sql":
+		dbx = openPG(cfg.DB_DSN)
+	case "pgxpool":
+		pool = openPool(cfg.DB_DSN, postgres.PoolConfig{
+			MaxConns:          int32(cfg.DBMaxConns),
+			MinConns:          int32(cfg.DBMinConns),
+			MaxConnLifetime:   cfg.DBMaxConnLifetime,
+			MaxConnIdleTime:   cfg.DBMaxConnIdleTime,
+			HealthCheckPeriod: cfg.DBHealthCheckPeriod,
+			StatementCache:    cfg.DBStatementCache,
+			TimeZone:          "Asia/Jakarta",
+		})
+		defer pool.Close()
+		dbx = stdlib.OpenDBFromPool(pool)
+	default:
+		log.Fatalf("DB_DRIVER=%q tidak dikenal (sql | pgxpool)", cfg.DBDriver)
+	}
 	defer dbx.Close()
 
-	if err := appdb.Migrate(dbx); err != nil {
-		log.Fatal(err)
+	if cfg.AutoMigrate {
+		pending, err := appdb.Migrate(dbx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, st := range pending {
+			log.Printf("migrasi %d_%s masih tertunda (manual), jalankan cmd/migrate up", st.Version, st.Name)
+		}
+	} else {
+		log.Println("AUTO_MIGRATE=false: migrasi dilewati, jalankan cmd/migrate up secara terpisah")
 	}
 
-	repo := postgres.NewTimesheetRepoPG(dbx) // ⬅️ panggil lewat nama paket "postgres"
-	svc := usecase.NewTimesheetService(repo)
+	timeouts := postgres.QueryTimeouts{Read: cfg.DBReadTimeout, Write: cfg.DBWriteTimeout, Stream: cfg.DBStreamTimeout}
+	var repo repository.TimesheetRepository
+	var txm repository.TxManager
+	if pool != nil {
+		repo = postgres.NewTimesheetRepoPGX(pool).WithTimeouts(timeouts)
+		txm = postgres.NewTxManagerPGX(pool).WithTimeouts(timeouts)
+	} else {
+		repo = postgres.NewTimesheetRepoPG(dbx).WithTimeouts(timeouts) // ⬅️ panggil lewat nama paket "postgres"
+		txm = postgres.NewTxManagerPG(dbx).WithTimeouts(timeouts)
+	}
+	empRepo := postgres.NewEmployeeRepoPG(dbx)
+	deptRepo := postgres.NewDepartmentRepoPG(dbx)
+	otRepo := postgres.NewOvertimePolicyRepoPG(dbx)
+	holidayRepo := postgres.NewHolidayRepoPG(dbx)
+	projectRepo := postgres.NewProjectRepoPG(dbx)
+	svc := usecase.NewTimesheetService(repo, empRepo, deptRepo, otRepo, holidayRepo, projectRepo).
+		WithTx(txm)
 	h := transport.NewTimesheetHandler(svc)
+	eh := transport.NewEmployeeHandler(usecase.NewEmployeeService(empRepo, deptRepo))
+	dh := transport.NewDepartmentHandler(usecase.NewDepartmentService(deptRepo, empRepo, otRepo))
+	oh := transport.NewOvertimePolicyHandler(usecase.NewOvertimePolicyService(otRepo))
+	hh := transport.NewHolidayHandler(usecase.NewHolidayService(holidayRepo))
+	prh := transport.NewProjectHandler(usecase.NewProjectService(projectRepo, deptRepo))
+	bh := transport.NewBillingHandler(usecase.NewBillingService(postgres.NewBillingRepoPG(dbx)))
+	loc, err := time.LoadLocation(cfg.TZ)
+	if err != nil {
+		log.Printf("warning: TZ=%q tidak dikenal (%v), pakai zona waktu lokal", cfg.TZ, err)
+		loc = time.Local
+	}
+	ph := transport.NewPunchHandler(usecase.NewPunchService(postgres.NewPunchRepoPG(dbx).WithTimeouts(timeouts), svc, usecase.PunchRule{
+		MaxOpen:    cfg.PunchMaxOpen,
+		CloseAfter: cfg.PunchCloseAfter,
+		CloseAt:    cfg.PunchCloseAt,
+	}, loc))
+	ih := transport.NewImportHandler(usecase.NewImportService(postgres.NewImportRepoPG(dbx), svc))
+	formula, err := payroll.Kepmen102(cfg.PayrollWorkWeek)
+	if err != nil {
+		log.Fatal(err)
+	}
+	payrollCols, err := payroll.ParseColumns(cfg.PayrollColumns, formula)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pyh := transport.NewPayrollHandler(usecase.NewPayrollService(repo, empRepo, formula, payrollCols))
+	lh := transport.NewLeaveHandler(usecase.NewLeaveService(postgres.NewLeaveRepoPG(dbx).WithTimeouts(timeouts), repo, empRepo, deptRepo, holidayRepo))
+
+	tokens, err := auth.NewTokenManager(auth.TokenConfig{
+		Algorithm:      cfg.JWTAlgorithm,
+		Secret:         cfg.JWTSecret,
+		PrivateKeyFile: cfg.JWTPrivateKeyFile,
+		PublicKeyFile:  cfg.JWTPublicKeyFile,
+		Issuer:         cfg.JWTIssuer,
+		AccessTTL:      cfg.AccessTokenTTL,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	authSvc := usecase.NewAuthService(postgres.NewUserRepoPG(dbx), postgres.NewRefreshTokenRepoPG(dbx), tokens, cfg.RefreshTokenTTL)
+	if err := authSvc.EnsureAdmin(cfg.AdminUsername, cfg.AdminPassword); err != nil {
+		log.Fatal(err)
+	}
+	ah := transport.NewAuthHandler(authSvc)
 
 	r := gin.Default()
 
@@ -44,6 +137,7 @@ func main() {
 	r.ForwardedByClientIP = true
 
 	r.Use(middleware.RequestID(), middleware.RecoveryJSON())
+	r.Use(middleware.Auth(tokens, append([]string{"/health"}, transport.AuthPublicPaths...)...))
 
 	r.GET("/health", func(c *gin.Context) {
 		if err := dbx.Ping(); err != nil {
@@ -53,7 +147,9 @@ func main() {
 		resp.OK(c, gin.H{"status": "ok"}, "Healthy")
 	})
 
-	h.Register(r)
+	for _, rt := range []transport.Routes{h, eh, dh, oh, hh, lh, ph, prh, bh, pyh, ih, ah} {
+		rt.Register(r)
+	}
 	for _, ri := range r.Routes() {
 	log.Printf("[ROUTE] %s %s -> %s", ri.Method, ri.Path, ri.Handler)
 	}
@@ -84,3 +180,20 @@ func openPG(dsn string) *sql.DB {
 	log.Fatalf("failed to connect DB: %v", err)
 	return nil
 }
+
+func openPool(dsn string, pc postgres.PoolConfig) *pgxpool.Pool {
+	if dsn == "" {
+		log.Fatal("missing DB_DSN")
+	}
+	var err error
+	for i := 0; i < 10; i++ {
+		var pool *pgxpool.Pool
+		pool, err = postgres.OpenPool(context.Background(), dsn, pc)
+		if err == nil {
+			return pool
+		}
+		time.Sleep(time.Duration(500*(i+1)) * time.Millisecond)
+	}
+	log.Fatalf("failed to connect DB: %v", err)
+	return nil
+}
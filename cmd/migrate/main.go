@@ -0,0 +1,144 @@
+// Command migrate mengelola migrasi database (internal/db/migrations, atau
+// folder MIGRATIONS_DIR jika diset).
+//
+//	go run ./cmd/migrate [--dry-run] status | up [N] | down [N] | redo | create <name>
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"timesheet-api/internal/config"
+	appdb "timesheet-api/internal/db"
+)
+
+const usage = `Usage: migrate [--dry-run] <command>
+
+Commands:
+  status         daftar migrasi dan statusnya
+  up [N]         terapkan N migrasi tertunda (default: semua)
+  down [N]       rollback N migrasi terakhir (default: 1)
+  redo           rollback lalu terapkan ulang migrasi terakhir
+  create <name>  buat pasangan file <timestamp>_<name>.up.sql / .down.sql
+
+Flags:
+`
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "tampilkan SQL yang akan dijalankan tanpa mengeksekusinya")
+	dir := flag.String("dir", "", "folder tujuan create (default: MIGRATIONS_DIR atau internal/db/migrations)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if err := run(flag.Args(), *dryRun, *dir); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, dryRun bool, dir string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cmd, rest := args[0], args[1:]
+
+	if cmd == "create" {
+		if len(rest) != 1 { return fmt.Errorf("create butuh satu nama, mis. create add_timesheet_notes") }
+		if dir == "" { dir = os.Getenv("MIGRATIONS_DIR") }
+		if dir == "" { dir = "internal/db/migrations" }
+		paths, err := appdb.Create(dir, rest[0], time.Now())
+		for _, p := range paths { fmt.Println("created", p) }
+		return err
+	}
+
+	switch cmd {
+	case "status", "up", "down", "redo":
+	default:
+		return fmt.Errorf("perintah tidak dikenal: %s", cmd)
+	}
+	n, err := count(rest)
+	if err != nil { return err }
+	cfg := config.Load()
+	if cfg.DB_DSN == "" { return fmt.Errorf("DB_DSN kosong") }
+	db, err := sql.Open("pgx", cfg.DB_DSN)
+	if err != nil { return err }
+	defer db.Close()
+	m, err := appdb.NewMigrator(db, appdb.Source())
+	if err != nil { return err }
+	m.DryRun = dryRun
+	ctx := context.Background()
+
+	switch cmd {
+	case "status":
+		return status(ctx, m)
+	case "up":
+		done, err := m.Up(ctx, n)
+		report("up", done, dryRun, func(mg appdb.Migration) string { return mg.Up })
+		return err
+	case "down":
+		if n == 0 { n = 1 }
+		done, err := m.Down(ctx, n)
+		report("down", done, dryRun, func(mg appdb.Migration) string { return mg.Down })
+		return err
+	case "redo":
+		mg, err := m.Redo(ctx)
+		if mg != nil {
+			report("down", []appdb.Migration{*mg}, dryRun, func(mg appdb.Migration) string { return mg.Down })
+			report("up", []appdb.Migration{*mg}, dryRun, func(mg appdb.Migration) string { return mg.Up })
+		} else if err == nil {
+			fmt.Println("belum ada migrasi yang diterapkan")
+		}
+		return err
+	}
+	return nil
+}
+
+// count: argumen N opsional untuk up/down.
+func count(args []string) (int, error) {
+	if len(args) == 0 { return 0, nil }
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || len(args) > 1 { return 0, fmt.Errorf("N harus bilangan bulat ≥ 1") }
+	return n, nil
+}
+
+func status(ctx context.Context, m *appdb.Migrator) error {
+	sts, err := m.Status(ctx)
+	if err != nil { return err }
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, st := range sts {
+		state, at := "pending", ""
+		if st.Manual { state = "pending (manual)" }
+		if st.Applied != nil {
+			state, at = "applied", st.Applied.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if st.Changed { state = "applied, CHECKSUM BERUBAH" }
+		if st.Missing { state = "applied, FILE HILANG" }
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.Version, st.Name, state, at)
+	}
+	return w.Flush()
+}
+
+// report mencetak migrasi yang dijalankan; dengan --dry-run beserta SQL-nya.
+func report(dir string, done []appdb.Migration, dryRun bool, script func(appdb.Migration) string) {
+	if len(done) == 0 && dir == "up" { fmt.Println("tidak ada migrasi tertunda") }
+	for _, mg := range done {
+		if !dryRun {
+			fmt.Printf("%s %d_%s\n", dir, mg.Version, mg.Name)
+			continue
+		}
+		fmt.Printf("-- [dry-run] %s %d_%s\n%s\n", dir, mg.Version, mg.Name, strings.TrimRight(script(mg), "\n"))
+	}
+}
@@ -0,0 +1,45 @@
+// Package audit berisi helper untuk audit trail: request ID di context dan
+// diff before/after antar snapshot.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID menyimpan request ID (dari middleware.RequestID) di context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom mengembalikan request ID dari context, "" jika tidak ada.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Diff membandingkan dua snapshot dan mengembalikan field yang berubah saja.
+// before nil → create (semua field masuk after); after nil → delete.
+func Diff(before, after map[string]interface{}) (b, a json.RawMessage, err error) {
+	if before == nil || after == nil {
+		if before != nil { b, err = json.Marshal(before) }
+		if after != nil { a, err = json.Marshal(after) }
+		return b, a, err
+	}
+	bd, ad := map[string]interface{}{}, map[string]interface{}{}
+	for k, av := range after {
+		bj, err := json.Marshal(before[k])
+		if err != nil { return nil, nil, err }
+		aj, err := json.Marshal(av)
+		if err != nil { return nil, nil, err }
+		if !bytes.Equal(bj, aj) {
+			bd[k], ad[k] = before[k], av
+		}
+	}
+	if b, err = json.Marshal(bd); err != nil { return nil, nil, err }
+	a, err = json.Marshal(ad)
+	return b, a, err
+}
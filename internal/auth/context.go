@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"timesheet-api/internal/domain"
+)
+
+type principalKey struct{}
+
+// WithPrincipal menyimpan principal di context request agar bisa dibaca usecase.
+func WithPrincipal(ctx context.Context, p *domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom mengembalikan principal dari context, nil jika anonim.
+func PrincipalFrom(ctx context.Context) *domain.Principal {
+	p, _ := ctx.Value(principalKey{}).(*domain.Principal)
+	return p
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"timesheet-api/internal/domain"
+)
+
+type TokenConfig struct {
+	Algorithm      string // HS256 | RS256
+	Secret         string // HS256
+	PrivateKeyFile string // RS256, PEM
+	PublicKeyFile  string // RS256, PEM; opsional jika private key tersedia
+	Issuer         string
+	AccessTTL      time.Duration
+}
+
+type claims struct {
+	Username   string      `json:"username"`
+	Role       domain.Role `json:"role"`
+	EmployeeID *int64      `json:"employee_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager menerbitkan dan memvalidasi access token JWT.
+type TokenManager struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+	ttl       time.Duration
+}
+
+func NewTokenManager(cfg TokenConfig) (*TokenManager, error) {
+	m := &TokenManager{issuer: cfg.Issuer, ttl: cfg.AccessTTL}
+	switch cfg.Algorithm {
+	case "", "HS256":
+		if len(cfg.Secret) < 32 {
+			return nil, errors.New("JWT_SECRET minimal 32 karakter untuk HS256")
+		}
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(cfg.Secret)
+		m.verifyKey = []byte(cfg.Secret)
+	case "RS256":
+		m.method = jwt.SigningMethodRS256
+		if cfg.PrivateKeyFile != "" {
+			b, err := os.ReadFile(cfg.PrivateKeyFile)
+			if err != nil { return nil, err }
+			key, err := jwt.ParseRSAPrivateKeyFromPEM(b)
+			if err != nil { return nil, fmt.Errorf("JWT private key: %w", err) }
+			m.signKey = key
+			m.verifyKey = &key.PublicKey
+		}
+		if cfg.PublicKeyFile != "" {
+			b, err := os.ReadFile(cfg.PublicKeyFile)
+			if err != nil { return nil, err }
+			key, err := jwt.ParseRSAPublicKeyFromPEM(b)
+			if err != nil { return nil, fmt.Errorf("JWT public key: %w", err) }
+			m.verifyKey = key
+		}
+		if m.verifyKey == nil {
+			return nil, errors.New("RS256 butuh JWT_PRIVATE_KEY_FILE atau JWT_PUBLIC_KEY_FILE")
+		}
+	default:
+		return nil, fmt.Errorf("JWT_ALG %q tidak didukung", cfg.Algorithm)
+	}
+	if m.ttl <= 0 { m.ttl = 15 * time.Minute }
+	return m, nil
+}
+
+func (m *TokenManager) AccessTTL() time.Duration { return m.ttl }
+
+// Issue menerbitkan access token untuk principal p.
+func (m *TokenManager) Issue(p domain.Principal) (string, error) {
+	if m.signKey == nil {
+		return "", errors.New("token manager hanya bisa verifikasi (tanpa private key)")
+	}
+	now := time.Now()
+	c := claims{
+		Username:   p.Username,
+		Role:       p.Role,
+		EmployeeID: p.EmployeeID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(p.UserID, 10),
+			Issuer:    m.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.ttl)),
+		},
+	}
+	return jwt.NewWithClaims(m.method, c).SignedString(m.signKey)
+}
+
+// Verify memvalidasi signature, algoritma, issuer dan masa berlaku token.
+func (m *TokenManager) Verify(token string) (*domain.Principal, error) {
+	var c claims
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{m.method.Alg()}), jwt.WithExpirationRequired()}
+	if m.issuer != "" { opts = append(opts, jwt.WithIssuer(m.issuer)) }
+	_, err := jwt.ParseWithClaims(token, &c, func(*jwt.Token) (interface{}, error) { return m.verifyKey, nil }, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+	uid, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: subject tidak valid", domain.ErrUnauthorized)
+	}
+	return &domain.Principal{UserID: uid, Username: c.Username, Role: c.Role, EmployeeID: c.EmployeeID}, nil
+}
+
+// NewOpaqueToken membuat refresh token acak beserta hash SHA-256 untuk disimpan di DB.
+func NewOpaqueToken() (raw, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil { return "", "", err }
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, HashToken(raw), nil
+}
+
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+// Package calendar berisi perhitungan hari kerja dan parser iCalendar (.ics)
+// untuk kalender hari libur.
+package calendar
+
+import (
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// MonthRange mengembalikan tanggal pertama dan terakhir bulan tersebut.
+func MonthRange(year, month int) (time.Time, time.Time) {
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return first, first.AddDate(0, 1, -1)
+}
+
+// IsWeekend: Sabtu & Minggu.
+func IsWeekend(d time.Time) bool {
+	return d.Weekday() == time.Saturday || d.Weekday() == time.Sunday
+}
+
+// WorkingDays menghitung hari kerja (Senin–Jumat) dalam sebulan dikurangi hari
+// libur yang jatuh di hari kerja.
+func WorkingDays(year, month int, holidays []domain.Holiday) int {
+	first, last := MonthRange(year, month)
+	return len(WorkingDates(first, last, holidays))
+}
+
+// WorkingDates mengembalikan tanggal hari kerja di [from, to], tanpa akhir
+// pekan dan hari libur.
+func WorkingDates(from, to time.Time, holidays []domain.Holiday) []time.Time {
+	off := make(map[string]bool, len(holidays))
+	for _, h := range holidays {
+		off[h.Date.Format("2006-01-02")] = true
+	}
+	var out []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !IsWeekend(d) && !off[d.Format("2006-01-02")] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
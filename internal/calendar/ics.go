@@ -0,0 +1,93 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// ParseICS membaca VEVENT dari file iCalendar (mis. kalender "Hari Libur di
+// Indonesia" dari Google Calendar). Event beberapa hari (DTEND eksklusif)
+// dipecah per tanggal. Summary yang mengandung "cuti bersama" diberi kind
+// cuti_bersama, sisanya defaultKind.
+func ParseICS(r io.Reader, defaultKind domain.HolidayKind) ([]domain.Holiday, error) {
+	lines, err := unfold(r)
+	if err != nil { return nil, err }
+
+	var out []domain.Holiday
+	var start, end *time.Time
+	var summary string
+	inEvent := false
+	for i, line := range lines {
+		name, value, ok := splitProp(line)
+		if !ok { continue }
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, summary = true, nil, nil, ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent { continue }
+			inEvent = false
+			if start == nil {
+				return nil, fmt.Errorf("%w: VEVENT tanpa DTSTART (baris %d)", domain.ErrInvalidInput, i+1)
+			}
+			last := *start
+			if end != nil && end.After(*start) { last = end.AddDate(0, 0, -1) }
+			kind := defaultKind
+			if strings.Contains(strings.ToLower(summary), "cuti bersama") { kind = domain.HolidayCutiBersama }
+			for d := *start; !d.After(last); d = d.AddDate(0, 0, 1) {
+				out = append(out, domain.Holiday{Date: d, Name: summary, Kind: kind})
+			}
+		case !inEvent:
+			// properti di luar VEVENT (VCALENDAR, VTIMEZONE, ...) diabaikan
+		case name == "DTSTART" || name == "DTEND":
+			d, err := parseICSDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s tidak valid (baris %d)", domain.ErrInvalidInput, name, i+1)
+			}
+			if name == "DTSTART" { start = &d } else { end = &d }
+		case name == "SUMMARY":
+			summary = unescapeText(value)
+		}
+	}
+	return out, nil
+}
+
+// unfold menggabungkan baris lanjutan (diawali spasi/tab) sesuai RFC 5545.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+// splitProp memecah "NAME;PARAM=x:VALUE" menjadi NAME dan VALUE.
+func splitProp(line string) (string, string, bool) {
+	i := strings.Index(line, ":")
+	if i < 0 { return "", "", false }
+	name := line[:i]
+	if j := strings.Index(name, ";"); j >= 0 { name = name[:j] }
+	return strings.ToUpper(name), line[i+1:], true
+}
+
+// parseICSDate menerima DATE (20250101) maupun DATE-TIME (20250101T000000[Z]);
+// jamnya diabaikan.
+func parseICSDate(v string) (time.Time, error) {
+	if len(v) < 8 { return time.Time{}, fmt.Errorf("tanggal %q terlalu pendek", v) }
+	return time.Parse("20060102", v[:8])
+}
+
+func unescapeText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s))
+}
@@ -1,8 +1,50 @@
 package config
 
+import "time"
+
 type Config struct {
 	Port  string
 	DB_DSN string
 	Env   string
 	TZ    string
+
+	// AutoMigrate: jalankan migrasi tertunda saat API start. Matikan jika
+	// migrasi dijalankan terpisah lewat cmd/migrate (mis. job deploy).
+	AutoMigrate bool
+
+	// Batas waktu query timesheet per operasi; 0 = tanpa batas (hanya sampai
+	// client putus). Query yang melewatinya dijawab 504.
+	DBReadTimeout   time.Duration
+	DBWriteTimeout  time.Duration
+	DBStreamTimeout time.Duration // seluruh export CSV/NDJSON
+
+	// DBDriver: implementasi repository timesheet. "sql" = database/sql (pgx
+	// stdlib), "pgxpool" = pgx native dengan pool & cache statement di bawah.
+	DBDriver            string
+	DBMaxConns          int           // 0 = default pgxpool
+	DBMinConns          int
+	DBMaxConnLifetime   time.Duration // 0 = default pgxpool
+	DBMaxConnIdleTime   time.Duration
+	DBHealthCheckPeriod time.Duration
+	DBStatementCache    int // prepared statement per koneksi; 0 = tanpa cache (PgBouncer mode transaction)
+
+	// Auth
+	JWTAlgorithm      string // HS256 | RS256
+	JWTSecret         string
+	JWTPrivateKeyFile string
+	JWTPublicKeyFile  string
+	JWTIssuer         string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	AdminUsername     string // user hr_admin awal, dibuat saat start jika belum ada
+	AdminPassword     string
+
+	// Punch: aturan auto-close punch in yang lupa di-punch out
+	PunchMaxOpen    time.Duration // lebih lama dari ini = lupa punch out
+	PunchCloseAfter time.Duration // jam selesai otomatis = punch in + durasi ini
+	PunchCloseAt    string        // "17:00"; opsional, menggantikan PunchCloseAfter jika setelah punch in
+
+	// Payroll export
+	PayrollWorkWeek int    // 5 atau 6 hari kerja seminggu, menentukan tier lembur hari istirahat
+	PayrollColumns  string // "NIK:employee_code,Nama:employee_name,..."; kosong = semua field
 }
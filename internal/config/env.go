@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +17,37 @@ func Load() Config {
 		DB_DSN: getenv("DB_DSN", ""),
 		Env:   getenv("APP_ENV", "development"),
 		TZ:    getenv("TZ", "Asia/Jakarta"),
+
+		AutoMigrate: getenvBool("AUTO_MIGRATE", true),
+
+		DBReadTimeout:   getenvDuration("DB_READ_TIMEOUT", 5*time.Second),
+		DBWriteTimeout:  getenvDuration("DB_WRITE_TIMEOUT", 10*time.Second),
+		DBStreamTimeout: getenvDuration("DB_STREAM_TIMEOUT", 10*time.Minute),
+
+		DBDriver:            getenv("DB_DRIVER", "sql"),
+		DBMaxConns:          getenvInt("DB_MAX_CONNS", 0),
+		DBMinConns:          getenvInt("DB_MIN_CONNS", 0),
+		DBMaxConnLifetime:   getenvDuration("DB_MAX_CONN_LIFETIME", 0),
+		DBMaxConnIdleTime:   getenvDuration("DB_MAX_CONN_IDLE_TIME", 0),
+		DBHealthCheckPeriod: getenvDuration("DB_HEALTH_CHECK_PERIOD", 0),
+		DBStatementCache:    getenvInt("DB_STATEMENT_CACHE", 512),
+
+		JWTAlgorithm:      getenv("JWT_ALG", "HS256"),
+		JWTSecret:         getenv("JWT_SECRET", ""),
+		JWTPrivateKeyFile: getenv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTPublicKeyFile:  getenv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:         getenv("JWT_ISSUER", "timesheet-api"),
+		AccessTokenTTL:    getenvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getenvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		AdminUsername:     getenv("ADMIN_USERNAME", ""),
+		AdminPassword:     getenv("ADMIN_PASSWORD", ""),
+
+		PunchMaxOpen:    getenvDuration("PUNCH_MAX_OPEN", 16*time.Hour),
+		PunchCloseAfter: getenvDuration("PUNCH_AUTO_CLOSE_AFTER", 8*time.Hour),
+		PunchCloseAt:    getenv("PUNCH_AUTO_CLOSE_AT", ""),
+
+		PayrollWorkWeek: getenvInt("PAYROLL_WORK_WEEK", 5),
+		PayrollColumns:  getenv("PAYROLL_COLUMNS", ""),
 	}
 	if cfg.DB_DSN == "" {
 		log.Println("warning: DB_DSN empty")
@@ -28,3 +61,42 @@ func getenv(k, def string) string {
 	}
 	return def
 }
+
+func getenvInt(k string, def int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("warning: %s=%q bukan angka, pakai %d", k, v, def)
+		return def
+	}
+	return n
+}
+
+func getenvBool(k string, def bool) bool {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("warning: %s=%q bukan boolean, pakai %t", k, v, def)
+		return def
+	}
+	return b
+}
+
+func getenvDuration(k string, def time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("warning: %s=%q bukan durasi valid, pakai %s", k, v, def)
+		return def
+	}
+	return d
+}
@@ -1,71 +1,391 @@
 package db
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
 	"embed"
+	"encoding/hex"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Embed semua file .sql di internal/db/migrations/ (path relatif ke file ini)
 //go:embed migrations/*.sql
 var embeddedMigrations embed.FS
 
-// Migrate akan:
-// 1) Jika ENV MIGRATIONS_DIR diset → baca dari folder itu (untuk dev/override).
-// 2) Jika tidak → pakai file yang di-embed.
-func Migrate(db *sql.DB) error {
+// migrateLockKey: kunci pg_advisory_lock supaya hanya satu replika yang
+// menjalankan migrasi pada satu waktu.
+const migrateLockKey int64 = 7_246_381_901
+
+// Nama file: <versi>_<nama>.sql (hanya up), atau pasangan <versi>_<nama>.up.sql
+// dan <versi>_<nama>.down.sql.
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+?)(\.up|\.down)?\.sql$`)
+
+// manualDirective di file up menandai migrasi yang hanya dijalankan lewat
+// cmd/migrate, tidak otomatis saat API start (lihat Migrate).
+var manualDirective = regexp.MustCompile(`(?m)^--\s*migrate:manual\s*$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // kosong = tidak bisa di-rollback
+	Checksum string // sha256 isi Up, ditambah Down jika ada
+	Manual   bool   // file up memuat baris "-- migrate:manual"
+
+	upChecksum string // sha256 Up saja: format ledger sebelum Down ikut dihitung
+}
+
+// Applied: baris di schema_migrations.
+type Applied struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrate menjalankan migrasi yang belum tercatat di schema_migrations, untuk
+// dipanggil saat API start. Sumbernya folder ENV MIGRATIONS_DIR jika diset (untuk
+// dev/override), selain itu file yang di-embed. Database lama yang belum punya
+// ledger aman: migrasi 0001–0015 ditulis idempoten, jadi dijalankan ulang sekali
+// lalu tercatat, dan setelah itu tidak pernah dijalankan lagi.
+//
+// Migrasi manual (mis. 0016, yang gagal selama data lama masih dobel) tidak
+// dijalankan: Migrate berhenti sebelum migrasi manual pertama dan mengembalikan
+// migrasi yang masih tertunda, supaya API tetap jalan dan operator menerapkannya
+// lewat cmd/migrate up.
+func Migrate(db *sql.DB) (pending []MigrationStatus, err error) {
+	m, err := NewMigrator(db, Source())
+	if err != nil { return nil, err }
+	m.SkipManual = true
+	if _, err := m.Up(context.Background(), 0); err != nil { return nil, err }
+	sts, err := m.Status(context.Background())
+	if err != nil { return nil, err }
+	for _, st := range sts {
+		if st.Applied == nil { pending = append(pending, st) }
+	}
+	return pending, nil
+}
+
+// Source: folder MIGRATIONS_DIR jika diset, selain itu migrasi yang di-embed.
+func Source() fs.FS {
 	if dir := strings.TrimSpace(os.Getenv("MIGRATIONS_DIR")); dir != "" {
-		return runFromDir(db, dir)
+		return os.DirFS(dir)
+	}
+	sub, _ := fs.Sub(embeddedMigrations, "migrations")
+	return sub
+}
+
+// Load membaca dan mengurutkan migrasi dari src (file .sql di root-nya).
+func Load(src fs.FS) ([]Migration, error) {
+	ents, err := fs.ReadDir(src, ".")
+	if err != nil { return nil, err }
+	byVersion := map[int64]*Migration{}
+	for _, e := range ents {
+		if e.IsDir() || !strings.HasSuffix(strings.ToLower(e.Name()), ".sql") { continue }
+		parts := migrationFile.FindStringSubmatch(e.Name())
+		if parts == nil { return nil, fmt.Errorf("migrasi %s: nama harus <versi>_<nama>[.up|.down].sql", e.Name()) }
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil { return nil, fmt.Errorf("migrasi %s: %w", e.Name(), err) }
+		b, err := fs.ReadFile(src, e.Name())
+		if err != nil { return nil, err }
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.Name != parts[2] { return nil, fmt.Errorf("migrasi versi %d dipakai dua nama: %s dan %s", version, m.Name, parts[2]) }
+		if parts[3] == ".down" {
+			if m.Down != "" { return nil, fmt.Errorf("migrasi versi %d: file down ganda", version) }
+			m.Down = string(b)
+			continue
+		}
+		if m.Up != "" { return nil, fmt.Errorf("migrasi versi %d: file up ganda", version) }
+		m.Up = string(b)
+		m.Manual = manualDirective.Match(b)
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" { return nil, fmt.Errorf("migrasi versi %d (%s): file down tanpa file up", m.Version, m.Name) }
+		m.Checksum, m.upChecksum = checksum(m.Up, m.Down), checksum(m.Up, "")
+		out = append(out, *m)
 	}
-	return runFromFS(db, embeddedMigrations, "migrations")
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// checksum: sha256 dari up, lalu down (dipisah byte 0) supaya rollback yang
+// diubah juga terdeteksi. Migrasi tanpa down tetap sha256 isi up saja.
+func checksum(up, down string) string {
+	h := sha256.New()
+	h.Write([]byte(up))
+	if down != "" {
+		h.Write([]byte{0})
+		h.Write([]byte(down))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// matches: checksum ledger sama dengan file, atau masih checksum up saja yang
+// dicatat sebelum file down-nya ditambahkan.
+func (mg Migration) matches(sum string) bool {
+	return sum == mg.Checksum || (mg.Down != "" && sum == mg.upChecksum)
 }
 
-func runFromFS(db *sql.DB, efs fs.FS, root string) error {
-	ents, err := fs.ReadDir(efs, root)
-	if err != nil {
+// Migrator menerapkan/me-rollback migrasi dan mencatatnya di schema_migrations.
+// Tiap migrasi berjalan dalam transaksinya sendiri bersama baris ledger-nya,
+// dan seluruh proses dijaga advisory lock.
+type Migrator struct {
+	// DryRun: Up/Down/Redo hanya menghitung migrasi yang akan dijalankan, tanpa
+	// mengeksekusi apa pun (termasuk membuat tabel schema_migrations).
+	DryRun bool
+	// SkipManual: Up berhenti sebelum migrasi manual pertama yang tertunda;
+	// migrasi sesudahnya ikut tertunda supaya urutan versi tetap terjaga.
+	SkipManual bool
+
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, src fs.FS) (*Migrator, error) {
+	ms, err := Load(src)
+	if err != nil { return nil, err }
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Up menerapkan n migrasi tertunda berikutnya (n ≤ 0 = semua) dan
+// mengembalikan yang diterapkan. Gagal sebelum menjalankan apa pun jika ada
+// migrasi tercatat yang isinya sudah berubah atau filenya hilang.
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]Applied) (err error) {
+		done, err = m.up(ctx, conn, applied, n)
 		return err
+	})
+	return done, err
+}
+
+// Down me-rollback n migrasi terakhir yang diterapkan (n ≤ 0 dianggap 1) dan
+// mengembalikan yang di-rollback, terbaru dulu.
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 { n = 1 }
+	var done []Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]Applied) (err error) {
+		done, err = m.down(ctx, conn, applied, n)
+		return err
+	})
+	return done, err
+}
+
+// Redo me-rollback migrasi terakhir lalu menerapkannya lagi, di bawah lock yang
+// sama. nil jika belum ada migrasi yang diterapkan.
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.locked(ctx, func(conn *sql.Conn, applied map[int64]Applied) error {
+		done, err := m.down(ctx, conn, applied, 1)
+		if err != nil || len(done) == 0 { return err }
+		if err := m.apply(ctx, conn, done[0]); err != nil { return err }
+		redone = &done[0]
+		return nil
+	})
+	return redone, err
+}
+
+func (m *Migrator) up(ctx context.Context, conn *sql.Conn, applied map[int64]Applied, n int) ([]Migration, error) {
+	var done []Migration
+	for _, mg := range m.migrations {
+		if _, ok := applied[mg.Version]; ok { continue }
+		if n > 0 && len(done) == n { break }
+		if mg.Manual && m.SkipManual { break }
+		if err := m.apply(ctx, conn, mg); err != nil { return done, err }
+		done = append(done, mg)
 	}
-	var names []string
-	for _, e := range ents {
-		if e.IsDir() { continue }
-		name := e.Name()
-		if strings.HasSuffix(strings.ToLower(name), ".sql") {
-			names = append(names, name)
+	return done, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mg Migration) error {
+	if m.DryRun { return nil }
+	err := inTx(ctx, conn, mg.Up, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		mg.Version, mg.Name, mg.Checksum)
+	if err != nil { return fmt.Errorf("migrate %d_%s: %w", mg.Version, mg.Name, err) }
+	return nil
+}
+
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, applied map[int64]Applied, n int) ([]Migration, error) {
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < n; i-- {
+		mg := m.migrations[i]
+		if _, ok := applied[mg.Version]; !ok { continue }
+		if mg.Down == "" { return done, fmt.Errorf("rollback %d_%s: tidak ada file .down.sql", mg.Version, mg.Name) }
+		if !m.DryRun {
+			if err := inTx(ctx, conn, mg.Down, `DELETE FROM schema_migrations WHERE version = $1`, mg.Version); err != nil {
+				return done, fmt.Errorf("rollback %d_%s: %w", mg.Version, mg.Name, err)
+			}
 		}
+		done = append(done, mg)
 	}
-	sort.Strings(names)
-	for _, name := range names {
-		b, err := fs.ReadFile(efs, filepath.Join(root, name))
-		if err != nil { return err }
-		if _, err := db.Exec(string(b)); err != nil {
-			return fmt.Errorf("migrate %s: %w", name, err)
+	return done, nil
+}
+
+// MigrationStatus: satu versi dari file migrasi dan/atau schema_migrations.
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	Manual  bool
+	Applied *Applied // nil = belum diterapkan
+	Changed bool     // sudah diterapkan tapi checksum file berbeda
+	Missing bool     // tercatat di schema_migrations tapi filenya tidak ada
+}
+
+// Status menggabungkan file migrasi dengan isi schema_migrations, urut versi.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := appliedOn(ctx, m.db)
+	if err != nil { return nil, err }
+	var out []MigrationStatus
+	for _, mg := range m.migrations {
+		st := MigrationStatus{Version: mg.Version, Name: mg.Name, Manual: mg.Manual}
+		if a, ok := applied[mg.Version]; ok {
+			st.Applied, st.Changed = &a, !mg.matches(a.Checksum)
+			delete(applied, mg.Version)
+		}
+		out = append(out, st)
+	}
+	for _, a := range applied {
+		a := a
+		out = append(out, MigrationStatus{Version: a.Version, Name: a.Name, Applied: &a, Missing: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// verify: migrasi yang sudah diterapkan harus masih ada dengan checksum yang sama.
+func (m *Migrator) verify(applied map[int64]Applied) error {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, mg := range m.migrations { known[mg.Version] = mg }
+	for v, a := range applied {
+		mg, ok := known[v]
+		if !ok { return fmt.Errorf("migrasi %d_%s tercatat di schema_migrations tapi filenya tidak ada", v, a.Name) }
+		if !mg.matches(a.Checksum) {
+			return fmt.Errorf("migrasi %d_%s sudah diterapkan tapi isinya berubah (checksum berbeda); buat migrasi baru", v, mg.Name)
 		}
 	}
 	return nil
 }
 
-func runFromDir(db *sql.DB, dir string) error {
-	ents, err := os.ReadDir(dir)
+// locked menjalankan fn di satu koneksi yang memegang advisory lock migrasi,
+// setelah ledger dibaca dan diverifikasi. Lock level sesi, jadi ikut lepas jika
+// proses mati di tengah jalan.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int64]Applied) error) error {
+	conn, err := m.db.Conn(ctx)
 	if err != nil { return err }
-	var paths []string
-	for _, e := range ents {
-		if e.IsDir() { continue }
-		if strings.HasSuffix(strings.ToLower(e.Name()), ".sql") {
-			paths = append(paths, filepath.Join(dir, e.Name()))
-		}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrateLockKey); err != nil { return err }
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockKey)
+	if !m.DryRun {
+		if err := ensureLedger(ctx, conn); err != nil { return err }
+	}
+	applied, err := appliedOn(ctx, conn)
+	if err != nil { return err }
+	if err := m.verify(applied); err != nil { return err }
+	if !m.DryRun {
+		if err := m.restamp(ctx, conn, applied); err != nil { return err }
 	}
-	sort.Strings(paths)
-	for _, p := range paths {
-		b, err := os.ReadFile(p)
-		if err != nil { return err }
-		if _, err := db.Exec(string(b)); err != nil {
-			return fmt.Errorf("migrate %s: %w", p, err)
+	return fn(conn, applied)
+}
+
+// restamp mengganti checksum ledger lama (up saja) dengan checksum up+down,
+// supaya perubahan file down setelahnya ikut terdeteksi.
+func (m *Migrator) restamp(ctx context.Context, conn *sql.Conn, applied map[int64]Applied) error {
+	for _, mg := range m.migrations {
+		a, ok := applied[mg.Version]
+		if !ok || a.Checksum == mg.Checksum { continue }
+		if _, err := conn.ExecContext(ctx, `UPDATE schema_migrations SET checksum = $1 WHERE version = $2`, mg.Checksum, mg.Version); err != nil {
+			return err
 		}
+		a.Checksum = mg.Checksum
+		applied[mg.Version] = a
 	}
 	return nil
 }
+
+type execQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func ensureLedger(ctx context.Context, db execQueryer) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		checksum   TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`)
+	return err
+}
+
+// appliedOn membaca schema_migrations; kosong jika tabelnya belum ada.
+func appliedOn(ctx context.Context, db execQueryer) (map[int64]Applied, error) {
+	out := map[int64]Applied{}
+	rows, err := db.QueryContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`)
+	if err != nil { return nil, err }
+	var exists bool
+	for rows.Next() {
+		if err := rows.Scan(&exists); err != nil { rows.Close(); return nil, err }
+	}
+	rows.Close()
+	if !exists { return out, nil }
+
+	rows, err = db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil { return nil, err }
+		out[a.Version] = a
+	}
+	return out, rows.Err()
+}
+
+// inTx menjalankan script migrasi lalu perubahan ledger-nya dalam satu transaksi.
+func inTx(ctx context.Context, conn *sql.Conn, script, ledger string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil { return err }
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil { return err }
+	if _, err := tx.ExecContext(ctx, ledger, args...); err != nil { return err }
+	return tx.Commit()
+}
+
+var nonIdent = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create membuat pasangan file <timestamp>_<name>.up.sql / .down.sql kosong di
+// dir dan mengembalikan path-nya. Versi berupa timestamp UTC (yyyymmddhhmmss)
+// supaya tidak bentrok antar branch.
+func Create(dir, name string, now time.Time) ([]string, error) {
+	slug := strings.Trim(nonIdent.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if slug == "" { return nil, fmt.Errorf("nama migrasi %q tidak valid", name) }
+	base := now.UTC().Format("20060102150405") + "_" + slug
+	var paths []string
+	for _, kind := range []string{"up", "down"} {
+		p := filepath.Join(dir, base+"."+kind+".sql")
+		body := fmt.Sprintf("-- %s: %s\n", kind, name)
+		if kind == "down" { body += "-- Kebalikan dari " + base + ".up.sql.\n" }
+		f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil { return paths, err }
+		_, err = f.WriteString(body)
+		if cerr := f.Close(); err == nil { err = cerr }
+		if err != nil { return paths, err }
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
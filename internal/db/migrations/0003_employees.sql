@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS employees (
+  id BIGSERIAL PRIMARY KEY,
+  code       VARCHAR(50) UNIQUE,
+  name       VARCHAR(100) NOT NULL,
+  email      VARCHAR(255),
+  active     BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_employees_name ON employees (name);
+
+ALTER TABLE timesheets ADD COLUMN IF NOT EXISTS employee_id BIGINT REFERENCES employees(id);
+
+-- Backfill: satu employee per employee_name unik, lalu timesheets dipindah ke employee_id.
+-- Kolom employee_name (dan UNIQUE lamanya) dihapus setelah backfill, jadi blok ini
+-- hanya jalan sekali walaupun file ini dieksekusi ulang setiap boot.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM information_schema.columns
+             WHERE table_name = 'timesheets' AND column_name = 'employee_name') THEN
+    INSERT INTO employees (name)
+    SELECT DISTINCT t.employee_name FROM timesheets t
+    WHERE t.employee_id IS NULL
+      AND NOT EXISTS (SELECT 1 FROM employees e WHERE e.name = t.employee_name);
+
+    UPDATE timesheets t SET employee_id = e.id
+    FROM employees e
+    WHERE t.employee_id IS NULL AND e.name = t.employee_name;
+
+    ALTER TABLE timesheets DROP COLUMN employee_name;
+  END IF;
+END $$;
+
+ALTER TABLE timesheets ALTER COLUMN employee_id SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_timesheets_employee_period ON timesheets (employee_id, month, year);
@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS departments (
+  id BIGSERIAL PRIMARY KEY,
+  code       VARCHAR(30) UNIQUE,
+  name       VARCHAR(100) NOT NULL,
+  parent_id  BIGINT REFERENCES departments(id),
+  manager_id BIGINT REFERENCES employees(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (parent_id IS NULL OR parent_id <> id)
+);
+-- "IT" dan "it" dianggap departemen yang sama.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_departments_name ON departments (lower(name));
+CREATE INDEX IF NOT EXISTS idx_departments_parent ON departments (parent_id);
+
+ALTER TABLE employees  ADD COLUMN IF NOT EXISTS department_id BIGINT REFERENCES departments(id);
+ALTER TABLE timesheets ADD COLUMN IF NOT EXISTS department_id BIGINT REFERENCES departments(id);
+
+-- Backfill dari teks timesheets.department (digabung case-insensitive), lalu kolom
+-- teksnya dihapus. Variasi ejaan lain (mis. "I.T.") tetap jadi departemen terpisah
+-- dan perlu dirapikan manual lewat /departments.
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM information_schema.columns
+             WHERE table_name = 'timesheets' AND column_name = 'department') THEN
+    INSERT INTO departments (name)
+    SELECT MIN(btrim(department)) FROM timesheets
+    WHERE btrim(COALESCE(department, '')) <> ''
+    GROUP BY lower(btrim(department))
+    ON CONFLICT ((lower(name))) DO NOTHING;
+
+    UPDATE timesheets t SET department_id = d.id
+    FROM departments d
+    WHERE t.department_id IS NULL AND lower(d.name) = lower(btrim(t.department));
+
+    -- Karyawan ikut departemen dari timesheet terbarunya.
+    UPDATE employees e SET department_id = x.department_id
+    FROM (SELECT DISTINCT ON (employee_id) employee_id, department_id
+          FROM timesheets WHERE department_id IS NOT NULL
+          ORDER BY employee_id, year DESC, month DESC) x
+    WHERE e.id = x.employee_id AND e.department_id IS NULL;
+
+    ALTER TABLE timesheets DROP COLUMN department;
+  END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_timesheets_department ON timesheets (department_id);
+CREATE INDEX IF NOT EXISTS idx_employees_department ON employees (department_id);
@@ -0,0 +1,15 @@
+ALTER TABLE timesheets ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'draft'
+  CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'reopened'));
+ALTER TABLE timesheets ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+ALTER TABLE timesheets ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(100);
+
+CREATE TABLE IF NOT EXISTS timesheet_status_history (
+  id BIGSERIAL PRIMARY KEY,
+  timesheet_id BIGINT NOT NULL REFERENCES timesheets(id) ON DELETE CASCADE,
+  from_status  VARCHAR(20) NOT NULL,
+  to_status    VARCHAR(20) NOT NULL,
+  actor        VARCHAR(100) NOT NULL,
+  reason       TEXT,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_status_history_timesheet ON timesheet_status_history (timesheet_id, created_at);
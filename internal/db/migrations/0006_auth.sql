@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS users (
+  id BIGSERIAL PRIMARY KEY,
+  username      VARCHAR(100) NOT NULL,
+  password_hash VARCHAR(100) NOT NULL,
+  role          VARCHAR(20) NOT NULL DEFAULT 'employee'
+                CHECK (role IN ('employee', 'manager', 'hr_admin')),
+  employee_id   BIGINT REFERENCES employees(id) ON DELETE SET NULL,
+  active        BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_users_username ON users (lower(username));
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id BIGSERIAL PRIMARY KEY,
+  user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  token_hash     CHAR(64) NOT NULL UNIQUE,
+  family_id      VARCHAR(64) NOT NULL,
+  expires_at     TIMESTAMPTZ NOT NULL,
+  revoked_at     TIMESTAMPTZ,
+  replaced_by_id BIGINT REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens (user_id);
@@ -0,0 +1,25 @@
+-- Audit trail append-only untuk timesheet & entries. Sengaja tanpa FK ke
+-- timesheets supaya riwayat tetap ada setelah timesheet dihapus.
+CREATE TABLE IF NOT EXISTS audit_events (
+  id BIGSERIAL PRIMARY KEY,
+  timesheet_id BIGINT NOT NULL,
+  entity       VARCHAR(20) NOT NULL,
+  entity_id    BIGINT NOT NULL,
+  action       VARCHAR(20) NOT NULL,
+  actor        VARCHAR(100) NOT NULL,
+  request_id   VARCHAR(100),
+  before       JSONB,
+  after        JSONB,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_timesheet ON audit_events (timesheet_id, id);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit_events;
+CREATE TRIGGER trg_audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+  FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS overtime_policies (
+  id BIGSERIAL PRIMARY KEY,
+  name                   VARCHAR(100) NOT NULL,
+  daily_threshold_hours  NUMERIC(5,2) NOT NULL DEFAULT 8,
+  weekly_threshold_hours NUMERIC(5,2) NOT NULL DEFAULT 40,
+  overtime_multiplier    NUMERIC(4,2) NOT NULL DEFAULT 1.5,
+  weekend_multiplier     NUMERIC(4,2) NOT NULL DEFAULT 2,
+  holiday_multiplier     NUMERIC(4,2) NOT NULL DEFAULT 2,
+  break_minutes          INT NOT NULL DEFAULT 0,
+  break_after_hours      NUMERIC(5,2) NOT NULL DEFAULT 0,
+  is_default             BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_overtime_policies_name ON overtime_policies (lower(name));
+-- Maksimal satu policy default.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_overtime_policies_default ON overtime_policies (is_default) WHERE is_default;
+
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS overtime_policy_id BIGINT REFERENCES overtime_policies(id);
+
+-- Rincian perhitungan lembur (domain.OvertimeBreakdown); NULL = diinput manual.
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS overtime_breakdown JSONB;
@@ -0,0 +1,9 @@
+-- Kalender hari libur: libur nasional, cuti bersama, dan libur perusahaan.
+CREATE TABLE IF NOT EXISTS holidays (
+  id BIGSERIAL PRIMARY KEY,
+  holiday_date DATE NOT NULL UNIQUE,
+  name         VARCHAR(200) NOT NULL,
+  kind         VARCHAR(20) NOT NULL DEFAULT 'national'
+    CHECK (kind IN ('national', 'cuti_bersama', 'company')),
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,7 @@
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS leave_request_id;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS leave_type_id;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS entry_type;
+
+DROP TABLE IF EXISTS leave_balances;
+DROP TABLE IF EXISTS leave_requests;
+DROP TABLE IF EXISTS leave_types;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS leave_types (
+  id BIGSERIAL PRIMARY KEY,
+  code         VARCHAR(30) NOT NULL UNIQUE,
+  name         VARCHAR(100) NOT NULL,
+  paid         BOOLEAN NOT NULL DEFAULT TRUE,
+  -- Jatah hari per tahun; 0 = tidak memotong saldo (mis. sakit dengan surat dokter).
+  annual_quota NUMERIC(5,1) NOT NULL DEFAULT 0,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+INSERT INTO leave_types (code, name, paid, annual_quota) VALUES
+  ('ANNUAL', 'Cuti Tahunan', TRUE, 12),
+  ('SICK', 'Sakit', TRUE, 0),
+  ('CUTI_BERSAMA', 'Cuti Bersama', TRUE, 0),
+  ('UNPAID', 'Cuti di Luar Tanggungan', FALSE, 0)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS leave_requests (
+  id BIGSERIAL PRIMARY KEY,
+  employee_id   BIGINT NOT NULL REFERENCES employees(id),
+  leave_type_id BIGINT NOT NULL REFERENCES leave_types(id),
+  start_date    DATE NOT NULL,
+  end_date      DATE NOT NULL,
+  days          NUMERIC(5,1) NOT NULL,
+  reason        TEXT,
+  status        VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+  decided_by    VARCHAR(100),
+  decided_at    TIMESTAMPTZ,
+  decision_note TEXT,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (end_date >= start_date)
+);
+CREATE INDEX IF NOT EXISTS idx_leave_requests_employee ON leave_requests (employee_id, start_date);
+
+CREATE TABLE IF NOT EXISTS leave_balances (
+  employee_id   BIGINT NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+  leave_type_id BIGINT NOT NULL REFERENCES leave_types(id) ON DELETE CASCADE,
+  year          INT NOT NULL,
+  entitled      NUMERIC(5,1) NOT NULL,
+  used          NUMERIC(5,1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (employee_id, leave_type_id, year)
+);
+
+-- Entry bertipe: work (default), leave (dari cuti), absent (mangkir/alpha).
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS entry_type VARCHAR(20) NOT NULL DEFAULT 'work'
+  CHECK (entry_type IN ('work', 'leave', 'absent'));
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS leave_type_id BIGINT REFERENCES leave_types(id);
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS leave_request_id BIGINT REFERENCES leave_requests(id) ON DELETE SET NULL;
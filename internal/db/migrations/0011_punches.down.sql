@@ -0,0 +1,4 @@
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS flag_reason;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS flagged;
+
+DROP TABLE IF EXISTS punch_events;
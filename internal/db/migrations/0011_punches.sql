@@ -0,0 +1,20 @@
+-- Punch in/out mentah dari karyawan. Waktu selalu waktu server; geo & device opsional dari client.
+CREATE TABLE IF NOT EXISTS punch_events (
+  id BIGSERIAL PRIMARY KEY,
+  employee_id BIGINT NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+  kind        VARCHAR(3) NOT NULL CHECK (kind IN ('in', 'out')),
+  punched_at  TIMESTAMPTZ NOT NULL,
+  latitude    DOUBLE PRECISION,
+  longitude   DOUBLE PRECISION,
+  device      VARCHAR(200),
+  ip          VARCHAR(64),
+  -- TRUE untuk punch out yang dibuat aturan auto-close (karyawan lupa punch out).
+  auto        BOOLEAN NOT NULL DEFAULT FALSE,
+  entry_id    BIGINT REFERENCES timesheet_entries(id) ON DELETE SET NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_punch_events_employee ON punch_events (employee_id, punched_at DESC, id DESC);
+
+-- Entry yang perlu dicek ulang manusia (mis. ditutup otomatis).
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS flag_reason TEXT;
@@ -0,0 +1,5 @@
+ALTER TABLE overtime_policies DROP COLUMN IF EXISTS night_end;
+ALTER TABLE overtime_policies DROP COLUMN IF EXISTS night_start;
+
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS night_hours;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS ends_next_day;
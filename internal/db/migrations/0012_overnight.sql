@@ -0,0 +1,8 @@
+-- Shift lintas tengah malam: end_time ≤ start_time berarti selesai keesokan harinya.
+-- Seluruh jam shift tetap dihitung ke work_date (tanggal mulai).
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS ends_next_day BOOLEAN NOT NULL DEFAULT FALSE;
+-- Jam yang jatuh di window malam policy, untuk tunjangan shift malam.
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS night_hours NUMERIC(5,2);
+
+ALTER TABLE overtime_policies ADD COLUMN IF NOT EXISTS night_start TIME NOT NULL DEFAULT '22:00';
+ALTER TABLE overtime_policies ADD COLUMN IF NOT EXISTS night_end   TIME NOT NULL DEFAULT '06:00';
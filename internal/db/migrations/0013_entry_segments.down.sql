@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_entries_timesheet_date;
+DROP TABLE IF EXISTS entry_segments;
@@ -0,0 +1,26 @@
+-- Satu hari bisa terdiri dari beberapa segmen (kerja pagi, istirahat, kerja sore,
+-- panggilan malam). Total jam entry diturunkan dari segmen-segmennya.
+CREATE TABLE IF NOT EXISTS entry_segments (
+  id BIGSERIAL PRIMARY KEY,
+  entry_id        BIGINT NOT NULL REFERENCES timesheet_entries(id) ON DELETE CASCADE,
+  segment_type    VARCHAR(20) NOT NULL CHECK (segment_type IN ('work', 'break', 'travel', 'on_call')),
+  start_time      TIME NOT NULL,
+  end_time        TIME NOT NULL,
+  starts_next_day BOOLEAN NOT NULL DEFAULT FALSE,
+  remarks         TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_entry_segments_entry ON entry_segments (entry_id);
+
+-- Satu entry per tanggal per timesheet. Data lama yang masih dobel harus
+-- digabung manual dulu; sampai itu, indeks dilewati dan service yang menjaga.
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM timesheet_entries GROUP BY timesheet_id, work_date HAVING COUNT(*) > 1
+  ) THEN
+    CREATE UNIQUE INDEX IF NOT EXISTS uq_entries_timesheet_date ON timesheet_entries (timesheet_id, work_date);
+  ELSE
+    RAISE WARNING 'timesheet_entries masih punya tanggal dobel; uq_entries_timesheet_date belum dibuat';
+  END IF;
+END $$;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS entry_allocations;
+
+ALTER TABLE entry_segments DROP COLUMN IF EXISTS task_id;
+ALTER TABLE entry_segments DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS projects;
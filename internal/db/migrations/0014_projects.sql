@@ -0,0 +1,35 @@
+-- Project & task untuk alokasi jam kerja, supaya rekap per project tidak lagi
+-- diambil dari remarks.
+CREATE TABLE IF NOT EXISTS projects (
+  id BIGSERIAL PRIMARY KEY,
+  code        VARCHAR(30)  NOT NULL UNIQUE,
+  name        VARCHAR(150) NOT NULL,
+  description TEXT,
+  active      BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  name       VARCHAR(150) NOT NULL,
+  active     BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (project_id, name)
+);
+
+-- Segmen dialokasikan utuh ke satu project/task.
+ALTER TABLE entry_segments ADD COLUMN IF NOT EXISTS project_id BIGINT REFERENCES projects(id);
+ALTER TABLE entry_segments ADD COLUMN IF NOT EXISTS task_id    BIGINT REFERENCES tasks(id);
+
+-- Jam entry per project/task. Untuk entry bersegmen baris-barisnya diturunkan
+-- dari segmen, jadi rekap cukup membaca tabel ini.
+CREATE TABLE IF NOT EXISTS entry_allocations (
+  id BIGSERIAL PRIMARY KEY,
+  entry_id   BIGINT NOT NULL REFERENCES timesheet_entries(id) ON DELETE CASCADE,
+  project_id BIGINT NOT NULL REFERENCES projects(id),
+  task_id    BIGINT REFERENCES tasks(id),
+  hours      NUMERIC(5,2) NOT NULL CHECK (hours > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_entry_allocations_entry   ON entry_allocations (entry_id);
+CREATE INDEX IF NOT EXISTS idx_entry_allocations_project ON entry_allocations (project_id);
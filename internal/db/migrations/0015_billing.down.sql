@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS rate_cards;
+
+DROP INDEX IF EXISTS idx_entries_client;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS client_id;
+ALTER TABLE timesheet_entries DROP COLUMN IF EXISTS billable;
+
+ALTER TABLE projects DROP COLUMN IF EXISTS client_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS position;
+
+DROP TABLE IF EXISTS clients;
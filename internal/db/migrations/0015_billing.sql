@@ -0,0 +1,33 @@
+-- Klien yang ditagih dari timesheet.
+CREATE TABLE IF NOT EXISTS clients (
+  id BIGSERIAL PRIMARY KEY,
+  code       VARCHAR(30)  NOT NULL UNIQUE,
+  name       VARCHAR(150) NOT NULL,
+  currency   CHAR(3) NOT NULL DEFAULT 'IDR',
+  active     BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Jabatan karyawan, dipakai untuk memilih tarif per role.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS position VARCHAR(100);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS client_id BIGINT REFERENCES clients(id);
+
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS billable  BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE timesheet_entries ADD COLUMN IF NOT EXISTS client_id BIGINT REFERENCES clients(id);
+CREATE INDEX IF NOT EXISTS idx_entries_client ON timesheet_entries (client_id) WHERE billable;
+
+-- Tarif per jam. Kolom kosong = berlaku untuk semua; tarif yang paling spesifik
+-- (project > jabatan > klien) dan berlaku di tanggal kerja yang dipakai.
+CREATE TABLE IF NOT EXISTS rate_cards (
+  id BIGSERIAL PRIMARY KEY,
+  client_id      BIGINT REFERENCES clients(id) ON DELETE CASCADE,
+  project_id     BIGINT REFERENCES projects(id) ON DELETE CASCADE,
+  position       VARCHAR(100),
+  hourly_rate    NUMERIC(14,2) NOT NULL CHECK (hourly_rate >= 0),
+  effective_from DATE NOT NULL,
+  effective_to   DATE,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (effective_to IS NULL OR effective_to >= effective_from)
+);
+CREATE INDEX IF NOT EXISTS idx_rate_cards_client ON rate_cards (client_id);
@@ -0,0 +1,2 @@
+-- Indeks yang sudah dibuat 0013 ikut terhapus; up berikutnya membuatnya lagi.
+DROP INDEX IF EXISTS uq_entries_timesheet_date;
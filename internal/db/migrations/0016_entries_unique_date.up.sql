@@ -0,0 +1,21 @@
+-- migrate:manual
+-- Susulan 0013: di sana uq_entries_timesheet_date dilewati jika masih ada tanggal
+-- dobel, tapi migrasinya tetap tercatat di schema_migrations sehingga tidak pernah
+-- dicoba lagi. Di sini indeks wajib ada, jadi migrasi ini gagal selama data dobel
+-- belum digabung. Karena itu manual: API tidak menjalankannya saat start (hanya
+-- mencatat di log bahwa migrasi ini tertunda); operator menggabungkan data lalu
+-- menjalankan cmd/migrate up.
+DO $$
+DECLARE
+  dup BIGINT;
+BEGIN
+  SELECT COUNT(*) INTO dup FROM (
+    SELECT 1 FROM timesheet_entries GROUP BY timesheet_id, work_date HAVING COUNT(*) > 1
+  ) d;
+  IF dup > 0 THEN
+    RAISE EXCEPTION 'timesheet_entries masih punya % tanggal dobel; uq_entries_timesheet_date belum bisa dibuat', dup
+      USING HINT = 'Cari dengan: SELECT timesheet_id, work_date, array_agg(id ORDER BY id) FROM timesheet_entries '
+                || 'GROUP BY 1, 2 HAVING COUNT(*) > 1; gabungkan isinya ke satu entry, hapus sisanya, lalu jalankan cmd/migrate up.';
+  END IF;
+END $$;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_entries_timesheet_date ON timesheet_entries (timesheet_id, work_date);
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityTimesheet = "timesheet"
+	AuditEntityEntry     = "entry"
+
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditStatus = "status"
+)
+
+// AuditEvent adalah satu baris append-only di audit_events. Before/After hanya
+// memuat field yang berubah; create hanya punya After, delete hanya Before.
+type AuditEvent struct {
+	ID          int64           `json:"id"`
+	TimesheetID int64           `json:"timesheet_id"`
+	Entity      string          `json:"entity"`
+	EntityID    int64           `json:"entity_id"`
+	Action      string          `json:"action"`
+	Actor       string          `json:"actor"`
+	RequestID   string          `json:"request_id,omitempty"`
+	Before      json.RawMessage `json:"before,omitempty"`
+	After       json.RawMessage `json:"after,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+type Client struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Currency  string    `json:"currency"` // ISO 4217, default IDR
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RateCard adalah tarif per jam. ClientID/ProjectID/Position kosong berarti
+// berlaku untuk semua; EffectiveTo kosong berarti masih berlaku.
+type RateCard struct {
+	ID            int64      `json:"id"`
+	ClientID      *int64     `json:"client_id,omitempty"`
+	ProjectID     *int64     `json:"project_id,omitempty"`
+	Position      string     `json:"position,omitempty"`
+	HourlyRate    float64    `json:"hourly_rate"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Covers: tarif berlaku di tanggal d.
+func (r RateCard) Covers(d time.Time) bool {
+	return !d.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || !d.After(*r.EffectiveTo))
+}
+
+// BillableItem adalah jam billable satu entry untuk satu project; ProjectID nil
+// untuk sisa jam entry yang tidak dialokasikan ke project.
+type BillableItem struct {
+	EntryID      int64
+	WorkDate     time.Time
+	EmployeeID   int64
+	EmployeeName string
+	Position     string
+	ProjectID    *int64
+	ProjectCode  string
+	Hours        float64
+}
+
+// InvoiceLine: jam & nilai per karyawan, project, dan tarif. RateCardID nil
+// berarti belum ada tarif yang cocok (amount 0).
+type InvoiceLine struct {
+	EmployeeID   int64   `json:"employee_id"`
+	EmployeeName string  `json:"employee_name"`
+	Position     string  `json:"position,omitempty"`
+	ProjectID    *int64  `json:"project_id,omitempty"`
+	ProjectCode  string  `json:"project_code,omitempty"`
+	RateCardID   *int64  `json:"rate_card_id,omitempty"`
+	HourlyRate   float64 `json:"hourly_rate"`
+	Hours        float64 `json:"hours"`
+	Amount       float64 `json:"amount"`
+}
+
+type InvoicePreview struct {
+	Client       Client        `json:"client"`
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	Lines        []InvoiceLine `json:"lines"`
+	TotalHours   float64       `json:"total_hours"`
+	UnratedHours float64       `json:"unrated_hours"` // jam tanpa tarif, perlu rate card baru
+	TotalAmount  float64       `json:"total_amount"`
+}
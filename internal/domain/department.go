@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type Department struct {
+	ID               int64     `json:"id"`
+	Code             *string   `json:"code,omitempty"`
+	Name             string    `json:"name"`
+	ParentID         *int64    `json:"parent_id,omitempty"`
+	ManagerID        *int64    `json:"manager_id,omitempty"` // employees.id
+	OvertimePolicyID *int64    `json:"overtime_policy_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+type Employee struct {
+	ID           int64     `json:"id"`
+	Code         *string   `json:"code,omitempty"` // NIK / nomor induk karyawan
+	Name         string    `json:"name"`
+	Email        *string   `json:"email,omitempty"`
+	Position     *string   `json:"position,omitempty"` // jabatan, untuk tarif per role
+	DepartmentID *int64    `json:"department_id,omitempty"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type HolidayKind string
+
+const (
+	HolidayNational    HolidayKind = "national"
+	HolidayCutiBersama HolidayKind = "cuti_bersama"
+	HolidayCompany     HolidayKind = "company"
+)
+
+func (k HolidayKind) Valid() bool {
+	return k == HolidayNational || k == HolidayCutiBersama || k == HolidayCompany
+}
+
+// Holiday adalah satu tanggal libur; satu tanggal hanya boleh punya satu baris.
+type Holiday struct {
+	ID        int64       `json:"id"`
+	Date      time.Time   `json:"date"`
+	Name      string      `json:"name"`
+	Kind      HolidayKind `json:"kind"`
+	CreatedAt time.Time   `json:"created_at"`
+}
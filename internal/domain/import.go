@@ -0,0 +1,38 @@
+package domain
+
+// ImportRow adalah satu baris file import yang sudah dipetakan lewat profile.
+// Karyawan dicari dari EmployeeID, lalu EmployeeCode, lalu EmployeeName.
+type ImportRow struct {
+	Line         int // nomor baris di file (header = 1)
+	EmployeeID   int64
+	EmployeeCode string
+	EmployeeName string
+	Entry        TimesheetEntry
+	Invalid      *ImportError // kesalahan parsing; baris ini tidak divalidasi lagi
+}
+
+type ImportError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult: ringkasan import. Pada dry run, Imported selalu 0 dan
+// NewTimesheets adalah jumlah timesheet yang akan dibuat.
+type ImportResult struct {
+	DryRun        bool          `json:"dry_run"`
+	Rows          int           `json:"rows"`
+	Valid         int           `json:"valid"`
+	Imported      int           `json:"imported"`
+	NewTimesheets int           `json:"new_timesheets"`
+	Errors        []ImportError `json:"errors"`
+}
+
+// ImportItem: satu entry hasil import yang siap ditulis. Timesheet dengan ID 0
+// dibuat lebih dulu; pointer-nya dipakai bersama oleh entry di bulan yang sama.
+type ImportItem struct {
+	Timesheet      *Timesheet
+	TimesheetAudit *AuditEvent // audit create timesheet, hanya untuk Timesheet baru
+	Entry          *TimesheetEntry
+	Audit          *AuditEvent
+}
@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// EntryType membedakan hari kerja, cuti, dan ketidakhadiran di timesheet.
+type EntryType string
+
+const (
+	EntryWork   EntryType = "work"
+	EntryLeave  EntryType = "leave"
+	EntryAbsent EntryType = "absent"
+)
+
+func (t EntryType) Valid() bool { return t == EntryWork || t == EntryLeave || t == EntryAbsent }
+
+type LeaveType struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Paid        bool      `json:"paid"`
+	AnnualQuota float64   `json:"annual_quota"` // 0 = tidak memotong saldo
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LeaveStatus string
+
+const (
+	LeavePending   LeaveStatus = "pending"
+	LeaveApproved  LeaveStatus = "approved"
+	LeaveRejected  LeaveStatus = "rejected"
+	LeaveCancelled LeaveStatus = "cancelled"
+)
+
+type LeaveRequest struct {
+	ID           int64       `json:"id"`
+	EmployeeID   int64       `json:"employee_id"`
+	EmployeeName string      `json:"employee_name"` // diisi dari tabel employees
+	LeaveTypeID  int64       `json:"leave_type_id"`
+	LeaveType    string      `json:"leave_type"` // nama, diisi dari tabel leave_types
+	StartDate    time.Time   `json:"start_date"`
+	EndDate      time.Time   `json:"end_date"`
+	Days         float64     `json:"days"` // hari kerja di rentang tanggal
+	Reason       string      `json:"reason,omitempty"`
+	Status       LeaveStatus `json:"status"`
+	DecidedBy    *string     `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time  `json:"decided_at,omitempty"`
+	DecisionNote string      `json:"decision_note,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// LeaveBalance adalah saldo satu jenis cuti per karyawan per tahun.
+type LeaveBalance struct {
+	EmployeeID  int64   `json:"employee_id"`
+	LeaveTypeID int64   `json:"leave_type_id"`
+	LeaveType   string  `json:"leave_type"`
+	Year        int     `json:"year"`
+	Entitled    float64 `json:"entitled"`
+	Used        float64 `json:"used"`
+	Remaining   float64 `json:"remaining"`
+}
+
+// LeaveDay adalah satu hari cuti yang akan ditulis ke timesheet bulanannya.
+// Timesheet dengan ID 0 dibuat dalam transaksi yang sama dengan approve-nya;
+// pointer-nya dipakai bersama oleh hari-hari di bulan yang sama.
+type LeaveDay struct {
+	Date           time.Time
+	Timesheet      *Timesheet
+	TimesheetAudit *AuditEvent // audit create timesheet, hanya untuk Timesheet baru
+}
+
+// LeaveDecision adalah approve/reject/cancel satu pengajuan cuti.
+// Days hanya diisi untuk approve.
+type LeaveDecision struct {
+	RequestID int64
+	From      LeaveStatus
+	To        LeaveStatus
+	Actor     string
+	Note      string
+	Days      []LeaveDay
+	Audit     *AuditEvent // template audit untuk entry yang dibuat/diubah
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// OvertimePolicy adalah aturan lembur bernama yang dipasang per departemen
+// (sub-departemen mewarisi policy parent-nya jika tidak punya sendiri).
+type OvertimePolicy struct {
+	ID                   int64     `json:"id"`
+	Name                 string    `json:"name"`
+	DailyThresholdHours  float64   `json:"daily_threshold_hours"`  // 0 = tanpa batas harian
+	WeeklyThresholdHours float64   `json:"weekly_threshold_hours"` // 0 = tanpa batas mingguan
+	OvertimeMultiplier   float64   `json:"overtime_multiplier"`    // lembur hari kerja
+	WeekendMultiplier    float64   `json:"weekend_multiplier"`
+	HolidayMultiplier    float64   `json:"holiday_multiplier"`
+	BreakMinutes         int       `json:"break_minutes"` // dipotong jika durasi ≥ BreakAfterHours
+	BreakAfterHours      float64   `json:"break_after_hours"`
+	NightStart           string    `json:"night_start"` // "22:00"; window jam malam untuk night_hours
+	NightEnd             string    `json:"night_end"`   // "06:00"
+	IsDefault            bool      `json:"is_default"`  // dipakai jika departemen tidak punya policy
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+const (
+	DayTypeWorkday = "workday"
+	DayTypeWeekend = "weekend"
+	DayTypeHoliday = "holiday"
+)
+
+// OvertimeBreakdown adalah rincian perhitungan lembur satu entry.
+// Di weekend/hari libur semua jam kerja dihitung lembur (RestDayHours).
+type OvertimeBreakdown struct {
+	PolicyID       int64   `json:"policy_id"`
+	PolicyName     string  `json:"policy_name"`
+	DayType        string  `json:"day_type"`
+	GrossHours     float64 `json:"gross_hours"`
+	BreakHours     float64 `json:"break_hours"`
+	RegularHours   float64 `json:"regular_hours"`
+	DailyOvertime  float64 `json:"daily_overtime"`
+	WeeklyOvertime float64 `json:"weekly_overtime"`
+	RestDayHours   float64 `json:"rest_day_hours"`
+	OvertimeHours  float64 `json:"overtime_hours"`
+	Multiplier     float64 `json:"multiplier"`
+	WeightedHours  float64 `json:"weighted_hours"` // overtime_hours × multiplier
+}
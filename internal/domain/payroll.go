@@ -0,0 +1,24 @@
+package domain
+
+// PayrollRow adalah rekap satu timesheet bulanan untuk export payroll.
+// OvertimeUnits = Σ jam lembur × multiplier; upah lembur = units × upah sejam
+// (1/173 upah sebulan), dihitung di sistem payroll.
+type PayrollRow struct {
+	EmployeeID           int64              `json:"employee_id"`
+	EmployeeCode         string             `json:"employee_code"`
+	EmployeeName         string             `json:"employee_name"`
+	Department           string             `json:"department"`
+	Year                 int                `json:"year"`
+	Month                int                `json:"month"`
+	Status               TimesheetStatus    `json:"status"`
+	WorkingDays          int                `json:"working_days"`
+	WorkedDays           int64              `json:"worked_days"`
+	LeaveDays            int64              `json:"leave_days"`
+	AbsentDays           int64              `json:"absent_days"`
+	TotalHours           float64            `json:"total_hours"`
+	OvertimeHours        float64            `json:"overtime_hours"`
+	WorkdayOvertimeHours float64            `json:"workday_ot_hours"`
+	RestDayHours         float64            `json:"rest_day_hours"`
+	OvertimeByMultiplier map[string]float64 `json:"overtime_by_multiplier"` // "1.5" → jam
+	OvertimeUnits        float64            `json:"overtime_units"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type Project struct {
+	ID          int64     `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ClientID    *int64    `json:"client_id,omitempty"` // klien default untuk entry billable
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Task struct {
+	ID        int64     `json:"id"`
+	ProjectID int64     `json:"project_id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EntryAllocation adalah jam entry yang dibebankan ke project (dan opsional task-nya).
+type EntryAllocation struct {
+	ID        int64   `json:"id"`
+	EntryID   int64   `json:"entry_id"`
+	ProjectID int64   `json:"project_id"`
+	TaskID    *int64  `json:"task_id,omitempty"`
+	Hours     float64 `json:"hours"`
+}
+
+// ProjectHours adalah satu baris rekap jam project per karyawan per bulan.
+type ProjectHours struct {
+	EmployeeID   int64   `json:"employee_id"`
+	EmployeeName string  `json:"employee_name"`
+	Year         int     `json:"year"`
+	Month        int     `json:"month"`
+	Hours        float64 `json:"hours"`
+}
+
+type ProjectHoursReport struct {
+	Project    Project        `json:"project"`
+	Rows       []ProjectHours `json:"rows"`
+	TotalHours float64        `json:"total_hours"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type PunchKind string
+
+const (
+	PunchIn  PunchKind = "in"
+	PunchOut PunchKind = "out"
+)
+
+// Punch adalah satu event punch in/out mentah. PunchedAt selalu waktu server.
+type Punch struct {
+	ID         int64     `json:"id"`
+	EmployeeID int64     `json:"employee_id"`
+	Kind       PunchKind `json:"kind"`
+	PunchedAt  time.Time `json:"punched_at"`
+	Latitude   *float64  `json:"latitude,omitempty"`
+	Longitude  *float64  `json:"longitude,omitempty"`
+	Device     string    `json:"device,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	Auto       bool      `json:"auto"` // punch out hasil auto-close
+	EntryID    *int64    `json:"entry_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// SegmentType: jenis segmen dalam satu hari. Semua kecuali break dihitung jam kerja.
+type SegmentType string
+
+const (
+	SegmentWork   SegmentType = "work"
+	SegmentBreak  SegmentType = "break"
+	SegmentTravel SegmentType = "travel"
+	SegmentOnCall SegmentType = "on_call"
+)
+
+func (t SegmentType) Valid() bool {
+	return t == SegmentWork || t == SegmentBreak || t == SegmentTravel || t == SegmentOnCall
+}
+
+// EntrySegment adalah satu rentang waktu di dalam entry. EndTime ≤ StartTime
+// berarti selesai keesokan harinya; StartsNextDay untuk segmen yang mulai
+// setelah tengah malam (mis. panggilan 01:00–03:00 milik shift hari sebelumnya).
+type EntrySegment struct {
+	ID            int64       `json:"id"`
+	EntryID       int64       `json:"entry_id"`
+	Type          SegmentType `json:"type"`
+	StartTime     time.Time   `json:"start_time"`
+	EndTime       time.Time   `json:"end_time"`
+	StartsNextDay bool        `json:"starts_next_day"`
+	Remarks       string      `json:"remarks,omitempty"`
+	ProjectID     *int64      `json:"project_id,omitempty"`
+	TaskID        *int64      `json:"task_id,omitempty"`
+}
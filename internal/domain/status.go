@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// TimesheetStatus: draft → submitted → approved/rejected → reopened → submitted ...
+type TimesheetStatus string
+
+const (
+	StatusDraft     TimesheetStatus = "draft"
+	StatusSubmitted TimesheetStatus = "submitted"
+	StatusApproved  TimesheetStatus = "approved"
+	StatusRejected  TimesheetStatus = "rejected"
+	StatusReopened  TimesheetStatus = "reopened"
+)
+
+var statusTransitions = map[TimesheetStatus][]TimesheetStatus{
+	StatusDraft:     {StatusSubmitted},
+	StatusSubmitted: {StatusApproved, StatusRejected},
+	StatusApproved:  {StatusReopened},
+	StatusRejected:  {StatusSubmitted, StatusReopened},
+	StatusReopened:  {StatusSubmitted},
+}
+
+// CanTransitionTo melaporkan apakah perpindahan status s → to diizinkan.
+func (s TimesheetStatus) CanTransitionTo(to TimesheetStatus) bool {
+	for _, v := range statusTransitions[s] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Editable: timesheet & entries hanya boleh diubah selama belum submitted/approved.
+func (s TimesheetStatus) Editable() bool {
+	return s != StatusSubmitted && s != StatusApproved
+}
+
+// StatusChange adalah satu baris riwayat perpindahan status timesheet.
+type StatusChange struct {
+	ID          int64           `json:"id"`
+	TimesheetID int64           `json:"timesheet_id"`
+	FromStatus  TimesheetStatus `json:"from_status"`
+	ToStatus    TimesheetStatus `json:"to_status"`
+	Actor       string          `json:"actor"`
+	Reason      string          `json:"reason,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+var (
+	ErrLocked            = errors.New("timesheet locked")
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
@@ -7,11 +7,16 @@ import (
 
 type Timesheet struct {
 	ID               int64            `json:"id"`
-	EmployeeName     string           `json:"employee_name"`
-	Department       string           `json:"department"`
+	EmployeeID       int64            `json:"employee_id"`
+	EmployeeName     string           `json:"employee_name"` // diisi dari tabel employees
+	DepartmentID     *int64           `json:"department_id,omitempty"`
+	Department       string           `json:"department"` // nama, diisi dari tabel departments
 	Month            int              `json:"month"`
 	Year             int              `json:"year"`
 	TotalWorkingDays *int             `json:"total_working_days,omitempty"`
+	Status           TimesheetStatus  `json:"status"`
+	StatusChangedAt  *time.Time       `json:"status_changed_at,omitempty"`
+	StatusChangedBy  *string          `json:"status_changed_by,omitempty"`
 	CreatedAt        time.Time        `json:"created_at"`
 	Entries          []TimesheetEntry `json:"entries,omitempty"`
 }
@@ -25,11 +30,49 @@ type TimesheetEntry struct {
 	TotalHours    *float64   `json:"total_hours,omitempty"`
 	OvertimeHours *float64   `json:"overtime_hours,omitempty"`
 	Remarks       string     `json:"remarks,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
+	Type          EntryType  `json:"entry_type"`
+	LeaveTypeID   *int64     `json:"leave_type_id,omitempty"`
+	// LeaveRequestID terisi jika entry dibuat otomatis dari cuti yang disetujui.
+	LeaveRequestID *int64 `json:"leave_request_id,omitempty"`
+	// Overtime diisi engine lembur; nil jika overtime_hours diinput manual.
+	Overtime *OvertimeBreakdown `json:"overtime,omitempty"`
+	Holiday  string             `json:"holiday,omitempty"` // nama hari libur, diisi dari tabel holidays
+	// EndsNextDay: shift melewati tengah malam (end_time ≤ start_time); jamnya tetap
+	// dihitung ke WorkDate.
+	EndsNextDay bool     `json:"ends_next_day"`
+	NightHours  *float64 `json:"night_hours,omitempty"` // jam di window malam policy
+	// Segments, jika ada, menjadi sumber start/end/total_hours entry.
+	Segments []EntrySegment `json:"segments,omitempty"`
+	// Allocations: jam per project/task; untuk entry bersegmen diturunkan dari segmen.
+	Allocations []EntryAllocation `json:"allocations,omitempty"`
+	// Billable: jam entry ditagihkan ke ClientID (wajib diisi jika billable).
+	Billable bool   `json:"billable"`
+	ClientID *int64 `json:"client_id,omitempty"`
+	// Flagged menandai entry yang perlu dicek ulang (mis. punch out ditutup otomatis).
+	Flagged    bool      `json:"flagged"`
+	FlagReason string    `json:"flag_reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TimesheetStats adalah ringkasan satu timesheet. AbsentDays = entry absent,
+// atau hari kerja sampai hari ini yang tidak terisi kerja/cuti jika
+// total_working_days diketahui (diambil yang terbesar); hari kerja yang belum
+// lewat tidak dihitung.
+type TimesheetStats struct {
+	DaysFilled    int64   `json:"days_filled"`
+	WorkedDays    int64   `json:"worked_days"`
+	LeaveDays     int64   `json:"leave_days"`
+	AbsentDays    int64   `json:"absent_days"`
+	TotalHours    float64 `json:"total_hours"`
+	OvertimeHours float64 `json:"overtime_hours"`
+	NightHours    float64 `json:"night_hours"`
 }
 
 var (
 	ErrNotFound     = errors.New("not found")
 	ErrInvalidInput = errors.New("invalid input")
 	ErrDuplicate    = errors.New("duplicate")
+	ErrInUse        = errors.New("in use")
+	// ErrTimeout: query melewati batas waktunya (lihat DB_*_TIMEOUT).
+	ErrTimeout = errors.New("timeout")
 )
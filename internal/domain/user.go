@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleEmployee Role = "employee"
+	RoleManager  Role = "manager"
+	RoleHRAdmin  Role = "hr_admin"
+)
+
+func (r Role) Valid() bool {
+	return r == RoleEmployee || r == RoleManager || r == RoleHRAdmin
+}
+
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `json:"role"`
+	EmployeeID   *int64    `json:"employee_id,omitempty"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Principal adalah identitas pemanggil yang sudah terautentikasi.
+type Principal struct {
+	UserID     int64  `json:"user_id"`
+	Username   string `json:"username"`
+	Role       Role   `json:"role"`
+	EmployeeID *int64 `json:"employee_id,omitempty"`
+}
+
+// RefreshToken disimpan sebagai hash; token mentahnya hanya dikirim sekali ke client.
+// Satu FamilyID = satu sesi login; setiap refresh merotasi token di family yang sama.
+type RefreshToken struct {
+	ID           int64
+	UserID       int64
+	TokenHash    string
+	FamilyID     string
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *int64
+	CreatedAt    time.Time
+}
+
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int64     `json:"expires_in"` // detik
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+)
@@ -0,0 +1,241 @@
+// Package importer membaca file CSV/XLSX (mis. ekspor mesin absensi atau
+// spreadsheet lama) menjadi baris entry timesheet lewat profile pemetaan kolom.
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"timesheet-api/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// FormatOf menebak format dari ekstensi nama file ("" jika tidak dikenal).
+func FormatOf(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	}
+	return ""
+}
+
+// Profile memetakan judul kolom di file (case-insensitive) ke field entry.
+// Kolom yang kosong memakai nama field-nya sendiri, mis. "date" atau "start_time".
+// Minimal salah satu kolom karyawan dan kolom date harus ada di file.
+type Profile struct {
+	EmployeeID   string `json:"employee_id"`
+	EmployeeCode string `json:"employee_code"`
+	EmployeeName string `json:"employee_name"`
+	Date         string `json:"date"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	TotalHours   string `json:"total_hours"`
+	EntryType    string `json:"entry_type"`
+	LeaveTypeID  string `json:"leave_type_id"`
+	Remarks      string `json:"remarks"`
+
+	DateFormat string `json:"date_format"` // layout Go, default 2006-01-02
+	Sheet      string `json:"sheet"`       // XLSX, default sheet pertama
+	Delimiter  string `json:"delimiter"`   // CSV, default ","
+}
+
+// Read membaca seluruh baris data. Error hanya untuk masalah level file
+// (format, header); kesalahan per baris dicatat di ImportRow.Invalid.
+func Read(format Format, r io.Reader, p Profile) ([]domain.ImportRow, error) {
+	var records [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r, p)
+	case FormatXLSX:
+		records, err = readXLSX(r, p)
+	default:
+		return nil, fmt.Errorf("%w: format file harus csv atau xlsx", domain.ErrInvalidInput)
+	}
+	if err != nil { return nil, err }
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: file kosong", domain.ErrInvalidInput)
+	}
+
+	cols, err := p.columns(records[0])
+	if err != nil { return nil, err }
+	var out []domain.ImportRow
+	for i, rec := range records[1:] {
+		if blank(rec) { continue }
+		out = append(out, cols.row(i+2, rec, p.dateFormat()))
+	}
+	return out, nil
+}
+
+func readCSV(r io.Reader, p Profile) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if p.Delimiter != "" {
+		d := []rune(p.Delimiter)
+		if len(d) != 1 {
+			return nil, fmt.Errorf("%w: delimiter harus satu karakter", domain.ErrInvalidInput)
+		}
+		cr.Comma = d[0]
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: CSV tidak valid: %v", domain.ErrInvalidInput, err)
+	}
+	if len(records) > 0 && len(records[0]) > 0 {
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff") // BOM dari Excel
+	}
+	return records, nil
+}
+
+// readXLSX membaca nilai mentah sel, jadi tanggal/jam berformat Excel
+// terbaca sebagai angka serial dan dikonversi di parseDate/parseClock.
+func readXLSX(r io.Reader, p Profile) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: XLSX tidak valid: %v", domain.ErrInvalidInput, err)
+	}
+	defer f.Close()
+	sheet := p.Sheet
+	if sheet == "" { sheet = f.GetSheetName(0) }
+	if idx, _ := f.GetSheetIndex(sheet); idx < 0 {
+		return nil, fmt.Errorf("%w: sheet %q tidak ditemukan", domain.ErrInvalidInput, sheet)
+	}
+	return f.GetRows(sheet, excelize.Options{RawCellValue: true})
+}
+
+func (p Profile) dateFormat() string {
+	if p.DateFormat == "" { return "2006-01-02" }
+	return p.DateFormat
+}
+
+// columns: indeks kolom tiap field (-1 = tidak ada di file).
+type columns struct {
+	employeeID, employeeCode, employeeName, date, start, end, hours, entryType, leaveType, remarks int
+}
+
+func (p Profile) columns(header []string) (columns, error) {
+	idx := map[string]int{}
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	find := func(name, field string) int {
+		if name == "" { name = field }
+		if i, ok := idx[strings.ToLower(strings.TrimSpace(name))]; ok { return i }
+		return -1
+	}
+	c := columns{
+		employeeID:   find(p.EmployeeID, "employee_id"),
+		employeeCode: find(p.EmployeeCode, "employee_code"),
+		employeeName: find(p.EmployeeName, "employee_name"),
+		date:         find(p.Date, "date"),
+		start:        find(p.StartTime, "start_time"),
+		end:          find(p.EndTime, "end_time"),
+		hours:        find(p.TotalHours, "total_hours"),
+		entryType:    find(p.EntryType, "entry_type"),
+		leaveType:    find(p.LeaveTypeID, "leave_type_id"),
+		remarks:      find(p.Remarks, "remarks"),
+	}
+	if c.employeeID < 0 && c.employeeCode < 0 && c.employeeName < 0 {
+		return c, fmt.Errorf("%w: kolom karyawan (employee_id/employee_code/employee_name) tidak ditemukan di header", domain.ErrInvalidInput)
+	}
+	if c.date < 0 {
+		return c, fmt.Errorf("%w: kolom tanggal %q tidak ditemukan di header", domain.ErrInvalidInput, orDefault(p.Date, "date"))
+	}
+	return c, nil
+}
+
+func (c columns) row(line int, rec []string, dateFormat string) domain.ImportRow {
+	get := func(i int) string {
+		if i < 0 || i >= len(rec) { return "" }
+		return strings.TrimSpace(rec[i])
+	}
+	r := domain.ImportRow{Line: line, EmployeeCode: get(c.employeeCode), EmployeeName: get(c.employeeName)}
+	invalid := func(field, msg string) domain.ImportRow {
+		r.Invalid = &domain.ImportError{Line: line, Field: field, Message: msg}
+		return r
+	}
+
+	if v := get(c.employeeID); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 { return invalid("employee_id", "harus angka > 0") }
+		r.EmployeeID = id
+	}
+	if r.EmployeeID == 0 && r.EmployeeCode == "" && r.EmployeeName == "" {
+		return invalid("employee", "karyawan wajib diisi")
+	}
+	d, err := parseDate(get(c.date), dateFormat)
+	if err != nil { return invalid("date", "format tanggal harus "+dateFormat) }
+
+	e := domain.TimesheetEntry{WorkDate: d, Type: domain.EntryType(strings.ToLower(get(c.entryType))), Remarks: get(c.remarks)}
+	if e.StartTime, err = parseClock(get(c.start)); err != nil { return invalid("start_time", "format HH:MM atau HH:MM:SS") }
+	if e.EndTime, err = parseClock(get(c.end)); err != nil { return invalid("end_time", "format HH:MM atau HH:MM:SS") }
+	if v := get(c.hours); v != "" {
+		h, err := strconv.ParseFloat(strings.ReplaceAll(v, ",", "."), 64)
+		if err != nil || h < 0 { return invalid("total_hours", "harus angka ≥ 0") }
+		e.TotalHours = &h
+	}
+	if v := get(c.leaveType); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 { return invalid("leave_type_id", "harus angka > 0") }
+		e.LeaveTypeID = &id
+	}
+	r.Entry = e
+	return r
+}
+
+// parseDate menerima teks sesuai layout, atau angka serial tanggal Excel.
+func parseDate(s, layout string) (time.Time, error) {
+	if d, err := time.Parse(layout, s); err == nil { return d, nil }
+	serial, err := strconv.ParseFloat(s, 64)
+	if err != nil || serial < 1 { return time.Time{}, domain.ErrInvalidInput }
+	t, err := excelize.ExcelDateToTime(math.Floor(serial), false)
+	if err != nil { return time.Time{}, err }
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// parseClock menerima HH:MM, HH.MM, HH:MM:SS, atau pecahan hari Excel
+// (diawali "0.", mis. 0.375 = 09:00; jam 00.xx ditulis "00.xx").
+// Hasilnya jam pada tanggal nol, sama seperti usecase.ParseTime.
+func parseClock(s string) (*time.Time, error) {
+	if s == "" { return nil, nil }
+	if strings.HasPrefix(s, "0.") {
+		frac, err := strconv.ParseFloat(s, 64)
+		if err != nil || frac >= 1 { return nil, domain.ErrInvalidInput }
+		t := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(math.Round(frac*86400)) * time.Second)
+		return &t, nil
+	}
+	s = strings.ReplaceAll(s, ".", ":")
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil { return &t, nil }
+	}
+	return nil, domain.ErrInvalidInput
+}
+
+func blank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" { return false }
+	}
+	return true
+}
+
+func orDefault(s, def string) string {
+	if s == "" { return def }
+	return s
+}
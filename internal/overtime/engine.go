@@ -0,0 +1,82 @@
+// Package overtime menghitung jam lembur dari domain.OvertimePolicy.
+package overtime
+
+import (
+	"math"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// Day adalah input perhitungan untuk satu entry.
+type Day struct {
+	Date        time.Time
+	GrossHours  float64 // durasi kerja
+	DeductBreak bool    // false jika jam diinput manual (dianggap sudah bersih)
+	Holiday     bool
+	// WeekRegularHours: jam reguler hari-hari sebelumnya di minggu yang sama
+	// (Senin–Minggu), untuk batas mingguan.
+	WeekRegularHours float64
+}
+
+// Calculate menerapkan policy ke satu hari kerja:
+//  1. potong istirahat jika durasi ≥ BreakAfterHours
+//  2. hari libur/weekend: semua jam = lembur dengan multiplier-nya
+//  3. hari kerja: lebih dari batas harian = lembur harian, lalu jam reguler yang
+//     melewati batas mingguan = lembur mingguan
+func Calculate(p domain.OvertimePolicy, d Day) domain.OvertimeBreakdown {
+	b := domain.OvertimeBreakdown{PolicyID: p.ID, PolicyName: p.Name, DayType: DayType(d.Date, d.Holiday), GrossHours: round(d.GrossHours)}
+
+	net := math.Max(d.GrossHours, 0)
+	if d.DeductBreak && p.BreakMinutes > 0 && net >= p.BreakAfterHours {
+		b.BreakHours = math.Min(float64(p.BreakMinutes)/60, net)
+		net -= b.BreakHours
+	}
+
+	switch b.DayType {
+	case domain.DayTypeHoliday:
+		b.RestDayHours, b.Multiplier = net, p.HolidayMultiplier
+	case domain.DayTypeWeekend:
+		b.RestDayHours, b.Multiplier = net, p.WeekendMultiplier
+	default:
+		b.Multiplier = p.OvertimeMultiplier
+		regular := net
+		if p.DailyThresholdHours > 0 && regular > p.DailyThresholdHours {
+			b.DailyOvertime = regular - p.DailyThresholdHours
+			regular = p.DailyThresholdHours
+		}
+		if p.WeeklyThresholdHours > 0 {
+			over := d.WeekRegularHours + regular - p.WeeklyThresholdHours
+			b.WeeklyOvertime = math.Min(math.Max(over, 0), regular)
+			regular -= b.WeeklyOvertime
+		}
+		b.RegularHours = regular
+	}
+
+	b.OvertimeHours = b.DailyOvertime + b.WeeklyOvertime + b.RestDayHours
+	b.WeightedHours = b.OvertimeHours * b.Multiplier
+	for _, v := range []*float64{&b.BreakHours, &b.RegularHours, &b.DailyOvertime, &b.WeeklyOvertime, &b.RestDayHours, &b.OvertimeHours, &b.WeightedHours} {
+		*v = round(*v)
+	}
+	return b
+}
+
+// DayType: holiday menang atas weekend.
+func DayType(d time.Time, holiday bool) string {
+	switch {
+	case holiday:
+		return domain.DayTypeHoliday
+	case d.Weekday() == time.Saturday || d.Weekday() == time.Sunday:
+		return domain.DayTypeWeekend
+	}
+	return domain.DayTypeWorkday
+}
+
+// WeekStart mengembalikan hari Senin di minggu yang sama dengan d.
+func WeekStart(d time.Time) time.Time {
+	offset := (int(d.Weekday()) + 6) % 7
+	y, m, day := d.Date()
+	return time.Date(y, m, day-offset, 0, 0, 0, 0, d.Location())
+}
+
+func round(v float64) float64 { return math.Round(v*100) / 100 }
@@ -0,0 +1,77 @@
+package overtime
+
+import "time"
+
+// Window adalah rentang jam harian (mis. jam malam 22:00–06:00). End ≤ Start
+// berarti rentang melewati tengah malam.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// DefaultNightWindow dipakai jika tidak ada policy lembur: 22:00–06:00.
+var DefaultNightWindow = Window{Start: clock(22, 0), End: clock(6, 0)}
+
+// Span adalah rentang kerja relatif terhadap tengah malam work_date, jadi
+// shift yang lewat tengah malam punya To > 24 jam.
+type Span struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// NewSpan membentuk Span dari jam mulai/selesai (jam saja, seperti ParseTime).
+// Selesai ≤ mulai berarti selesai keesokan harinya; startsNextDay menggeser
+// seluruh rentang ke hari berikutnya (mis. segmen 00:30–02:00 setelah shift malam).
+func NewSpan(start, end time.Time, startsNextDay bool) Span {
+	s, e := sinceMidnight(start), sinceMidnight(end)
+	if e <= s { e += day }
+	if startsNextDay { s, e = s+day, e+day }
+	return Span{From: s, To: e}
+}
+
+func (s Span) Hours() float64 { return (s.To - s.From).Hours() }
+
+// EndsNextDay: rentang berakhir setelah tengah malam work_date.
+func (s Span) EndsNextDay() bool { return s.To > day }
+
+// Overlaps: rentang yang hanya bersentuhan (12:00–13:00 dan 13:00–17:00) tidak dianggap tumpang tindih.
+func (s Span) Overlaps(o Span) bool { return s.From < o.To && o.From < s.To }
+
+// NightHours menghitung jam dalam Span yang jatuh di window malam.
+func (s Span) NightHours(w Window) float64 {
+	ws, we := sinceMidnight(w.Start), sinceMidnight(w.End)
+	if we <= ws { we += day }
+	var total time.Duration
+	// Span paling jauh berakhir di hari ke-2, jadi window hari -1 s/d 2 sudah cukup.
+	for d := time.Duration(-1); d <= 2; d++ {
+		if lo, hi := max(s.From, ws+d*day), min(s.To, we+d*day); hi > lo {
+			total += hi - lo
+		}
+	}
+	return round(total.Hours())
+}
+
+// EndsNextDay: jam selesai ≤ jam mulai berarti shift berakhir keesokan harinya.
+func EndsNextDay(start, end time.Time) bool { return NewSpan(start, end, false).EndsNextDay() }
+
+// ShiftHours menghitung durasi shift dari jam mulai/selesai, termasuk shift yang
+// melewati tengah malam (22:00–06:00 = 8 jam).
+func ShiftHours(start, end time.Time) float64 { return NewSpan(start, end, false).Hours() }
+
+// NightHours menghitung jam shift yang jatuh di dalam window malam.
+func NightHours(start, end time.Time, w Window) float64 {
+	return NewSpan(start, end, false).NightHours(w)
+}
+
+// Clock mengembalikan jam dari offset Span (modulo 24 jam), dalam representasi ParseTime.
+func Clock(d time.Duration) time.Time {
+	return time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d % day)
+}
+
+const day = 24 * time.Hour
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+func clock(h, m int) time.Time { return time.Date(0, 1, 1, h, m, 0, 0, time.UTC) }
@@ -0,0 +1,172 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// Build merekap satu timesheet (lengkap dengan entries & stats-nya). Di hari
+// istirahat/libur semua jam kerja adalah lembur; di hari kerja yang dipakai
+// overtime_hours entry. Tier diterapkan per hari.
+func Build(ts *domain.Timesheet, st *domain.TimesheetStats, employeeCode string, f Formula) domain.PayrollRow {
+	r := domain.PayrollRow{
+		EmployeeID: ts.EmployeeID, EmployeeCode: employeeCode, EmployeeName: ts.EmployeeName, Department: ts.Department,
+		Year: ts.Year, Month: ts.Month, Status: ts.Status,
+		WorkedDays: st.WorkedDays, LeaveDays: st.LeaveDays, AbsentDays: st.AbsentDays,
+		TotalHours: st.TotalHours, OvertimeHours: st.OvertimeHours,
+		OvertimeByMultiplier: map[string]float64{},
+	}
+	if ts.TotalWorkingDays != nil { r.WorkingDays = *ts.TotalWorkingDays }
+	for _, m := range f.Multipliers() {
+		r.OvertimeByMultiplier[MultiplierKey(m)] = 0
+	}
+
+	for _, e := range ts.Entries {
+		if e.Type != domain.EntryWork || e.TotalHours == nil { continue }
+		var split map[float64]float64
+		if f.restDay(e) {
+			r.RestDayHours += *e.TotalHours
+			split = Split(f.RestDay, *e.TotalHours)
+		} else {
+			if e.OvertimeHours == nil { continue }
+			r.WorkdayOvertimeHours += *e.OvertimeHours
+			split = Split(f.Workday, *e.OvertimeHours)
+		}
+		for m, h := range split {
+			r.OvertimeByMultiplier[MultiplierKey(m)] += h
+			r.OvertimeUnits += h * m
+		}
+	}
+	for k, v := range r.OvertimeByMultiplier {
+		r.OvertimeByMultiplier[k] = round(v)
+	}
+	r.WorkdayOvertimeHours, r.RestDayHours, r.OvertimeUnits = round(r.WorkdayOvertimeHours), round(r.RestDayHours), round(r.OvertimeUnits)
+	return r
+}
+
+// restDay: libur resmi, Minggu, atau Sabtu kecuali 6 hari kerja seminggu.
+func (f Formula) restDay(e domain.TimesheetEntry) bool {
+	if e.Holiday != "" || (e.Overtime != nil && e.Overtime.DayType == domain.DayTypeHoliday) { return true }
+	switch e.WorkDate.Weekday() {
+	case time.Sunday:
+		return true
+	case time.Saturday:
+		return f.WorkWeek != 6
+	}
+	return false
+}
+
+// ====== Kolom export ======
+
+// Column memetakan satu field PayrollRow ke judul kolom di sistem payroll.
+type Column struct {
+	Header string
+	Field  string
+}
+
+var baseFields = []string{
+	"employee_id", "employee_code", "employee_name", "department", "year", "month", "status",
+	"working_days", "worked_days", "leave_days", "absent_days",
+	"total_hours", "overtime_hours", "workday_ot_hours", "rest_day_hours",
+}
+
+// Fields mengembalikan semua field yang bisa di-export, termasuk ot_<m>x per
+// multiplier formula (mis. ot_1_5x) dan overtime_units.
+func Fields(f Formula) []string {
+	out := append([]string{}, baseFields...)
+	for _, m := range f.Multipliers() {
+		out = append(out, multiplierField(MultiplierKey(m)))
+	}
+	return append(out, "overtime_units")
+}
+
+func multiplierField(key string) string { return "ot_" + strings.ReplaceAll(key, ".", "_") + "x" }
+
+// ParseColumns membaca spec "Judul:field,Judul:field". Spec kosong = semua
+// field dengan judul sama dengan nama field-nya; "field" tanpa judul juga boleh.
+func ParseColumns(spec string, f Formula) ([]Column, error) {
+	known := map[string]bool{}
+	for _, fd := range Fields(f) { known[fd] = true }
+	if strings.TrimSpace(spec) == "" {
+		var cols []Column
+		for _, fd := range Fields(f) { cols = append(cols, Column{Header: fd, Field: fd}) }
+		return cols, nil
+	}
+	var cols []Column
+	for _, part := range strings.Split(spec, ",") {
+		header, field := "", strings.TrimSpace(part)
+		if i := strings.LastIndex(part, ":"); i >= 0 {
+			header, field = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		if header == "" { header = field }
+		if !known[field] {
+			return nil, fmt.Errorf("payroll: field %q tidak dikenal (pilihan: %s)", field, strings.Join(Fields(f), ", "))
+		}
+		cols = append(cols, Column{Header: header, Field: field})
+	}
+	return cols, nil
+}
+
+// Value mengembalikan nilai field dari row; field tidak dikenal → nil.
+func Value(r domain.PayrollRow, field string) interface{} {
+	switch field {
+	case "employee_id": return r.EmployeeID
+	case "employee_code": return r.EmployeeCode
+	case "employee_name": return r.EmployeeName
+	case "department": return r.Department
+	case "year": return r.Year
+	case "month": return r.Month
+	case "status": return r.Status
+	case "working_days": return r.WorkingDays
+	case "worked_days": return r.WorkedDays
+	case "leave_days": return r.LeaveDays
+	case "absent_days": return r.AbsentDays
+	case "total_hours": return r.TotalHours
+	case "overtime_hours": return r.OvertimeHours
+	case "workday_ot_hours": return r.WorkdayOvertimeHours
+	case "rest_day_hours": return r.RestDayHours
+	case "overtime_units": return r.OvertimeUnits
+	}
+	for k, v := range r.OvertimeByMultiplier {
+		if multiplierField(k) == field { return v }
+	}
+	return nil
+}
+
+// Records mengubah rows jadi objek JSON berkunci judul kolom.
+func Records(rows []domain.PayrollRow, cols []Column) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(rows))
+	for _, r := range rows {
+		rec := make(map[string]interface{}, len(cols))
+		for _, c := range cols { rec[c.Header] = Value(r, c.Field) }
+		out = append(out, rec)
+	}
+	return out
+}
+
+func WriteCSV(w io.Writer, rows []domain.PayrollRow, cols []Column) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(cols))
+	for i, c := range cols { header[i] = c.Header }
+	if err := cw.Write(header); err != nil { return err }
+	for _, r := range rows {
+		rec := make([]string, len(cols))
+		for i, c := range cols {
+			switch v := Value(r, c.Field).(type) {
+			case float64:
+				rec[i] = strconv.FormatFloat(v, 'f', 2, 64)
+			default:
+				rec[i] = fmt.Sprint(v)
+			}
+		}
+		if err := cw.Write(rec); err != nil { return err }
+	}
+	cw.Flush()
+	return cw.Error()
+}
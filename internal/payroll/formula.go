@@ -0,0 +1,76 @@
+// Package payroll menghitung jam lembur tertimbang sesuai Kepmenakertrans
+// 102/MEN/VI/2004 dan menyusun baris export untuk sistem payroll.
+package payroll
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Tier: Hours jam berikutnya dikali Multiplier; Hours 0 = semua sisa jam.
+type Tier struct {
+	Hours      float64
+	Multiplier float64
+}
+
+// Formula memisahkan tier lembur hari kerja dan hari istirahat/libur resmi.
+// WorkWeek 6 berarti Sabtu hari kerja biasa.
+type Formula struct {
+	WorkWeek int
+	Workday  []Tier
+	RestDay  []Tier
+}
+
+// Kepmen102 mengembalikan formula Pasal 11 Kepmen 102/2004:
+//   - hari kerja: jam pertama 1,5×, jam berikutnya 2×
+//   - hari istirahat/libur, 5 hari kerja seminggu: 8 jam pertama 2×, jam ke-9 3×, ke-10 dst 4×
+//   - hari istirahat/libur, 6 hari kerja seminggu: 7 jam pertama 2×, jam ke-8 3×, ke-9 dst 4×
+//
+// Libur resmi yang jatuh di hari kerja terpendek (5/6/7 jam) tidak dibedakan.
+func Kepmen102(workWeek int) (Formula, error) {
+	f := Formula{WorkWeek: workWeek, Workday: []Tier{{1, 1.5}, {0, 2}}}
+	switch workWeek {
+	case 5:
+		f.RestDay = []Tier{{8, 2}, {1, 3}, {0, 4}}
+	case 6:
+		f.RestDay = []Tier{{7, 2}, {1, 3}, {0, 4}}
+	default:
+		return Formula{}, fmt.Errorf("payroll: work week %d hari tidak didukung (5 atau 6)", workWeek)
+	}
+	return f, nil
+}
+
+// Split membagi jam lembur satu hari ke tier-nya; hasilnya jam per multiplier.
+func Split(tiers []Tier, hours float64) map[float64]float64 {
+	out := map[float64]float64{}
+	left := hours
+	for _, t := range tiers {
+		if left <= 0 { break }
+		h := left
+		if t.Hours > 0 { h = math.Min(left, t.Hours) }
+		out[t.Multiplier] += h
+		left -= h
+	}
+	return out
+}
+
+// Multipliers mengembalikan semua multiplier di formula, urut naik.
+func (f Formula) Multipliers() []float64 {
+	seen := map[float64]bool{}
+	var out []float64
+	for _, t := range append(append([]Tier{}, f.Workday...), f.RestDay...) {
+		if !seen[t.Multiplier] {
+			seen[t.Multiplier] = true
+			out = append(out, t.Multiplier)
+		}
+	}
+	sort.Float64s(out)
+	return out
+}
+
+// MultiplierKey: 1.5 → "1.5", kunci PayrollRow.OvertimeByMultiplier.
+func MultiplierKey(m float64) string { return strconv.FormatFloat(m, 'f', -1, 64) }
+
+func round(v float64) float64 { return math.Round(v*100) / 100 }
@@ -0,0 +1,20 @@
+package repository
+
+import "timesheet-api/internal/domain"
+
+type UserRepository interface {
+	Create(u *domain.User) (int64, error)
+	FindByID(id int64) (*domain.User, error)
+	FindByUsername(username string) (*domain.User, error)
+	List() ([]domain.User, error)
+}
+
+type RefreshTokenRepository interface {
+	Create(t *domain.RefreshToken) (int64, error)
+	FindByHash(hash string) (*domain.RefreshToken, error)
+	// Rotate mencabut token oldID dan menyimpan penggantinya secara atomik.
+	// ErrUnauthorized jika oldID sudah dicabut duluan (refresh paralel / reuse).
+	Rotate(oldID int64, next *domain.RefreshToken) error
+	RevokeFamily(familyID string) error
+	RevokeUser(userID int64) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type BillingRepository interface {
+	CreateClient(c *domain.Client) (int64, error)
+	FindClient(id int64) (*domain.Client, error)
+	ListClients() ([]domain.Client, error)
+	UpdateClient(c *domain.Client) error
+	DeleteClient(id int64) error
+
+	CreateRate(r *domain.RateCard) (int64, error)
+	FindRate(id int64) (*domain.RateCard, error)
+	// ListRates: clientID nil = semua rate card.
+	ListRates(clientID *int64) ([]domain.RateCard, error)
+	UpdateRate(r *domain.RateCard) error
+	DeleteRate(id int64) error
+
+	// RatesFor mengembalikan rate card klien itu plus yang berlaku untuk semua
+	// klien, yang masa berlakunya beririsan dengan [from, to].
+	RatesFor(clientID int64, from, to time.Time) ([]domain.RateCard, error)
+	// BillableItems mengembalikan jam entry billable klien di [from, to], dipecah
+	// per alokasi project; sisa jam tanpa alokasi jadi item tanpa project.
+	BillableItems(clientID int64, from, to time.Time) ([]domain.BillableItem, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "timesheet-api/internal/domain"
+
+type DepartmentRepository interface {
+	Create(d *domain.Department) (int64, error)
+	FindByID(id int64) (*domain.Department, error)
+	List() ([]domain.Department, error)
+	Update(d *domain.Department) error
+	Delete(id int64) error
+
+	// Subtree mengembalikan id departemen beserta seluruh turunannya.
+	Subtree(id int64) ([]int64, error)
+	// ManagedBy mengembalikan semua departemen (beserta turunannya) yang
+	// manager_id-nya employeeID.
+	ManagedBy(employeeID int64) ([]int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "timesheet-api/internal/domain"
+
+type EmployeeFilter struct {
+	Name   string // pencarian sebagian (case-insensitive)
+	Active *bool
+}
+
+type EmployeeRepository interface {
+	Create(e *domain.Employee) (int64, error)
+	FindByID(id int64) (*domain.Employee, error)
+	FindByName(name string) ([]domain.Employee, error)
+	FindByCode(code string) (*domain.Employee, error)
+	List(f EmployeeFilter) ([]domain.Employee, error)
+	Update(e *domain.Employee) error
+	Delete(id int64) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type HolidayRepository interface {
+	Create(h *domain.Holiday) (int64, error)
+	FindByID(id int64) (*domain.Holiday, error)
+	Update(h *domain.Holiday) error
+	Delete(id int64) error
+
+	// Between mengembalikan hari libur pada rentang [from, to] (inklusif), urut tanggal.
+	Between(from, to time.Time) ([]domain.Holiday, error)
+	// Upsert menyimpan banyak hari libur sekaligus dalam satu transaksi; tanggal
+	// yang sudah ada diperbarui nama & kind-nya.
+	Upsert(items []domain.Holiday) (int, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"timesheet-api/internal/domain"
+)
+
+type ImportRepository interface {
+	// Import menulis semua item dalam satu transaksi: timesheet baru dibuat,
+	// timesheet lama dikunci dan harus masih editable (ErrLocked jika tidak),
+	// lalu entry beserta audit-nya. Gagal satu → tidak ada yang tersimpan.
+	Import(ctx context.Context, items []domain.ImportItem) error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"timesheet-api/internal/domain"
+)
+
+type LeaveFilter struct {
+	EmployeeID *int64
+	Status     domain.LeaveStatus
+	Year       *int
+
+	Scope *Scope
+}
+
+type LeaveRepository interface {
+	CreateType(t *domain.LeaveType) (int64, error)
+	FindType(id int64) (*domain.LeaveType, error)
+	ListTypes() ([]domain.LeaveType, error)
+	UpdateType(t *domain.LeaveType) error
+	DeleteType(id int64) error
+
+	CreateRequest(lr *domain.LeaveRequest) (int64, error)
+	FindRequest(id int64) (*domain.LeaveRequest, error)
+	ListRequests(f LeaveFilter) ([]domain.LeaveRequest, error)
+	// Decide memindahkan status pengajuan dari d.From ke d.To dalam satu transaksi.
+	// Untuk approve, timesheet baru di d.Days dibuat, saldo cuti dipotong sebanyak
+	// len(d.Days) (ErrInvalidInput jika tidak cukup; days pengajuan ikut diperbarui)
+	// dan d.Days ditulis sebagai entry leave di timesheet masing-masing
+	// (ErrLocked jika timesheet-nya sudah submitted/approved). ErrTimeout jika
+	// melewati batas waktu tulis.
+	Decide(ctx context.Context, d *domain.LeaveDecision) error
+
+	// Balances mengembalikan saldo semua jenis cuti ber-kuota untuk karyawan & tahun itu;
+	// yang belum punya baris dianggap entitled = annual_quota.
+	Balances(employeeID int64, year int) ([]domain.LeaveBalance, error)
+	SetEntitlement(b *domain.LeaveBalance) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "timesheet-api/internal/domain"
+
+type OvertimePolicyRepository interface {
+	Create(p *domain.OvertimePolicy) (int64, error)
+	FindByID(id int64) (*domain.OvertimePolicy, error)
+	List() ([]domain.OvertimePolicy, error)
+	Update(p *domain.OvertimePolicy) error
+	Delete(id int64) error
+
+	// ForDepartment mengembalikan policy terdekat di hierarki departemen
+	// (departemen itu sendiri, lalu parent-parent-nya), atau policy default.
+	// nil, nil jika tidak ada sama sekali.
+	ForDepartment(departmentID *int64) (*domain.OvertimePolicy, error)
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type UserRepoPG struct {
+	DB *sql.DB
+}
+
+func NewUserRepoPG(db *sql.DB) *UserRepoPG { return &UserRepoPG{DB: db} }
+
+const userCols = `id, username, password_hash, role, employee_id, active, created_at`
+
+func scanUser(row interface{ Scan(...interface{}) error }, u *domain.User) error {
+	return row.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role, &u.EmployeeID, &u.Active, &u.CreatedAt)
+}
+
+func (r *UserRepoPG) Create(u *domain.User) (int64, error) {
+	q := `INSERT INTO users (username, password_hash, role, employee_id, active) VALUES ($1,$2,$3,$4,$5) RETURNING id, created_at`
+	var id int64
+	var created time.Time
+	if err := r.DB.QueryRow(q, u.Username, u.PasswordHash, u.Role, u.EmployeeID, u.Active).Scan(&id, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	u.ID = id
+	u.CreatedAt = created
+	return id, nil
+}
+
+func (r *UserRepoPG) FindByID(id int64) (*domain.User, error) {
+	return r.findOne(`SELECT `+userCols+` FROM users WHERE id=$1`, id)
+}
+
+func (r *UserRepoPG) FindByUsername(username string) (*domain.User, error) {
+	return r.findOne(`SELECT `+userCols+` FROM users WHERE lower(username)=lower($1)`, username)
+}
+
+func (r *UserRepoPG) findOne(q string, arg interface{}) (*domain.User, error) {
+	var u domain.User
+	err := scanUser(r.DB.QueryRow(q, arg), &u)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepoPG) List() ([]domain.User, error) {
+	rows, err := r.DB.Query(`SELECT ` + userCols + ` FROM users ORDER BY username ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := scanUser(rows, &u); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+type RefreshTokenRepoPG struct {
+	DB *sql.DB
+}
+
+func NewRefreshTokenRepoPG(db *sql.DB) *RefreshTokenRepoPG { return &RefreshTokenRepoPG{DB: db} }
+
+func (r *RefreshTokenRepoPG) Create(t *domain.RefreshToken) (int64, error) {
+	return insertRefreshToken(r.DB, t)
+}
+
+func insertRefreshToken(q interface {
+	QueryRow(string, ...interface{}) *sql.Row
+}, t *domain.RefreshToken) (int64, error) {
+	err := q.QueryRow(`INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+	                   VALUES ($1,$2,$3,$4) RETURNING id, created_at`,
+		t.UserID, t.TokenHash, t.FamilyID, t.ExpiresAt).Scan(&t.ID, &t.CreatedAt)
+	if err != nil { return 0, mapPGError(err) }
+	return t.ID, nil
+}
+
+func (r *RefreshTokenRepoPG) FindByHash(hash string) (*domain.RefreshToken, error) {
+	var t domain.RefreshToken
+	err := r.DB.QueryRow(`SELECT id, user_id, token_hash, family_id, expires_at, revoked_at, replaced_by_id, created_at
+	                      FROM refresh_tokens WHERE token_hash=$1`, hash).
+		Scan(&t.ID, &t.UserID, &t.TokenHash, &t.FamilyID, &t.ExpiresAt, &t.RevokedAt, &t.ReplacedByID, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *RefreshTokenRepoPG) Rotate(oldID int64, next *domain.RefreshToken) error {
+	tx, err := r.DB.Begin()
+	if err != nil { return err }
+	defer tx.Rollback()
+
+	if _, err := insertRefreshToken(tx, next); err != nil {
+		return err
+	}
+	res, err := tx.Exec(`UPDATE refresh_tokens SET revoked_at=NOW(), replaced_by_id=$1
+	                     WHERE id=$2 AND revoked_at IS NULL`, next.ID, oldID)
+	if err != nil { return err }
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return domain.ErrUnauthorized
+	}
+	return tx.Commit()
+}
+
+func (r *RefreshTokenRepoPG) RevokeFamily(familyID string) error {
+	_, err := r.DB.Exec(`UPDATE refresh_tokens SET revoked_at=NOW() WHERE family_id=$1 AND revoked_at IS NULL`, familyID)
+	return err
+}
+
+func (r *RefreshTokenRepoPG) RevokeUser(userID int64) error {
+	_, err := r.DB.Exec(`UPDATE refresh_tokens SET revoked_at=NOW() WHERE user_id=$1 AND revoked_at IS NULL`, userID)
+	return err
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type BillingRepoPG struct {
+	DB *sql.DB
+}
+
+func NewBillingRepoPG(db *sql.DB) *BillingRepoPG { return &BillingRepoPG{DB: db} }
+
+// ====== Clients ======
+
+const clientCols = `id, code, name, currency, active, created_at`
+
+func scanClient(row interface{ Scan(...interface{}) error }, c *domain.Client) error {
+	return row.Scan(&c.ID, &c.Code, &c.Name, &c.Currency, &c.Active, &c.CreatedAt)
+}
+
+func (r *BillingRepoPG) CreateClient(c *domain.Client) (int64, error) {
+	q := `INSERT INTO clients (code, name, currency, active) VALUES ($1,$2,$3,$4) RETURNING id, created_at`
+	var created time.Time
+	if err := r.DB.QueryRow(q, c.Code, c.Name, c.Currency, c.Active).Scan(&c.ID, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	c.CreatedAt = created
+	return c.ID, nil
+}
+
+func (r *BillingRepoPG) FindClient(id int64) (*domain.Client, error) {
+	var c domain.Client
+	err := scanClient(r.DB.QueryRow(`SELECT `+clientCols+` FROM clients WHERE id=$1`, id), &c)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *BillingRepoPG) ListClients() ([]domain.Client, error) {
+	rows, err := r.DB.Query(`SELECT ` + clientCols + ` FROM clients ORDER BY code ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Client
+	for rows.Next() {
+		var c domain.Client
+		if err := scanClient(rows, &c); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *BillingRepoPG) UpdateClient(c *domain.Client) error {
+	res, err := r.DB.Exec(`UPDATE clients SET code=$1, name=$2, currency=$3, active=$4 WHERE id=$5`,
+		c.Code, c.Name, c.Currency, c.Active, c.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// DeleteClient gagal dengan ErrInUse jika klien sudah dipakai project/entry.
+func (r *BillingRepoPG) DeleteClient(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM clients WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// ====== Rate cards ======
+
+const rateCols = `id, client_id, project_id, COALESCE(position, ''), hourly_rate, effective_from, effective_to, created_at`
+
+func scanRate(row interface{ Scan(...interface{}) error }, rc *domain.RateCard) error {
+	return row.Scan(&rc.ID, &rc.ClientID, &rc.ProjectID, &rc.Position, &rc.HourlyRate, &rc.EffectiveFrom, &rc.EffectiveTo, &rc.CreatedAt)
+}
+
+func collectRates(rows *sql.Rows) ([]domain.RateCard, error) {
+	defer rows.Close()
+	var out []domain.RateCard
+	for rows.Next() {
+		var rc domain.RateCard
+		if err := scanRate(rows, &rc); err != nil {
+			return nil, err
+		}
+		out = append(out, rc)
+	}
+	return out, rows.Err()
+}
+
+func (r *BillingRepoPG) CreateRate(rc *domain.RateCard) (int64, error) {
+	q := `INSERT INTO rate_cards (client_id, project_id, position, hourly_rate, effective_from, effective_to)
+	      VALUES ($1,$2,NULLIF($3,''),$4,$5,$6) RETURNING id, created_at`
+	var created time.Time
+	err := r.DB.QueryRow(q, rc.ClientID, rc.ProjectID, rc.Position, rc.HourlyRate, rc.EffectiveFrom, rc.EffectiveTo).
+		Scan(&rc.ID, &created)
+	if err != nil { return 0, mapPGError(err) }
+	rc.CreatedAt = created
+	return rc.ID, nil
+}
+
+func (r *BillingRepoPG) FindRate(id int64) (*domain.RateCard, error) {
+	var rc domain.RateCard
+	err := scanRate(r.DB.QueryRow(`SELECT `+rateCols+` FROM rate_cards WHERE id=$1`, id), &rc)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+func (r *BillingRepoPG) ListRates(clientID *int64) ([]domain.RateCard, error) {
+	rows, err := r.DB.Query(`SELECT `+rateCols+` FROM rate_cards
+	                         WHERE $1::bigint IS NULL OR client_id = $1
+	                         ORDER BY client_id NULLS FIRST, project_id NULLS FIRST, position NULLS FIRST, effective_from`, clientID)
+	if err != nil { return nil, err }
+	return collectRates(rows)
+}
+
+func (r *BillingRepoPG) UpdateRate(rc *domain.RateCard) error {
+	res, err := r.DB.Exec(`UPDATE rate_cards SET client_id=$1, project_id=$2, position=NULLIF($3,''), hourly_rate=$4,
+	                         effective_from=$5, effective_to=$6 WHERE id=$7`,
+		rc.ClientID, rc.ProjectID, rc.Position, rc.HourlyRate, rc.EffectiveFrom, rc.EffectiveTo, rc.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *BillingRepoPG) DeleteRate(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM rate_cards WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *BillingRepoPG) RatesFor(clientID int64, from, to time.Time) ([]domain.RateCard, error) {
+	rows, err := r.DB.Query(`SELECT `+rateCols+` FROM rate_cards
+	                         WHERE (client_id = $1 OR client_id IS NULL)
+	                           AND effective_from <= $3 AND (effective_to IS NULL OR effective_to >= $2)`, clientID, from, to)
+	if err != nil { return nil, err }
+	return collectRates(rows)
+}
+
+// ====== Invoice ======
+
+func (r *BillingRepoPG) BillableItems(clientID int64, from, to time.Time) ([]domain.BillableItem, error) {
+	q := `
+	  WITH b AS (
+	    SELECT en.id, en.work_date, COALESCE(en.total_hours, 0) AS total_hours, t.employee_id, e.name, COALESCE(e.position, '') AS position,
+	           COALESCE((SELECT SUM(a.hours) FROM entry_allocations a WHERE a.entry_id = en.id), 0) AS allocated
+	    FROM timesheet_entries en
+	    JOIN timesheets t ON t.id = en.timesheet_id
+	    JOIN employees e ON e.id = t.employee_id
+	    WHERE en.billable AND en.entry_type = 'work' AND en.client_id = $1 AND en.work_date BETWEEN $2 AND $3
+	  )
+	  SELECT b.id, b.work_date, b.employee_id, b.name, b.position, a.project_id, p.code, a.hours
+	  FROM b JOIN entry_allocations a ON a.entry_id = b.id JOIN projects p ON p.id = a.project_id
+	  UNION ALL
+	  SELECT b.id, b.work_date, b.employee_id, b.name, b.position, NULL::bigint, '', b.total_hours - b.allocated
+	  FROM b WHERE b.total_hours > b.allocated
+	  ORDER BY 4, 2, 1`
+	rows, err := r.DB.Query(q, clientID, from, to)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.BillableItem
+	for rows.Next() {
+		var it domain.BillableItem
+		if err := rows.Scan(&it.EntryID, &it.WorkDate, &it.EmployeeID, &it.EmployeeName, &it.Position,
+			&it.ProjectID, &it.ProjectCode, &it.Hours); err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
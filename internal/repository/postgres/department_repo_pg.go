@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type DepartmentRepoPG struct {
+	DB *sql.DB
+}
+
+func NewDepartmentRepoPG(db *sql.DB) *DepartmentRepoPG { return &DepartmentRepoPG{DB: db} }
+
+const departmentCols = `id, code, name, parent_id, manager_id, overtime_policy_id, created_at`
+
+// subtreeSQL mengembalikan id departemen yang cocok dengan kondisi %s
+// beserta semua turunannya.
+const subtreeSQL = `WITH RECURSIVE sub AS (
+	  SELECT id FROM departments WHERE %s
+	  UNION ALL
+	  SELECT c.id FROM departments c JOIN sub ON c.parent_id = sub.id
+	) SELECT id FROM sub`
+
+func scanDepartment(row interface{ Scan(...interface{}) error }, d *domain.Department) error {
+	return row.Scan(&d.ID, &d.Code, &d.Name, &d.ParentID, &d.ManagerID, &d.OvertimePolicyID, &d.CreatedAt)
+}
+
+func (r *DepartmentRepoPG) Create(d *domain.Department) (int64, error) {
+	q := `INSERT INTO departments (code, name, parent_id, manager_id, overtime_policy_id) VALUES ($1,$2,$3,$4,$5) RETURNING id, created_at`
+	var id int64
+	var created time.Time
+	if err := r.DB.QueryRow(q, d.Code, d.Name, d.ParentID, d.ManagerID, d.OvertimePolicyID).Scan(&id, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	d.ID = id
+	d.CreatedAt = created
+	return id, nil
+}
+
+func (r *DepartmentRepoPG) FindByID(id int64) (*domain.Department, error) {
+	var d domain.Department
+	err := scanDepartment(r.DB.QueryRow(`SELECT `+departmentCols+` FROM departments WHERE id=$1`, id), &d)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *DepartmentRepoPG) List() ([]domain.Department, error) {
+	rows, err := r.DB.Query(`SELECT ` + departmentCols + ` FROM departments ORDER BY name ASC, id ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Department
+	for rows.Next() {
+		var d domain.Department
+		if err := scanDepartment(rows, &d); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *DepartmentRepoPG) Update(d *domain.Department) error {
+	res, err := r.DB.Exec(`UPDATE departments SET code=$1, name=$2, parent_id=$3, manager_id=$4, overtime_policy_id=$5 WHERE id=$6`,
+		d.Code, d.Name, d.ParentID, d.ManagerID, d.OvertimePolicyID, d.ID)
+	if err != nil { return mapPGError(err) }
+	aff, _ := res.RowsAffected()
+	if aff == 0 { return domain.ErrNotFound }
+	return nil
+}
+
+func (r *DepartmentRepoPG) Delete(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM departments WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	aff, _ := res.RowsAffected()
+	if aff == 0 { return domain.ErrNotFound }
+	return nil
+}
+
+func (r *DepartmentRepoPG) Subtree(id int64) ([]int64, error) {
+	ids, err := r.queryIDs(fmt.Sprintf(subtreeSQL, "id = $1"), id)
+	if err != nil { return nil, err }
+	if len(ids) == 0 { return nil, domain.ErrNotFound }
+	return ids, nil
+}
+
+func (r *DepartmentRepoPG) ManagedBy(employeeID int64) ([]int64, error) {
+	return r.queryIDs(`SELECT DISTINCT id FROM (`+fmt.Sprintf(subtreeSQL, "manager_id = $1")+`) m`, employeeID)
+}
+
+func (r *DepartmentRepoPG) queryIDs(q string, args ...interface{}) ([]int64, error) {
+	rows, err := r.DB.Query(q, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		ids = append(ids, v)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type EmployeeRepoPG struct {
+	DB *sql.DB
+}
+
+func NewEmployeeRepoPG(db *sql.DB) *EmployeeRepoPG { return &EmployeeRepoPG{DB: db} }
+
+const employeeCols = `id, code, name, email, position, department_id, active, created_at`
+
+func scanEmployee(row interface{ Scan(...interface{}) error }, e *domain.Employee) error {
+	return row.Scan(&e.ID, &e.Code, &e.Name, &e.Email, &e.Position, &e.DepartmentID, &e.Active, &e.CreatedAt)
+}
+
+func (r *EmployeeRepoPG) Create(e *domain.Employee) (int64, error) {
+	q := `INSERT INTO employees (code, name, email, position, department_id, active) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id, created_at`
+	var id int64
+	var created time.Time
+	if err := r.DB.QueryRow(q, e.Code, e.Name, e.Email, e.Position, e.DepartmentID, e.Active).Scan(&id, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	e.ID = id
+	e.CreatedAt = created
+	return id, nil
+}
+
+func (r *EmployeeRepoPG) FindByID(id int64) (*domain.Employee, error) {
+	var e domain.Employee
+	err := scanEmployee(r.DB.QueryRow(`SELECT `+employeeCols+` FROM employees WHERE id=$1`, id), &e)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *EmployeeRepoPG) FindByName(name string) ([]domain.Employee, error) {
+	rows, err := r.DB.Query(`SELECT `+employeeCols+` FROM employees WHERE name = $1 ORDER BY id`, name)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	return collectEmployees(rows)
+}
+
+func (r *EmployeeRepoPG) FindByCode(code string) (*domain.Employee, error) {
+	var e domain.Employee
+	err := scanEmployee(r.DB.QueryRow(`SELECT `+employeeCols+` FROM employees WHERE code=$1`, code), &e)
+	if err == sql.ErrNoRows { return nil, domain.ErrNotFound }
+	if err != nil { return nil, err }
+	return &e, nil
+}
+
+func (r *EmployeeRepoPG) List(f repository.EmployeeFilter) ([]domain.Employee, error) {
+	q := `SELECT ` + employeeCols + ` FROM employees WHERE 1=1`
+	var args []interface{}
+	i := 1
+	if f.Name != "" { q += fmt.Sprintf(" AND name ILIKE $%d", i); args = append(args, "%"+f.Name+"%"); i++ }
+	if f.Active != nil { q += fmt.Sprintf(" AND active = $%d", i); args = append(args, *f.Active); i++ }
+	q += " ORDER BY name ASC, id ASC"
+
+	rows, err := r.DB.Query(q, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	return collectEmployees(rows)
+}
+
+func (r *EmployeeRepoPG) Update(e *domain.Employee) error {
+	res, err := r.DB.Exec(`UPDATE employees SET code=$1, name=$2, email=$3, position=$4, department_id=$5, active=$6 WHERE id=$7`,
+		e.Code, e.Name, e.Email, e.Position, e.DepartmentID, e.Active, e.ID)
+	if err != nil { return mapPGError(err) }
+	aff, _ := res.RowsAffected()
+	if aff == 0 { return domain.ErrNotFound }
+	return nil
+}
+
+func (r *EmployeeRepoPG) Delete(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM employees WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	aff, _ := res.RowsAffected()
+	if aff == 0 { return domain.ErrNotFound }
+	return nil
+}
+
+func collectEmployees(rows *sql.Rows) ([]domain.Employee, error) {
+	var out []domain.Employee
+	for rows.Next() {
+		var e domain.Employee
+		if err := scanEmployee(rows, &e); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"timesheet-api/internal/domain"
+)
+
+// mapPGError menerjemahkan pelanggaran constraint Postgres ke error domain.
+func mapPGError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return domain.ErrDuplicate
+		case "23503": // foreign_key_violation
+			return domain.ErrInUse
+		}
+	}
+	return err
+}
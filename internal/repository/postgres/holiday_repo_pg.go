@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type HolidayRepoPG struct {
+	DB *sql.DB
+}
+
+func NewHolidayRepoPG(db *sql.DB) *HolidayRepoPG { return &HolidayRepoPG{DB: db} }
+
+const holidayCols = `id, holiday_date, name, kind, created_at`
+
+func scanHoliday(row interface{ Scan(...interface{}) error }, h *domain.Holiday) error {
+	return row.Scan(&h.ID, &h.Date, &h.Name, &h.Kind, &h.CreatedAt)
+}
+
+func (r *HolidayRepoPG) Create(h *domain.Holiday) (int64, error) {
+	q := `INSERT INTO holidays (holiday_date, name, kind) VALUES ($1,$2,$3) RETURNING id, created_at`
+	var id int64
+	var created time.Time
+	if err := r.DB.QueryRow(q, h.Date, h.Name, h.Kind).Scan(&id, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	h.ID = id
+	h.CreatedAt = created
+	return id, nil
+}
+
+func (r *HolidayRepoPG) FindByID(id int64) (*domain.Holiday, error) {
+	var h domain.Holiday
+	err := scanHoliday(r.DB.QueryRow(`SELECT `+holidayCols+` FROM holidays WHERE id=$1`, id), &h)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *HolidayRepoPG) Update(h *domain.Holiday) error {
+	res, err := r.DB.Exec(`UPDATE holidays SET holiday_date=$1, name=$2, kind=$3 WHERE id=$4`, h.Date, h.Name, h.Kind, h.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *HolidayRepoPG) Delete(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM holidays WHERE id=$1`, id)
+	if err != nil { return err }
+	return mustAffect(res)
+}
+
+func (r *HolidayRepoPG) Between(from, to time.Time) ([]domain.Holiday, error) {
+	rows, err := r.DB.Query(`SELECT `+holidayCols+` FROM holidays WHERE holiday_date BETWEEN $1 AND $2 ORDER BY holiday_date ASC`, from, to)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Holiday
+	for rows.Next() {
+		var h domain.Holiday
+		if err := scanHoliday(rows, &h); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (r *HolidayRepoPG) Upsert(items []domain.Holiday) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil { return 0, err }
+	defer tx.Rollback()
+
+	q := `INSERT INTO holidays (holiday_date, name, kind) VALUES ($1,$2,$3)
+	      ON CONFLICT (holiday_date) DO UPDATE SET name = EXCLUDED.name, kind = EXCLUDED.kind`
+	for _, h := range items {
+		if _, err := tx.Exec(q, h.Date, h.Name, h.Kind); err != nil {
+			return 0, mapPGError(err)
+		}
+	}
+	return len(items), tx.Commit()
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"timesheet-api/internal/domain"
+)
+
+type ImportRepoPG struct {
+	DB *sql.DB
+}
+
+func NewImportRepoPG(db *sql.DB) *ImportRepoPG { return &ImportRepoPG{DB: db} }
+
+func (r *ImportRepoPG) Import(ctx context.Context, items []domain.ImportItem) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil { return err }
+	defer tx.Rollback()
+
+	done := map[*domain.Timesheet]bool{}
+	for _, it := range items {
+		ts := it.Timesheet
+		if !done[ts] {
+			if err := prepareImportTimesheet(ctx, tx, ts, it.TimesheetAudit); err != nil { return err }
+			done[ts] = true
+		}
+		it.Entry.TimesheetID = ts.ID
+		if err := insertEntry(ctx, tx, it.Entry); err != nil { return err }
+		if it.Audit != nil {
+			it.Audit.TimesheetID, it.Audit.EntityID = ts.ID, it.Entry.ID
+			if err := insertAuditEvent(ctx, tx, it.Audit); err != nil { return err }
+		}
+	}
+	return tx.Commit()
+}
+
+// prepareImportTimesheet membuat timesheet baru, atau mengunci timesheet lama
+// supaya statusnya tidak berubah (submit/approve) selama import berjalan.
+func prepareImportTimesheet(ctx context.Context, tx *sql.Tx, ts *domain.Timesheet, ev *domain.AuditEvent) error {
+	if ts.ID == 0 {
+		if err := insertTimesheet(ctx, tx, ts); err != nil { return err }
+		if ev == nil { return nil }
+		ev.TimesheetID, ev.EntityID = ts.ID, ts.ID
+		return insertAuditEvent(ctx, tx, ev)
+	}
+	var st domain.TimesheetStatus
+	err := tx.QueryRowContext(ctx, `SELECT status FROM timesheets WHERE id=$1 FOR UPDATE`, ts.ID).Scan(&st)
+	if err == sql.ErrNoRows { return domain.ErrNotFound }
+	if err != nil { return err }
+	if !st.Editable() {
+		return fmt.Errorf("%w: timesheet %d/%d sudah %s", domain.ErrLocked, ts.Month, ts.Year, st)
+	}
+	return nil
+}
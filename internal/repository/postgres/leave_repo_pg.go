@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type LeaveRepoPG struct {
+	DB       *sql.DB
+	Timeouts QueryTimeouts // hanya Decide, yang ikut menulis timesheet
+}
+
+func NewLeaveRepoPG(db *sql.DB) *LeaveRepoPG { return &LeaveRepoPG{DB: db} }
+
+// WithTimeouts memasang batas waktu query per operasi.
+func (r *LeaveRepoPG) WithTimeouts(t QueryTimeouts) *LeaveRepoPG {
+	r.Timeouts = t
+	return r
+}
+
+// ====== Leave types ======
+
+const leaveTypeCols = `id, code, name, paid, annual_quota, created_at`
+
+func scanLeaveType(row interface{ Scan(...interface{}) error }, t *domain.LeaveType) error {
+	return row.Scan(&t.ID, &t.Code, &t.Name, &t.Paid, &t.AnnualQuota, &t.CreatedAt)
+}
+
+func (r *LeaveRepoPG) CreateType(t *domain.LeaveType) (int64, error) {
+	q := `INSERT INTO leave_types (code, name, paid, annual_quota) VALUES ($1,$2,$3,$4) RETURNING id, created_at`
+	var created time.Time
+	if err := r.DB.QueryRow(q, t.Code, t.Name, t.Paid, t.AnnualQuota).Scan(&t.ID, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	t.CreatedAt = created
+	return t.ID, nil
+}
+
+func (r *LeaveRepoPG) FindType(id int64) (*domain.LeaveType, error) {
+	var t domain.LeaveType
+	err := scanLeaveType(r.DB.QueryRow(`SELECT `+leaveTypeCols+` FROM leave_types WHERE id=$1`, id), &t)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *LeaveRepoPG) ListTypes() ([]domain.LeaveType, error) {
+	rows, err := r.DB.Query(`SELECT ` + leaveTypeCols + ` FROM leave_types ORDER BY code ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.LeaveType
+	for rows.Next() {
+		var t domain.LeaveType
+		if err := scanLeaveType(rows, &t); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveRepoPG) UpdateType(t *domain.LeaveType) error {
+	res, err := r.DB.Exec(`UPDATE leave_types SET code=$1, name=$2, paid=$3, annual_quota=$4 WHERE id=$5`,
+		t.Code, t.Name, t.Paid, t.AnnualQuota, t.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *LeaveRepoPG) DeleteType(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM leave_types WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// ====== Leave requests ======
+
+const (
+	leaveRequestCols = `lr.id, lr.employee_id, e.name, lr.leave_type_id, lt.name, lr.start_date, lr.end_date, lr.days,
+	                    COALESCE(lr.reason, ''), lr.status, lr.decided_by, lr.decided_at, COALESCE(lr.decision_note, ''), lr.created_at`
+	leaveRequestFrom = `FROM leave_requests lr JOIN employees e ON e.id = lr.employee_id JOIN leave_types lt ON lt.id = lr.leave_type_id`
+)
+
+func scanLeaveRequest(row interface{ Scan(...interface{}) error }, lr *domain.LeaveRequest) error {
+	return row.Scan(&lr.ID, &lr.EmployeeID, &lr.EmployeeName, &lr.LeaveTypeID, &lr.LeaveType, &lr.StartDate, &lr.EndDate, &lr.Days,
+		&lr.Reason, &lr.Status, &lr.DecidedBy, &lr.DecidedAt, &lr.DecisionNote, &lr.CreatedAt)
+}
+
+func (r *LeaveRepoPG) CreateRequest(lr *domain.LeaveRequest) (int64, error) {
+	q := `INSERT INTO leave_requests (employee_id, leave_type_id, start_date, end_date, days, reason)
+	      VALUES ($1,$2,$3,$4,$5,NULLIF($6,'')) RETURNING id, status, created_at`
+	var created time.Time
+	err := r.DB.QueryRow(q, lr.EmployeeID, lr.LeaveTypeID, lr.StartDate, lr.EndDate, lr.Days, lr.Reason).
+		Scan(&lr.ID, &lr.Status, &created)
+	if err != nil {
+		return 0, mapPGError(err)
+	}
+	lr.CreatedAt = created
+	return lr.ID, nil
+}
+
+func (r *LeaveRepoPG) FindRequest(id int64) (*domain.LeaveRequest, error) {
+	var lr domain.LeaveRequest
+	err := scanLeaveRequest(r.DB.QueryRow(`SELECT `+leaveRequestCols+` `+leaveRequestFrom+` WHERE lr.id=$1`, id), &lr)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lr, nil
+}
+
+func (r *LeaveRepoPG) ListRequests(f repository.LeaveFilter) ([]domain.LeaveRequest, error) {
+	q := `SELECT ` + leaveRequestCols + ` ` + leaveRequestFrom + ` WHERE 1=1`
+	var args []interface{}
+	i := 1
+	if f.EmployeeID != nil { q += fmt.Sprintf(" AND lr.employee_id = $%d", i); args = append(args, *f.EmployeeID); i++ }
+	if f.Status != "" { q += fmt.Sprintf(" AND lr.status = $%d", i); args = append(args, f.Status); i++ }
+	if f.Year != nil { q += fmt.Sprintf(" AND EXTRACT(YEAR FROM lr.start_date) = $%d", i); args = append(args, *f.Year); i++ }
+	if f.Scope != nil {
+		q += fmt.Sprintf(" AND (lr.employee_id = $%d OR e.department_id = ANY($%d))", i, i+1)
+		args = append(args, f.Scope.EmployeeID, f.Scope.DepartmentIDs); i += 2
+	}
+	q += " ORDER BY lr.start_date DESC, lr.id DESC"
+
+	rows, err := r.DB.Query(q, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.LeaveRequest
+	for rows.Next() {
+		var lr domain.LeaveRequest
+		if err := scanLeaveRequest(rows, &lr); err != nil {
+			return nil, err
+		}
+		out = append(out, lr)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveRepoPG) Decide(ctx context.Context, d *domain.LeaveDecision) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil { return err }
+	defer tx.Rollback()
+
+	var employeeID, typeID int64
+	var year int
+	err = tx.QueryRowContext(ctx, `UPDATE leave_requests SET status=$1, decided_by=$2, decided_at=NOW(), decision_note=NULLIF($3,'')
+	                               WHERE id=$4 AND status=$5
+	                               RETURNING employee_id, leave_type_id, EXTRACT(YEAR FROM start_date)::int`,
+		d.To, d.Actor, d.Note, d.RequestID, d.From).Scan(&employeeID, &typeID, &year)
+	if err == sql.ErrNoRows {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM leave_requests WHERE id=$1)`, d.RequestID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists { return domain.ErrNotFound }
+		return domain.ErrInvalidTransition
+	}
+	if err != nil { return err }
+
+	if d.To == domain.LeaveApproved {
+		// Hari kerja dihitung ulang saat approve, jadi saldo & days mengikuti d.Days.
+		days := float64(len(d.Days))
+		if _, err := tx.ExecContext(ctx, `UPDATE leave_requests SET days=$1 WHERE id=$2`, days, d.RequestID); err != nil { return err }
+		if err := createLeaveTimesheets(ctx, tx, d.Days); err != nil { return err }
+		if err := deductBalance(ctx, tx, employeeID, typeID, year, days); err != nil { return err }
+		if err := writeLeaveEntries(ctx, tx, d, typeID); err != nil { return err }
+	}
+	return tx.Commit()
+}
+
+// createLeaveTimesheets membuat timesheet bulanan yang belum ada (ID 0).
+func createLeaveTimesheets(ctx context.Context, tx *sql.Tx, days []domain.LeaveDay) error {
+	for _, day := range days {
+		ts := day.Timesheet
+		if ts.ID != 0 { continue }
+		if err := insertTimesheet(ctx, tx, ts); err != nil { return err }
+		if ev := day.TimesheetAudit; ev != nil {
+			ev.TimesheetID, ev.EntityID = ts.ID, ts.ID
+			if err := insertAuditEvent(ctx, tx, ev); err != nil { return err }
+		}
+	}
+	return nil
+}
+
+// deductBalance memotong saldo jenis cuti ber-kuota; jenis tanpa kuota dilewati.
+func deductBalance(ctx context.Context, tx *sql.Tx, employeeID, typeID int64, year int, days float64) error {
+	var quota float64
+	if err := tx.QueryRowContext(ctx, `SELECT annual_quota FROM leave_types WHERE id=$1`, typeID).Scan(&quota); err != nil { return err }
+	if quota <= 0 { return nil }
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO leave_balances (employee_id, leave_type_id, year, entitled) VALUES ($1,$2,$3,$4)
+	                                  ON CONFLICT DO NOTHING`, employeeID, typeID, year, quota); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `UPDATE leave_balances SET used = used + $4
+	                                 WHERE employee_id=$1 AND leave_type_id=$2 AND year=$3 AND entitled - used >= $4`,
+		employeeID, typeID, year, days)
+	if err != nil { return err }
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return fmt.Errorf("%w: saldo cuti tidak cukup", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// writeLeaveEntries menulis satu entry leave per hari. Entry yang sudah ada di
+// tanggal itu diubah jadi leave (jam kerjanya dikosongkan) dan tercatat di audit.
+func writeLeaveEntries(ctx context.Context, tx *sql.Tx, d *domain.LeaveDecision, typeID int64) error {
+	var ids []int64
+	for _, day := range d.Days { ids = append(ids, day.Timesheet.ID) }
+	rows, err := tx.QueryContext(ctx, `SELECT status FROM timesheets WHERE id = ANY($1) FOR UPDATE`, ids)
+	if err != nil { return err }
+	for rows.Next() {
+		var st domain.TimesheetStatus
+		if err := rows.Scan(&st); err != nil { rows.Close(); return err }
+		if !st.Editable() {
+			rows.Close()
+			return fmt.Errorf("%w: timesheet periode cuti sudah %s", domain.ErrLocked, st)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil { return err }
+
+	var typeName string
+	if err := tx.QueryRowContext(ctx, `SELECT name FROM leave_types WHERE id=$1`, typeID).Scan(&typeName); err != nil { return err }
+
+	for _, day := range d.Days {
+		var entryID int64
+		action := domain.AuditUpdate
+		err := tx.QueryRowContext(ctx, `UPDATE timesheet_entries SET entry_type='leave', leave_type_id=$3, leave_request_id=$4,
+		                                  start_time=NULL, end_time=NULL, total_hours=NULL, overtime_hours=NULL, overtime_breakdown=NULL,
+		                                  ends_next_day=FALSE, night_hours=NULL, billable=FALSE, client_id=NULL, remarks=$5
+		                                WHERE id = (SELECT id FROM timesheet_entries WHERE timesheet_id=$1 AND work_date=$2 ORDER BY id LIMIT 1)
+		                                RETURNING id`, day.Timesheet.ID, day.Date, typeID, d.RequestID, typeName).Scan(&entryID)
+		if err == sql.ErrNoRows {
+			action = domain.AuditCreate
+			err = tx.QueryRowContext(ctx, `INSERT INTO timesheet_entries (timesheet_id, work_date, entry_type, leave_type_id, leave_request_id, remarks)
+			                               VALUES ($1,$2,'leave',$3,$4,$5) RETURNING id`, day.Timesheet.ID, day.Date, typeID, d.RequestID, typeName).Scan(&entryID)
+		}
+		if err != nil { return err }
+		if _, err := tx.ExecContext(ctx, `DELETE FROM entry_segments WHERE entry_id=$1`, entryID); err != nil { return err }
+		if _, err := tx.ExecContext(ctx, `DELETE FROM entry_allocations WHERE entry_id=$1`, entryID); err != nil { return err }
+
+		if d.Audit != nil {
+			ev := *d.Audit
+			ev.TimesheetID, ev.EntityID, ev.Action = day.Timesheet.ID, entryID, action
+			if err := insertAuditEvent(ctx, tx, &ev); err != nil { return err }
+		}
+	}
+	return nil
+}
+
+// ====== Balances ======
+
+func (r *LeaveRepoPG) Balances(employeeID int64, year int) ([]domain.LeaveBalance, error) {
+	q := `SELECT lt.id, lt.name, COALESCE(b.entitled, lt.annual_quota), COALESCE(b.used, 0)
+	      FROM leave_types lt
+	      LEFT JOIN leave_balances b ON b.leave_type_id = lt.id AND b.employee_id = $1 AND b.year = $2
+	      WHERE lt.annual_quota > 0 OR b.employee_id IS NOT NULL
+	      ORDER BY lt.code ASC`
+	rows, err := r.DB.Query(q, employeeID, year)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.LeaveBalance
+	for rows.Next() {
+		b := domain.LeaveBalance{EmployeeID: employeeID, Year: year}
+		if err := rows.Scan(&b.LeaveTypeID, &b.LeaveType, &b.Entitled, &b.Used); err != nil {
+			return nil, err
+		}
+		b.Remaining = b.Entitled - b.Used
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (r *LeaveRepoPG) SetEntitlement(b *domain.LeaveBalance) error {
+	_, err := r.DB.Exec(`INSERT INTO leave_balances (employee_id, leave_type_id, year, entitled) VALUES ($1,$2,$3,$4)
+	                     ON CONFLICT (employee_id, leave_type_id, year) DO UPDATE SET entitled = EXCLUDED.entitled`,
+		b.EmployeeID, b.LeaveTypeID, b.Year, b.Entitled)
+	return mapPGError(err)
+}
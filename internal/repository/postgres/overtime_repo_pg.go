@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type OvertimePolicyRepoPG struct {
+	DB *sql.DB
+}
+
+func NewOvertimePolicyRepoPG(db *sql.DB) *OvertimePolicyRepoPG { return &OvertimePolicyRepoPG{DB: db} }
+
+const overtimePolicyCols = `p.id, p.name, p.daily_threshold_hours, p.weekly_threshold_hours, p.overtime_multiplier,
+	p.weekend_multiplier, p.holiday_multiplier, p.break_minutes, p.break_after_hours,
+	to_char(p.night_start, 'HH24:MI'), to_char(p.night_end, 'HH24:MI'), p.is_default, p.created_at`
+
+func scanOvertimePolicy(row interface{ Scan(...interface{}) error }, p *domain.OvertimePolicy) error {
+	return row.Scan(&p.ID, &p.Name, &p.DailyThresholdHours, &p.WeeklyThresholdHours, &p.OvertimeMultiplier,
+		&p.WeekendMultiplier, &p.HolidayMultiplier, &p.BreakMinutes, &p.BreakAfterHours,
+		&p.NightStart, &p.NightEnd, &p.IsDefault, &p.CreatedAt)
+}
+
+func (r *OvertimePolicyRepoPG) Create(p *domain.OvertimePolicy) (int64, error) {
+	tx, err := r.DB.Begin()
+	if err != nil { return 0, err }
+	defer tx.Rollback()
+
+	if p.IsDefault {
+		if _, err := tx.Exec(`UPDATE overtime_policies SET is_default = FALSE WHERE is_default`); err != nil { return 0, err }
+	}
+	q := `INSERT INTO overtime_policies (name, daily_threshold_hours, weekly_threshold_hours, overtime_multiplier,
+	        weekend_multiplier, holiday_multiplier, break_minutes, break_after_hours, night_start, night_end, is_default)
+	      VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9::time,$10::time,$11) RETURNING id, created_at`
+	var created time.Time
+	if err := tx.QueryRow(q, p.Name, p.DailyThresholdHours, p.WeeklyThresholdHours, p.OvertimeMultiplier,
+		p.WeekendMultiplier, p.HolidayMultiplier, p.BreakMinutes, p.BreakAfterHours, p.NightStart, p.NightEnd, p.IsDefault).
+		Scan(&p.ID, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	p.CreatedAt = created
+	return p.ID, tx.Commit()
+}
+
+func (r *OvertimePolicyRepoPG) FindByID(id int64) (*domain.OvertimePolicy, error) {
+	var p domain.OvertimePolicy
+	err := scanOvertimePolicy(r.DB.QueryRow(`SELECT `+overtimePolicyCols+` FROM overtime_policies p WHERE p.id=$1`, id), &p)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *OvertimePolicyRepoPG) List() ([]domain.OvertimePolicy, error) {
+	rows, err := r.DB.Query(`SELECT ` + overtimePolicyCols + ` FROM overtime_policies p ORDER BY p.name ASC, p.id ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.OvertimePolicy
+	for rows.Next() {
+		var p domain.OvertimePolicy
+		if err := scanOvertimePolicy(rows, &p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *OvertimePolicyRepoPG) Update(p *domain.OvertimePolicy) error {
+	tx, err := r.DB.Begin()
+	if err != nil { return err }
+	defer tx.Rollback()
+
+	if p.IsDefault {
+		if _, err := tx.Exec(`UPDATE overtime_policies SET is_default = FALSE WHERE is_default AND id <> $1`, p.ID); err != nil { return err }
+	}
+	res, err := tx.Exec(`UPDATE overtime_policies SET name=$1, daily_threshold_hours=$2, weekly_threshold_hours=$3,
+	                       overtime_multiplier=$4, weekend_multiplier=$5, holiday_multiplier=$6, break_minutes=$7,
+	                       break_after_hours=$8, night_start=$9::time, night_end=$10::time, is_default=$11 WHERE id=$12`,
+		p.Name, p.DailyThresholdHours, p.WeeklyThresholdHours, p.OvertimeMultiplier, p.WeekendMultiplier,
+		p.HolidayMultiplier, p.BreakMinutes, p.BreakAfterHours, p.NightStart, p.NightEnd, p.IsDefault, p.ID)
+	if err != nil { return mapPGError(err) }
+	if err := mustAffect(res); err != nil { return err }
+	return tx.Commit()
+}
+
+func (r *OvertimePolicyRepoPG) Delete(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM overtime_policies WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *OvertimePolicyRepoPG) ForDepartment(departmentID *int64) (*domain.OvertimePolicy, error) {
+	q := `WITH RECURSIVE up AS (
+	        SELECT id, parent_id, overtime_policy_id, 0 AS depth FROM departments WHERE id = $1
+	        UNION ALL
+	        SELECT d.id, d.parent_id, d.overtime_policy_id, up.depth + 1 FROM departments d JOIN up ON d.id = up.parent_id
+	      )
+	      SELECT ` + overtimePolicyCols + ` FROM overtime_policies p
+	      WHERE p.id = (SELECT overtime_policy_id FROM up WHERE overtime_policy_id IS NOT NULL ORDER BY depth LIMIT 1)
+	         OR p.is_default
+	      ORDER BY p.is_default ASC LIMIT 1`
+	var p domain.OvertimePolicy
+	err := scanOvertimePolicy(r.DB.QueryRow(q, departmentID), &p)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// minPoolConns: Stream dengan entries memegang cursor timesheet di satu koneksi
+// sambil memuat entries di koneksi kedua; dengan satu koneksi ia menunggu
+// dirinya sendiri selamanya.
+const minPoolConns = 2
+
+// PoolConfig: ukuran & perilaku pool pgx. Nilai 0 = default pgxpool (atau
+// nilai pool_* di DSN).
+type PoolConfig struct {
+	MaxConns          int32 // minimal 2, lihat minPoolConns
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// StatementCache: jumlah prepared statement yang di-cache per koneksi.
+	// 0 = tanpa cache (describe tiap query), aman di belakang PgBouncer mode transaction.
+	StatementCache int
+	TimeZone       string // zona waktu sesi, mis. "Asia/Jakarta"; kosong = default server
+}
+
+// OpenPool membuka pgxpool untuk dsn dengan pengaturan pc dan memastikan
+// koneksinya hidup. Gagal jika max conns (dari pc atau DSN) kurang dari dua.
+func OpenPool(ctx context.Context, dsn string, pc PoolConfig) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil { return nil, err }
+	if pc.MaxConns > 0 { cfg.MaxConns = pc.MaxConns }
+	if pc.MinConns > 0 { cfg.MinConns = pc.MinConns }
+	if pc.MaxConnLifetime > 0 { cfg.MaxConnLifetime = pc.MaxConnLifetime }
+	if pc.MaxConnIdleTime > 0 { cfg.MaxConnIdleTime = pc.MaxConnIdleTime }
+	if pc.HealthCheckPeriod > 0 { cfg.HealthCheckPeriod = pc.HealthCheckPeriod }
+	if cfg.MaxConns < minPoolConns {
+		return nil, fmt.Errorf("pgxpool: max conns %d, minimal %d (export stream memakai dua koneksi sekaligus)", cfg.MaxConns, minPoolConns)
+	}
+
+	cc := cfg.ConnConfig
+	if pc.StatementCache > 0 {
+		cc.StatementCacheCapacity = pc.StatementCache
+		cc.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	} else {
+		cc.StatementCacheCapacity = 0
+		cc.DefaultQueryExecMode = pgx.QueryExecModeDescribeExec
+	}
+	if pc.TimeZone != "" { cc.RuntimeParams["timezone"] = pc.TimeZone }
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil { return nil, err }
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type ProjectRepoPG struct {
+	DB *sql.DB
+}
+
+func NewProjectRepoPG(db *sql.DB) *ProjectRepoPG { return &ProjectRepoPG{DB: db} }
+
+// ====== Projects ======
+
+const projectCols = `id, code, name, COALESCE(description, ''), client_id, active, created_at`
+
+func scanProject(row interface{ Scan(...interface{}) error }, p *domain.Project) error {
+	return row.Scan(&p.ID, &p.Code, &p.Name, &p.Description, &p.ClientID, &p.Active, &p.CreatedAt)
+}
+
+func (r *ProjectRepoPG) Create(p *domain.Project) (int64, error) {
+	q := `INSERT INTO projects (code, name, description, client_id, active) VALUES ($1,$2,NULLIF($3,''),$4,$5) RETURNING id, created_at`
+	var created time.Time
+	if err := r.DB.QueryRow(q, p.Code, p.Name, p.Description, p.ClientID, p.Active).Scan(&p.ID, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	p.CreatedAt = created
+	return p.ID, nil
+}
+
+func (r *ProjectRepoPG) FindByID(id int64) (*domain.Project, error) {
+	var p domain.Project
+	err := scanProject(r.DB.QueryRow(`SELECT `+projectCols+` FROM projects WHERE id=$1`, id), &p)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProjectRepoPG) List(activeOnly bool) ([]domain.Project, error) {
+	q := `SELECT ` + projectCols + ` FROM projects`
+	if activeOnly { q += ` WHERE active` }
+	rows, err := r.DB.Query(q + ` ORDER BY code ASC`)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Project
+	for rows.Next() {
+		var p domain.Project
+		if err := scanProject(rows, &p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *ProjectRepoPG) Update(p *domain.Project) error {
+	res, err := r.DB.Exec(`UPDATE projects SET code=$1, name=$2, description=NULLIF($3,''), client_id=$4, active=$5 WHERE id=$6`,
+		p.Code, p.Name, p.Description, p.ClientID, p.Active, p.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// Delete gagal dengan ErrInUse jika project (atau task-nya) sudah dipakai alokasi.
+func (r *ProjectRepoPG) Delete(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM projects WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// ====== Tasks ======
+
+const taskCols = `id, project_id, name, active, created_at`
+
+func scanTask(row interface{ Scan(...interface{}) error }, t *domain.Task) error {
+	return row.Scan(&t.ID, &t.ProjectID, &t.Name, &t.Active, &t.CreatedAt)
+}
+
+func (r *ProjectRepoPG) CreateTask(t *domain.Task) (int64, error) {
+	q := `INSERT INTO tasks (project_id, name, active) VALUES ($1,$2,$3) RETURNING id, created_at`
+	var created time.Time
+	if err := r.DB.QueryRow(q, t.ProjectID, t.Name, t.Active).Scan(&t.ID, &created); err != nil {
+		return 0, mapPGError(err)
+	}
+	t.CreatedAt = created
+	return t.ID, nil
+}
+
+func (r *ProjectRepoPG) FindTask(id int64) (*domain.Task, error) {
+	var t domain.Task
+	err := scanTask(r.DB.QueryRow(`SELECT `+taskCols+` FROM tasks WHERE id=$1`, id), &t)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *ProjectRepoPG) ListTasks(projectID int64) ([]domain.Task, error) {
+	rows, err := r.DB.Query(`SELECT `+taskCols+` FROM tasks WHERE project_id=$1 ORDER BY name ASC`, projectID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Task
+	for rows.Next() {
+		var t domain.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *ProjectRepoPG) UpdateTask(t *domain.Task) error {
+	res, err := r.DB.Exec(`UPDATE tasks SET name=$1, active=$2 WHERE id=$3`, t.Name, t.Active, t.ID)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+func (r *ProjectRepoPG) DeleteTask(id int64) error {
+	res, err := r.DB.Exec(`DELETE FROM tasks WHERE id=$1`, id)
+	if err != nil { return mapPGError(err) }
+	return mustAffect(res)
+}
+
+// ====== Report ======
+
+func (r *ProjectRepoPG) Hours(projectID int64, f repository.ProjectHoursFilter) ([]domain.ProjectHours, error) {
+	q := `SELECT t.employee_id, e.name, t.year, t.month, SUM(a.hours)
+	      FROM entry_allocations a
+	      JOIN timesheet_entries en ON en.id = a.entry_id
+	      JOIN timesheets t ON t.id = en.timesheet_id
+	      JOIN employees e ON e.id = t.employee_id
+	      WHERE a.project_id = $1`
+	args := []interface{}{projectID}
+	i := 2
+	if f.From != nil { q += fmt.Sprintf(" AND en.work_date >= $%d", i); args = append(args, *f.From); i++ }
+	if f.To != nil { q += fmt.Sprintf(" AND en.work_date <= $%d", i); args = append(args, *f.To); i++ }
+	if f.Scope != nil {
+		q += fmt.Sprintf(" AND (t.employee_id = $%d OR t.department_id = ANY($%d))", i, i+1)
+		args = append(args, f.Scope.EmployeeID, f.Scope.DepartmentIDs); i += 2
+	}
+	q += " GROUP BY t.employee_id, e.name, t.year, t.month ORDER BY t.year, t.month, e.name"
+
+	rows, err := r.DB.Query(q, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.ProjectHours
+	for rows.Next() {
+		var h domain.ProjectHours
+		if err := rows.Scan(&h.EmployeeID, &h.EmployeeName, &h.Year, &h.Month, &h.Hours); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type PunchRepoPG struct {
+	DB       *sql.DB
+	Timeouts QueryTimeouts // hanya Record; query baca punch tidak dibatasi
+}
+
+func NewPunchRepoPG(db *sql.DB) *PunchRepoPG { return &PunchRepoPG{DB: db} }
+
+// WithTimeouts memasang batas waktu query per operasi.
+func (r *PunchRepoPG) WithTimeouts(t QueryTimeouts) *PunchRepoPG {
+	r.Timeouts = t
+	return r
+}
+
+const punchCols = `id, employee_id, kind, punched_at, latitude, longitude, COALESCE(device, ''), COALESCE(ip, ''), auto, entry_id, created_at`
+
+func scanPunch(row interface{ Scan(...interface{}) error }, p *domain.Punch) error {
+	return row.Scan(&p.ID, &p.EmployeeID, &p.Kind, &p.PunchedAt, &p.Latitude, &p.Longitude, &p.Device, &p.IP, &p.Auto, &p.EntryID, &p.CreatedAt)
+}
+
+func (r *PunchRepoPG) LastOpen(employeeID int64) (*domain.Punch, error) {
+	var p domain.Punch
+	err := scanPunch(r.DB.QueryRow(`SELECT `+punchCols+` FROM punch_events WHERE employee_id=$1
+	                                ORDER BY punched_at DESC, id DESC LIMIT 1`, employeeID), &p)
+	if err == sql.ErrNoRows || (err == nil && p.Kind != domain.PunchIn) {
+		return nil, nil
+	}
+	if err != nil { return nil, err }
+	return &p, nil
+}
+
+func (r *PunchRepoPG) OpenBefore(t time.Time) ([]domain.Punch, error) {
+	q := `SELECT ` + punchCols + ` FROM (
+	        SELECT DISTINCT ON (employee_id) * FROM punch_events ORDER BY employee_id, punched_at DESC, id DESC
+	      ) last WHERE kind = 'in' AND punched_at < $1 ORDER BY punched_at ASC`
+	rows, err := r.DB.Query(q, t)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Punch
+	for rows.Next() {
+		var p domain.Punch
+		if err := scanPunch(rows, &p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *PunchRepoPG) Record(ctx context.Context, p *domain.Punch, e *domain.TimesheetEntry, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil { return err }
+	defer tx.Rollback()
+
+	// Serialisasi per karyawan supaya dua punch in bersamaan tidak sama-sama lolos.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('punch_events'), $1::int)`, p.EmployeeID); err != nil {
+		return err
+	}
+	var last domain.PunchKind
+	err = tx.QueryRowContext(ctx, `SELECT kind FROM punch_events WHERE employee_id=$1 ORDER BY punched_at DESC, id DESC LIMIT 1`, p.EmployeeID).Scan(&last)
+	if err != nil && err != sql.ErrNoRows { return err }
+	if p.Kind == domain.PunchIn && last == domain.PunchIn {
+		return fmt.Errorf("%w: masih punch in", domain.ErrInvalidTransition)
+	}
+	if p.Kind == domain.PunchOut && last != domain.PunchIn {
+		return fmt.Errorf("%w: belum punch in", domain.ErrInvalidTransition)
+	}
+
+	if e != nil {
+		action := domain.AuditUpdate
+		if e.ID == 0 {
+			action = domain.AuditCreate
+			err = insertEntry(ctx, tx, e)
+		} else {
+			err = updateEntry(ctx, tx, e)
+		}
+		if err != nil { return err }
+		p.EntryID = &e.ID
+		if ev != nil {
+			ev.TimesheetID, ev.EntityID, ev.Action = e.TimesheetID, e.ID, action
+			if err := insertAuditEvent(ctx, tx, ev); err != nil { return err }
+		}
+	}
+
+	q := `INSERT INTO punch_events (employee_id, kind, punched_at, latitude, longitude, device, ip, auto, entry_id)
+	      VALUES ($1,$2,$3,$4,$5,NULLIF($6,''),NULLIF($7,''),$8,$9) RETURNING id, created_at`
+	if err := tx.QueryRowContext(ctx, q, p.EmployeeID, p.Kind, p.PunchedAt, p.Latitude, p.Longitude, p.Device, p.IP, p.Auto, p.EntryID).
+		Scan(&p.ID, &p.CreatedAt); err != nil {
+		return mapPGError(err)
+	}
+	return tx.Commit()
+}
+
+func (r *PunchRepoPG) List(employeeID int64, from, to time.Time) ([]domain.Punch, error) {
+	rows, err := r.DB.Query(`SELECT `+punchCols+` FROM punch_events
+	                         WHERE employee_id=$1 AND punched_at >= $2 AND punched_at < $3
+	                         ORDER BY punched_at ASC, id ASC`, employeeID, from, to)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Punch
+	for rows.Next() {
+		var p domain.Punch
+		if err := scanPunch(rows, &p); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
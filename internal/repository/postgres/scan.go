@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// clockScanner men-scan kolom TIME ke *time.Time. Driver pgx (stdlib) mengembalikan
+// TIME sebagai string "15:04:05[.ffffff]", bukan time.Time, jadi sql.NullTime gagal.
+type clockScanner struct{ dst **time.Time }
+
+func (c clockScanner) Scan(v interface{}) error {
+	switch x := v.(type) {
+	case nil:
+		*c.dst = nil
+		return nil
+	case time.Time:
+		*c.dst = &x
+		return nil
+	case []byte:
+		return c.parse(string(x))
+	case string:
+		return c.parse(x)
+	}
+	return fmt.Errorf("clockScanner: tipe %T tidak didukung", v)
+}
+
+// jsonScanner meng-unmarshal kolom JSONB (dipilih sebagai ::text) ke dst.
+// dst berupa pointer ke pointer supaya NULL tetap nil.
+type jsonScanner struct{ dst interface{} }
+
+func (j jsonScanner) Scan(v interface{}) error {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(x, j.dst)
+	case string:
+		return json.Unmarshal([]byte(x), j.dst)
+	}
+	return fmt.Errorf("jsonScanner: tipe %T tidak didukung", v)
+}
+
+// jsonArg mengubah v jadi argumen JSONB (pakai $n::jsonb); pointer nil → NULL.
+func jsonArg(v interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil { return nil, err }
+	return string(b), nil
+}
+
+func (c clockScanner) parse(s string) error {
+	t, err := time.Parse("15:04:05.999999", s)
+	if err != nil { return err }
+	*c.dst = &t
+	return nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// queryer: *sql.DB maupun *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+const (
+	segmentsSQL = `SELECT id, entry_id, segment_type, start_time, end_time, starts_next_day, COALESCE(remarks, ''),
+	                      project_id, task_id
+	               FROM entry_segments WHERE entry_id = ANY($1) ORDER BY entry_id, starts_next_day, start_time, id`
+	deleteSegmentsSQL = `DELETE FROM entry_segments WHERE entry_id=$1`
+	insertSegmentSQL  = `INSERT INTO entry_segments (entry_id, segment_type, start_time, end_time, starts_next_day, remarks, project_id, task_id)
+	                     VALUES ($1,$2,$3,$4,$5,NULLIF($6,''),$7,$8) RETURNING id`
+
+	allocationsSQL = `SELECT id, entry_id, project_id, task_id, hours
+	                  FROM entry_allocations WHERE entry_id = ANY($1) ORDER BY entry_id, id`
+	deleteAllocationsSQL = `DELETE FROM entry_allocations WHERE entry_id=$1`
+	insertAllocationSQL  = `INSERT INTO entry_allocations (entry_id, project_id, task_id, hours) VALUES ($1,$2,$3,$4) RETURNING id`
+)
+
+// entryIndex: id semua entries (untuk ANY($1)) dan posisinya di slice.
+func entryIndex(entries []domain.TimesheetEntry) ([]int64, map[int64]int) {
+	ids := make([]int64, len(entries))
+	idx := make(map[int64]int, len(entries))
+	for i, e := range entries {
+		ids[i], idx[e.ID] = e.ID, i
+	}
+	return ids, idx
+}
+
+func scanSegment(row interface{ Scan(...interface{}) error }, s *domain.EntrySegment) error {
+	var start, end *time.Time
+	if err := row.Scan(&s.ID, &s.EntryID, &s.Type, clockScanner{&start}, clockScanner{&end}, &s.StartsNextDay, &s.Remarks,
+		&s.ProjectID, &s.TaskID); err != nil {
+		return err
+	}
+	s.StartTime, s.EndTime = *start, *end
+	return nil
+}
+
+func segmentArgs(entryID int64, s *domain.EntrySegment) []interface{} {
+	return []interface{}{entryID, s.Type, s.StartTime.Format("15:04:05"), s.EndTime.Format("15:04:05"), s.StartsNextDay, s.Remarks,
+		s.ProjectID, s.TaskID}
+}
+
+// loadSegments mengisi Segments untuk semua entries dalam satu query.
+func loadSegments(ctx context.Context, q queryer, entries []domain.TimesheetEntry) error {
+	if len(entries) == 0 { return nil }
+	ids, idx := entryIndex(entries)
+	rows, err := q.QueryContext(ctx, segmentsSQL, ids)
+	if err != nil { return err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.EntrySegment
+		if err := scanSegment(rows, &s); err != nil { return err }
+		e := &entries[idx[s.EntryID]]
+		e.Segments = append(e.Segments, s)
+	}
+	return rows.Err()
+}
+
+// replaceSegments mengganti semua segmen entry dengan e.Segments.
+func replaceSegments(ctx context.Context, tx *sql.Tx, e *domain.TimesheetEntry) error {
+	if _, err := tx.ExecContext(ctx, deleteSegmentsSQL, e.ID); err != nil { return err }
+	for i := range e.Segments {
+		s := &e.Segments[i]
+		s.EntryID = e.ID
+		if err := tx.QueryRowContext(ctx, insertSegmentSQL, segmentArgs(e.ID, s)...).Scan(&s.ID); err != nil { return mapPGError(err) }
+	}
+	return nil
+}
+
+// loadAllocations mengisi Allocations untuk semua entries dalam satu query.
+func loadAllocations(ctx context.Context, q queryer, entries []domain.TimesheetEntry) error {
+	if len(entries) == 0 { return nil }
+	ids, idx := entryIndex(entries)
+	rows, err := q.QueryContext(ctx, allocationsSQL, ids)
+	if err != nil { return err }
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.EntryAllocation
+		if err := rows.Scan(&a.ID, &a.EntryID, &a.ProjectID, &a.TaskID, &a.Hours); err != nil { return err }
+		e := &entries[idx[a.EntryID]]
+		e.Allocations = append(e.Allocations, a)
+	}
+	return rows.Err()
+}
+
+// replaceAllocations mengganti semua alokasi entry dengan e.Allocations.
+func replaceAllocations(ctx context.Context, tx *sql.Tx, e *domain.TimesheetEntry) error {
+	if _, err := tx.ExecContext(ctx, deleteAllocationsSQL, e.ID); err != nil { return err }
+	for i := range e.Allocations {
+		a := &e.Allocations[i]
+		a.EntryID = e.ID
+		err := tx.QueryRowContext(ctx, insertAllocationSQL, e.ID, a.ProjectID, a.TaskID, a.Hours).Scan(&a.ID)
+		if err != nil { return mapPGError(err) }
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// QueryTimeouts: batas waktu per operasi, di atas ctx request. 0 = tanpa
+// batas tambahan (hanya ctx request).
+type QueryTimeouts struct {
+	Read   time.Duration // FindByID, List, Count, History, Stats, ...
+	Write  time.Duration // Create, Update, Delete, ChangeStatus, entry (termasuk audit-nya)
+	Stream time.Duration // seluruh Stream, termasuk waktu menulis ke client
+}
+
+// withDeadline membatasi ctx dengan d. done wajib di-defer: melepas timer dan
+// mengganti *err yang terjadi karena deadline (dari d maupun ctx pemanggil)
+// dengan domain.ErrTimeout. Pembatalan biasa (client putus) dibiarkan apa adanya.
+func withDeadline(ctx context.Context, d time.Duration, err *error) (context.Context, func()) {
+	cancel := context.CancelFunc(func() {})
+	if d > 0 { ctx, cancel = context.WithTimeout(ctx, d) }
+	return ctx, func() {
+		defer cancel()
+		if *err == nil || errors.Is(*err, domain.ErrTimeout) { return }
+		if errors.Is(*err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			*err = fmt.Errorf("%w: %v", domain.ErrTimeout, *err)
+		}
+	}
+}
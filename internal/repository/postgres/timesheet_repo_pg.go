@@ -1,9 +1,10 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"timesheet-api/internal/domain"
@@ -11,35 +12,84 @@ import (
 )
 
 type TimesheetRepoPG struct {
-	DB *sql.DB
+	DB       *sql.DB
+	Timeouts QueryTimeouts
+	tx       *txState // non-nil: repo terikat transaksi TxManagerPG
 }
 
 func NewTimesheetRepoPG(db *sql.DB) *TimesheetRepoPG { return &TimesheetRepoPG{DB: db} }
 
-func (r *TimesheetRepoPG) Create(ts *domain.Timesheet) (int64, error) {
-	q := `INSERT INTO timesheets (employee_name, department, month, year, total_working_days)
-	      VALUES ($1,$2,$3,$4,$5) RETURNING id, created_at`
-	var id int64
+// WithTimeouts memasang batas waktu query per operasi.
+func (r *TimesheetRepoPG) WithTimeouts(t QueryTimeouts) *TimesheetRepoPG {
+	r.Timeouts = t
+	return r
+}
+
+// Nama karyawan & departemen selalu diambil dari employees, bukan disimpan di timesheets.
+const (
+	timesheetCols = `t.id, t.employee_id, e.name, t.department_id, COALESCE(d.name, ''), t.month, t.year, t.total_working_days,
+	                 t.status, t.status_changed_at, t.status_changed_by, t.created_at`
+	timesheetFrom = `FROM timesheets t JOIN employees e ON e.id = t.employee_id LEFT JOIN departments d ON d.id = t.department_id`
+)
+
+// Nama hari libur di-join dari holidays, tidak disimpan di entry.
+const (
+	entryCols = `en.id, en.timesheet_id, en.work_date, en.start_time, en.end_time, en.total_hours, en.overtime_hours,
+	             COALESCE(en.remarks, ''), en.entry_type, en.leave_type_id, en.leave_request_id,
+	             en.overtime_breakdown::text, COALESCE(h.name, ''), en.ends_next_day, en.night_hours, en.billable, en.client_id,
+	             en.flagged, COALESCE(en.flag_reason, ''), en.created_at`
+	entryFrom = `FROM timesheet_entries en LEFT JOIN holidays h ON h.holiday_date = en.work_date`
+)
+
+func scanEntry(row interface{ Scan(...interface{}) error }, e *domain.TimesheetEntry) error {
+	return row.Scan(&e.ID, &e.TimesheetID, &e.WorkDate, clockScanner{&e.StartTime}, clockScanner{&e.EndTime},
+		&e.TotalHours, &e.OvertimeHours, &e.Remarks, &e.Type, &e.LeaveTypeID, &e.LeaveRequestID,
+		jsonScanner{&e.Overtime}, &e.Holiday, &e.EndsNextDay, &e.NightHours, &e.Billable, &e.ClientID, &e.Flagged, &e.FlagReason, &e.CreatedAt)
+}
+
+func scanTimesheet(row interface{ Scan(...interface{}) error }, ts *domain.Timesheet) error {
+	return row.Scan(&ts.ID, &ts.EmployeeID, &ts.EmployeeName, &ts.DepartmentID, &ts.Department, &ts.Month, &ts.Year, &ts.TotalWorkingDays,
+		&ts.Status, &ts.StatusChangedAt, &ts.StatusChangedBy, &ts.CreatedAt)
+}
+
+func scanAuditEvent(row interface{ Scan(...interface{}) error }, ev *domain.AuditEvent) error {
+	var before, after string
+	if err := row.Scan(&ev.ID, &ev.TimesheetID, &ev.Entity, &ev.EntityID, &ev.Action, &ev.Actor, &ev.RequestID,
+		&before, &after, &ev.CreatedAt); err != nil {
+		return err
+	}
+	if before != "" { ev.Before = json.RawMessage(before) }
+	if after != "" { ev.After = json.RawMessage(after) }
+	return nil
+}
+
+func (r *TimesheetRepoPG) Create(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	err = r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		if err := insertTimesheet(ctx, tx, ts); err != nil { return err }
+		if ev != nil { ev.TimesheetID, ev.EntityID = ts.ID, ts.ID }
+		return nil
+	})
+	if err != nil { return 0, err }
+	return ts.ID, nil
+}
+
+func insertTimesheet(ctx context.Context, tx *sql.Tx, ts *domain.Timesheet) error {
 	var created time.Time
-	err := r.DB.QueryRow(q, ts.EmployeeName, ts.Department, ts.Month, ts.Year, ts.TotalWorkingDays).
-		Scan(&id, &created)
-	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "unique") {
-			return 0, domain.ErrDuplicate
-		}
-		return 0, err
+	if err := tx.QueryRowContext(ctx, insertTimesheetSQL, ts.EmployeeID, ts.DepartmentID, ts.Month, ts.Year, ts.TotalWorkingDays).
+		Scan(&ts.ID, &ts.Status, &created); err != nil {
+		return mapPGError(err)
 	}
-	ts.ID = id
 	ts.CreatedAt = created
-	return id, nil
+	return nil
 }
 
-func (r *TimesheetRepoPG) FindByID(id int64) (*domain.Timesheet, error) {
+func (r *TimesheetRepoPG) FindByID(ctx context.Context, id int64) (_ *domain.Timesheet, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
 	var ts domain.Timesheet
-	q := `SELECT id, employee_name, department, month, year, total_working_days, created_at
-	      FROM timesheets WHERE id=$1`
-	err := r.DB.QueryRow(q, id).
-		Scan(&ts.ID, &ts.EmployeeName, &ts.Department, &ts.Month, &ts.Year, &ts.TotalWorkingDays, &ts.CreatedAt)
+	err = scanTimesheet(r.conn().QueryRowContext(ctx, timesheetByIDSQL, id), &ts)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
 	}
@@ -47,113 +97,399 @@ func (r *TimesheetRepoPG) FindByID(id int64) (*domain.Timesheet, error) {
 		return nil, err
 	}
 
-	rows, err := r.DB.Query(`SELECT id, work_date, start_time, end_time, total_hours, overtime_hours, remarks, created_at
-	                         FROM timesheet_entries WHERE timesheet_id = $1 ORDER BY work_date ASC`, id)
+	rows, err := r.conn().QueryContext(ctx, entriesByTimesheetSQL, id)
 	if err != nil { return nil, err }
 	defer rows.Close()
 
 	var entries []domain.TimesheetEntry
 	for rows.Next() {
 		var e domain.TimesheetEntry
-		var st, et sql.NullTime
-		err := rows.Scan(&e.ID, &e.WorkDate, &st, &et, &e.TotalHours, &e.OvertimeHours, &e.Remarks, &e.CreatedAt)
-		if err != nil { return nil, err }
-		e.TimesheetID = id
-		if st.Valid { t := st.Time; e.StartTime = &t }
-		if et.Valid { t := et.Time; e.EndTime = &t }
+		if err := scanEntry(rows, &e); err != nil { return nil, err }
 		entries = append(entries, e)
 	}
+	if err := rows.Err(); err != nil { return nil, err }
+	if err := loadSegments(ctx, r.conn(), entries); err != nil { return nil, err }
+	if err := loadAllocations(ctx, r.conn(), entries); err != nil { return nil, err }
 	ts.Entries = entries
 	return &ts, nil
 }
 
-func (r *TimesheetRepoPG) List(f repository.Filter) ([]domain.Timesheet, error) {
-	q := `SELECT id, employee_name, department, month, year, total_working_days, created_at
-	      FROM timesheets WHERE 1=1`
-	var args []interface{}
-	i := 1
-	if f.EmployeeName != "" { q += fmt.Sprintf(" AND employee_name = $%d", i); args = append(args, f.EmployeeName); i++ }
-	if f.Month != nil { q += fmt.Sprintf(" AND month = $%d", i); args = append(args, *f.Month); i++ }
-	if f.Year  != nil { q += fmt.Sprintf(" AND year = $%d", i);  args = append(args, *f.Year);  i++ }
-	q += " ORDER BY year DESC, month DESC, id DESC"
-
-	rows, err := r.DB.Query(q, args...)
+func (r *TimesheetRepoPG) List(ctx context.Context, f repository.Filter) (_ []domain.Timesheet, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	q, args, err := listQuery(f)
+	if err != nil { return nil, err }
+	rows, err := r.conn().QueryContext(ctx, q, args...)
 	if err != nil { return nil, err }
 	defer rows.Close()
 
 	var out []domain.Timesheet
 	for rows.Next() {
 		var t domain.Timesheet
-		if err := rows.Scan(&t.ID, &t.EmployeeName, &t.Department, &t.Month, &t.Year, &t.TotalWorkingDays, &t.CreatedAt); err != nil {
+		if err := scanTimesheet(rows, &t); err != nil {
 			return nil, err
 		}
 		out = append(out, t)
 	}
-	return out, nil
+	return out, rows.Err()
 }
 
-func (r *TimesheetRepoPG) Update(ts *domain.Timesheet) error {
-	res, err := r.DB.Exec(`UPDATE timesheets SET employee_name=$1, department=$2, month=$3, year=$4, total_working_days=$5 WHERE id=$6`,
-		ts.EmployeeName, ts.Department, ts.Month, ts.Year, ts.TotalWorkingDays, ts.ID)
+// streamChunk: jumlah timesheet yang entries-nya dimuat sekaligus saat Stream
+// dengan entries, supaya memori tetap terbatas berapa pun jumlah hasilnya.
+const streamChunk = 200
+
+func (r *TimesheetRepoPG) Stream(ctx context.Context, f repository.Filter, withEntries bool, fn func(*domain.Timesheet) error) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Stream, &err)
+	defer done()
+	q, args, err := listQuery(f)
+	if err != nil { return err }
+	rows, err := r.DB.QueryContext(ctx, q, args...)
 	if err != nil { return err }
-	aff, _ := res.RowsAffected()
-	if aff == 0 { return domain.ErrNotFound }
+	defer rows.Close()
+
+	// Entries dimuat per potongan lewat koneksi lain selama cursor timesheet masih
+	// terbuka, jadi Stream selalu membaca lewat pool, juga pada repo yang terikat transaksi.
+	var chunk []domain.Timesheet
+	flush := func() error {
+		if withEntries {
+			if err := r.loadEntries(ctx, chunk); err != nil { return err }
+		}
+		for i := range chunk {
+			if err := fn(&chunk[i]); err != nil { return err }
+		}
+		chunk = chunk[:0]
+		return nil
+	}
+	for rows.Next() {
+		var t domain.Timesheet
+		if err := scanTimesheet(rows, &t); err != nil { return err }
+		chunk = append(chunk, t)
+		if !withEntries || len(chunk) == streamChunk {
+			if err := flush(); err != nil { return err }
+		}
+	}
+	if err := rows.Err(); err != nil { return err }
+	return flush()
+}
+
+// loadEntries mengisi Entries (beserta segmen & alokasinya) untuk beberapa
+// timesheet sekaligus.
+func (r *TimesheetRepoPG) loadEntries(ctx context.Context, items []domain.Timesheet) error {
+	if len(items) == 0 { return nil }
+	ids := make([]int64, len(items))
+	idx := make(map[int64]int, len(items))
+	for i, ts := range items {
+		ids[i], idx[ts.ID] = ts.ID, i
+	}
+	rows, err := r.DB.QueryContext(ctx, entriesByTimesheetsSQL, ids)
+	if err != nil { return err }
+	defer rows.Close()
+
+	var entries []domain.TimesheetEntry
+	for rows.Next() {
+		var e domain.TimesheetEntry
+		if err := scanEntry(rows, &e); err != nil { return err }
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil { return err }
+	if err := loadSegments(ctx, r.DB, entries); err != nil { return err }
+	if err := loadAllocations(ctx, r.DB, entries); err != nil { return err }
+	for _, e := range entries {
+		ts := &items[idx[e.TimesheetID]]
+		ts.Entries = append(ts.Entries, e)
+	}
 	return nil
 }
 
-func (r *TimesheetRepoPG) Delete(id int64) error {
-	res, err := r.DB.Exec(`DELETE FROM timesheets WHERE id=$1`, id)
+func (r *TimesheetRepoPG) Count(ctx context.Context, f repository.Filter) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	where, args := listWhere(f)
+	var n int64
+	err = r.conn().QueryRowContext(ctx, `SELECT COUNT(*) `+timesheetFrom+where, args...).Scan(&n)
+	return n, err
+}
+
+// sortCols: kolom SQL untuk repository.TimesheetSortFields.
+var sortCols = map[string]string{
+	"year": "t.year", "month": "t.month", "id": "t.id", "employee_name": "e.name", "department": "d.name",
+	"status": "t.status", "created_at": "t.created_at",
+}
+
+// listQuery: SELECT lengkap untuk List/Stream — filter, cursor, urutan, lalu LIMIT/OFFSET.
+func listQuery(f repository.Filter) (string, []interface{}, error) {
+	where, args := listWhere(f)
+	if f.Cursor != nil {
+		desc, ok := repository.KeysetSort(f.Sort)
+		if !ok { return "", nil, fmt.Errorf("%w: cursor hanya bisa dipakai dengan sort year,month,id", domain.ErrInvalidInput) }
+		op := ">"
+		if desc { op = "<" }
+		n := len(args)
+		where += fmt.Sprintf(" AND (t.year, t.month, t.id) %s ($%d, $%d, $%d)", op, n+1, n+2, n+3)
+		args = append(args, f.Cursor.Year, f.Cursor.Month, f.Cursor.ID)
+	}
+
+	order := " ORDER BY t.year DESC, t.month DESC, t.id DESC"
+	if len(f.Sort) > 0 {
+		order = " ORDER BY "
+		byID := false
+		for i, s := range f.Sort {
+			col, ok := sortCols[s.Field]
+			if !ok { return "", nil, fmt.Errorf("%w: sort %q tidak didukung", domain.ErrInvalidInput, s.Field) }
+			if i > 0 { order += ", " }
+			order += col
+			if s.Desc { order += " DESC" }
+			byID = byID || s.Field == "id"
+		}
+		if !byID { order += ", t.id" }
+	}
+
+	q := `SELECT ` + timesheetCols + ` ` + timesheetFrom + where + order
+	if f.PageSize > 0 {
+		q += fmt.Sprintf(" LIMIT %d", f.PageSize)
+		if f.Cursor == nil && f.Page > 1 { q += fmt.Sprintf(" OFFSET %d", (f.Page-1)*f.PageSize) }
+	}
+	return q, args, nil
+}
+
+// listWhere: klausa WHERE untuk List/Stream beserta argumennya.
+func listWhere(f repository.Filter) (string, []interface{}) {
+	q := ` WHERE 1=1`
+	var args []interface{}
+	i := 1
+	if f.EmployeeID != nil { q += fmt.Sprintf(" AND t.employee_id = $%d", i); args = append(args, *f.EmployeeID); i++ }
+	if f.EmployeeName != "" { q += fmt.Sprintf(" AND e.name = $%d", i); args = append(args, f.EmployeeName); i++ }
+	if f.Department != "" {
+		q += " AND t.department_id IN (" + fmt.Sprintf(subtreeSQL, fmt.Sprintf("lower(name) = lower($%d)", i)) + ")"
+		args = append(args, f.Department); i++
+	}
+	if f.DepartmentID != nil {
+		q += " AND t.department_id IN (" + fmt.Sprintf(subtreeSQL, fmt.Sprintf("id = $%d", i)) + ")"
+		args = append(args, *f.DepartmentID); i++
+	}
+	if f.Month != nil { q += fmt.Sprintf(" AND t.month = $%d", i); args = append(args, *f.Month); i++ }
+	if f.Year  != nil { q += fmt.Sprintf(" AND t.year = $%d", i);  args = append(args, *f.Year);  i++ }
+	if f.Scope != nil {
+		q += fmt.Sprintf(" AND (t.employee_id = $%d OR t.department_id = ANY($%d))", i, i+1)
+		args = append(args, f.Scope.EmployeeID, f.Scope.DepartmentIDs)
+	}
+	return q, args
+}
+
+func (r *TimesheetRepoPG) Update(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, updateTimesheetSQL,
+			ts.EmployeeID, ts.DepartmentID, ts.Month, ts.Year, ts.TotalWorkingDays, ts.ID)
+		if err != nil { return mapPGError(err) }
+		return mustAffect(res)
+	})
+}
+
+func (r *TimesheetRepoPG) Delete(ctx context.Context, id int64, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, deleteTimesheetSQL, id)
+		if err != nil { return err }
+		return mustAffect(res)
+	})
+}
+
+func (r *TimesheetRepoPG) ChangeStatus(ctx context.Context, ch *domain.StatusChange, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, changeStatusSQL, ch.ToStatus, ch.Actor, ch.TimesheetID, ch.FromStatus)
+		if err != nil { return err }
+		if aff, _ := res.RowsAffected(); aff == 0 {
+			var exists bool
+			if err := tx.QueryRowContext(ctx, timesheetExistsSQL, ch.TimesheetID).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists { return domain.ErrNotFound }
+			return domain.ErrInvalidTransition
+		}
+
+		return tx.QueryRowContext(ctx, insertStatusChangeSQL, ch.TimesheetID, ch.FromStatus, ch.ToStatus, ch.Actor, ch.Reason).Scan(&ch.ID, &ch.CreatedAt)
+	})
+}
+
+func (r *TimesheetRepoPG) History(ctx context.Context, timesheetID int64) (_ []domain.AuditEvent, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	rows, err := r.conn().QueryContext(ctx, auditEventsSQL, timesheetID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.AuditEvent
+	for rows.Next() {
+		var ev domain.AuditEvent
+		if err := scanAuditEvent(rows, &ev); err != nil { return nil, err }
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+// conn: transaksi jika repo terikat TxManagerPG, selain itu pool.
+func (r *TimesheetRepoPG) conn() dbtx {
+	if r.tx != nil { return r.tx.tx }
+	return r.DB
+}
+
+// withAudit menjalankan fn lalu menulis ev (jika ada) dalam satu transaksi,
+// jadi perubahan data dan audit-nya selalu tersimpan/gagal bersama. Di dalam
+// transaksi TxManagerPG keduanya dibungkus savepoint, jadi kegagalan di sini
+// tidak membatalkan seluruh transaksi luar.
+func (r *TimesheetRepoPG) withAudit(ctx context.Context, ev *domain.AuditEvent, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return r.tx.savepoint(ctx, func() error {
+			if err := fn(r.tx.tx); err != nil { return err }
+			if ev == nil { return nil }
+			return insertAuditEvent(ctx, r.tx.tx, ev)
+		})
+	}
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil { return err }
-	aff, _ := res.RowsAffected()
-	if aff == 0 { return domain.ErrNotFound }
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil { return err }
+	if ev != nil {
+		if err := insertAuditEvent(ctx, tx, ev); err != nil { return err }
+	}
+	return tx.Commit()
+}
+
+func insertAuditEvent(ctx context.Context, tx *sql.Tx, ev *domain.AuditEvent) error {
+	return tx.QueryRowContext(ctx, insertAuditEventSQL, auditEventArgs(ev)...).Scan(&ev.ID, &ev.CreatedAt)
+}
+
+func mustAffect(res sql.Result) error {
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return domain.ErrNotFound
+	}
 	return nil
 }
 
-func (r *TimesheetRepoPG) AddEntry(e *domain.TimesheetEntry) (int64, error) {
-	q := `INSERT INTO timesheet_entries (timesheet_id, work_date, start_time, end_time, total_hours, overtime_hours, remarks)
-	      VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id, created_at`
-	var id int64
-	var created time.Time
-	err := r.DB.QueryRow(q, e.TimesheetID, e.WorkDate, e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Remarks).
-		Scan(&id, &created)
+func (r *TimesheetRepoPG) StatusHistory(ctx context.Context, timesheetID int64) (_ []domain.StatusChange, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	rows, err := r.conn().QueryContext(ctx, statusHistorySQL, timesheetID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.StatusChange
+	for rows.Next() {
+		var ch domain.StatusChange
+		if err := rows.Scan(&ch.ID, &ch.TimesheetID, &ch.FromStatus, &ch.ToStatus, &ch.Actor, &ch.Reason, &ch.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, ch)
+	}
+	return out, rows.Err()
+}
+
+func (r *TimesheetRepoPG) FindEntryByID(ctx context.Context, id int64) (_ *domain.TimesheetEntry, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var e domain.TimesheetEntry
+	err = scanEntry(r.conn().QueryRowContext(ctx, entryByIDSQL, id), &e)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := []domain.TimesheetEntry{e}
+	if err := loadSegments(ctx, r.conn(), entries); err != nil { return nil, err }
+	if err := loadAllocations(ctx, r.conn(), entries); err != nil { return nil, err }
+	return &entries[0], nil
+}
+
+func (r *TimesheetRepoPG) AddEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	err = r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		if err := insertEntry(ctx, tx, e); err != nil { return err }
+		if ev != nil { ev.EntityID = e.ID }
+		return nil
+	})
 	if err != nil { return 0, err }
-	e.ID = id
-	e.CreatedAt = created
-	return id, nil
+	return e.ID, nil
+}
+
+func (r *TimesheetRepoPG) AddEntries(ctx context.Context, items []repository.NewEntry) (err error) {
+	if len(items) == 0 { return nil }
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, nil, func(tx *sql.Tx) error {
+		for _, it := range items {
+			if err := insertEntry(ctx, tx, it.Entry); err != nil { return err }
+			if it.Audit == nil { continue }
+			it.Audit.EntityID = it.Entry.ID
+			if err := insertAuditEvent(ctx, tx, it.Audit); err != nil { return err }
+		}
+		return nil
+	})
 }
 
-func (r *TimesheetRepoPG) UpdateEntry(e *domain.TimesheetEntry) error {
-	q := `UPDATE timesheet_entries
-	      SET work_date=$1, start_time=$2, end_time=$3, total_hours=$4, overtime_hours=$5, remarks=$6
-	      WHERE id=$7`
-	res, err := r.DB.Exec(q, e.WorkDate, e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Remarks, e.ID)
+func (r *TimesheetRepoPG) UpdateEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx *sql.Tx) error { return updateEntry(ctx, tx, e) })
+}
+
+// insertEntry & updateEntry dipakai juga oleh repository lain yang menulis
+// entry di dalam transaksinya sendiri (mis. punch).
+func insertEntry(ctx context.Context, tx *sql.Tx, e *domain.TimesheetEntry) error {
+	args, err := insertEntryArgs(e)
 	if err != nil { return err }
-	aff, _ := res.RowsAffected()
-	if aff == 0 { return domain.ErrNotFound }
-	return nil
+	if err := tx.QueryRowContext(ctx, insertEntrySQL, args...).Scan(&e.ID, &e.CreatedAt); err != nil { return mapPGError(err) }
+	if err := replaceSegments(ctx, tx, e); err != nil { return err }
+	return replaceAllocations(ctx, tx, e)
 }
 
-func (r *TimesheetRepoPG) DeleteEntry(id int64) error {
-	res, err := r.DB.Exec(`DELETE FROM timesheet_entries WHERE id=$1`, id)
+func updateEntry(ctx context.Context, tx *sql.Tx, e *domain.TimesheetEntry) error {
+	args, err := updateEntryArgs(e)
 	if err != nil { return err }
-	aff, _ := res.RowsAffected()
-	if aff == 0 { return domain.ErrNotFound }
-	return nil
+	res, err := tx.ExecContext(ctx, updateEntrySQL, args...)
+	if err != nil { return mapPGError(err) }
+	if err := mustAffect(res); err != nil { return err }
+	if err := replaceSegments(ctx, tx, e); err != nil { return err }
+	return replaceAllocations(ctx, tx, e)
+}
+
+func (r *TimesheetRepoPG) DeleteEntry(ctx context.Context, id int64, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, deleteEntrySQL, id)
+		if err != nil { return err }
+		return mustAffect(res)
+	})
+}
+
+func (r *TimesheetRepoPG) WeekRegularHours(ctx context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (_ float64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var h float64
+	if err := r.conn().QueryRowContext(ctx, weekRegularHoursSQL, employeeID, from, before, excludeEntryID).Scan(&h); err != nil {
+		return 0, err
+	}
+	return h, nil
 }
 
-func (r *TimesheetRepoPG) Stats(timesheetID int64) (int64, float64, float64, error) {
-	q := `
-	  SELECT
-	    COUNT(*) FILTER (WHERE total_hours IS NOT NULL OR start_time IS NOT NULL OR end_time IS NOT NULL) AS days_filled,
-	    COALESCE(SUM(total_hours), 0)   AS total_hours,
-	    COALESCE(SUM(overtime_hours),0) AS overtime_hours
-	  FROM timesheet_entries
-	  WHERE timesheet_id = $1
-	`
-	var days int64
-	var th, oh float64
-	if err := r.DB.QueryRow(q, timesheetID).Scan(&days, &th, &oh); err != nil {
-		return 0, 0, 0, err
-	}
-	return days, th, oh, nil
+func (r *TimesheetRepoPG) Stats(ctx context.Context, timesheetID int64) (_ *domain.TimesheetStats, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var st domain.TimesheetStats
+	err = r.conn().QueryRowContext(ctx, statsSQL, timesheetID).Scan(&st.DaysFilled, &st.WorkedDays, &st.LeaveDays, &st.AbsentDays, &st.TotalHours, &st.OvertimeHours, &st.NightHours)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
 }
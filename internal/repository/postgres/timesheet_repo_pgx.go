@@ -0,0 +1,489 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// TimesheetRepoPGX: TimesheetRepository langsung di atas pgxpool (tanpa
+// database/sql). SQL-nya sama dengan TimesheetRepoPG; bedanya AddEntries
+// menulis lewat COPY dan statement di-cache per koneksi (lihat PoolConfig).
+type TimesheetRepoPGX struct {
+	Pool     *pgxpool.Pool
+	Timeouts QueryTimeouts
+	tx       pgx.Tx // non-nil: repo terikat transaksi TxManagerPGX
+}
+
+func NewTimesheetRepoPGX(pool *pgxpool.Pool) *TimesheetRepoPGX { return &TimesheetRepoPGX{Pool: pool} }
+
+// WithTimeouts memasang batas waktu query per operasi.
+func (r *TimesheetRepoPGX) WithTimeouts(t QueryTimeouts) *TimesheetRepoPGX {
+	r.Timeouts = t
+	return r
+}
+
+// pgxConn: operasi yang sama di *pgxpool.Pool dan pgx.Tx.
+type pgxConn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// conn: transaksi jika repo terikat TxManagerPGX, selain itu pool.
+func (r *TimesheetRepoPGX) conn() pgxConn {
+	if r.tx != nil { return r.tx }
+	return r.Pool
+}
+
+// withAudit: sama dengan TimesheetRepoPG.withAudit. Di dalam transaksi
+// TxManagerPGX, Begin pada pgx.Tx membuat savepoint.
+func (r *TimesheetRepoPGX) withAudit(ctx context.Context, ev *domain.AuditEvent, fn func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, r.conn(), func(tx pgx.Tx) error {
+		if err := fn(tx); err != nil { return err }
+		if ev == nil { return nil }
+		return pgxInsertAuditEvent(ctx, tx, ev)
+	})
+}
+
+func (r *TimesheetRepoPGX) Create(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	err = r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, insertTimesheetSQL, ts.EmployeeID, ts.DepartmentID, ts.Month, ts.Year, ts.TotalWorkingDays).
+			Scan(&ts.ID, &ts.Status, &ts.CreatedAt)
+		if err != nil { return mapPGError(err) }
+		if ev != nil { ev.TimesheetID, ev.EntityID = ts.ID, ts.ID }
+		return nil
+	})
+	if err != nil { return 0, err }
+	return ts.ID, nil
+}
+
+func (r *TimesheetRepoPGX) FindByID(ctx context.Context, id int64) (_ *domain.Timesheet, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var ts domain.Timesheet
+	err = scanTimesheet(r.conn().QueryRow(ctx, timesheetByIDSQL, id), &ts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries, err := pgxQueryEntries(ctx, r.conn(), entriesByTimesheetSQL, id)
+	if err != nil { return nil, err }
+	ts.Entries = entries
+	return &ts, nil
+}
+
+func (r *TimesheetRepoPGX) List(ctx context.Context, f repository.Filter) (_ []domain.Timesheet, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	q, args, err := listQuery(f)
+	if err != nil { return nil, err }
+	rows, err := r.conn().Query(ctx, q, args...)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.Timesheet
+	for rows.Next() {
+		var t domain.Timesheet
+		if err := scanTimesheet(rows, &t); err != nil { return nil, err }
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TimesheetRepoPGX) Stream(ctx context.Context, f repository.Filter, withEntries bool, fn func(*domain.Timesheet) error) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Stream, &err)
+	defer done()
+	q, args, err := listQuery(f)
+	if err != nil { return err }
+	rows, err := r.Pool.Query(ctx, q, args...)
+	if err != nil { return err }
+	defer rows.Close()
+
+	// Seperti TimesheetRepoPG.Stream: entries dimuat lewat koneksi lain dari pool,
+	// karena itu OpenPool menolak pool dengan kurang dari dua koneksi.
+	var chunk []domain.Timesheet
+	flush := func() error {
+		if withEntries {
+			if err := r.loadEntries(ctx, chunk); err != nil { return err }
+		}
+		for i := range chunk {
+			if err := fn(&chunk[i]); err != nil { return err }
+		}
+		chunk = chunk[:0]
+		return nil
+	}
+	for rows.Next() {
+		var t domain.Timesheet
+		if err := scanTimesheet(rows, &t); err != nil { return err }
+		chunk = append(chunk, t)
+		if !withEntries || len(chunk) == streamChunk {
+			if err := flush(); err != nil { return err }
+		}
+	}
+	if err := rows.Err(); err != nil { return err }
+	return flush()
+}
+
+func (r *TimesheetRepoPGX) loadEntries(ctx context.Context, items []domain.Timesheet) error {
+	if len(items) == 0 { return nil }
+	ids := make([]int64, len(items))
+	idx := make(map[int64]int, len(items))
+	for i, ts := range items {
+		ids[i], idx[ts.ID] = ts.ID, i
+	}
+	entries, err := pgxQueryEntries(ctx, r.Pool, entriesByTimesheetsSQL, ids)
+	if err != nil { return err }
+	for _, e := range entries {
+		ts := &items[idx[e.TimesheetID]]
+		ts.Entries = append(ts.Entries, e)
+	}
+	return nil
+}
+
+func (r *TimesheetRepoPGX) Count(ctx context.Context, f repository.Filter) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	where, args := listWhere(f)
+	var n int64
+	err = r.conn().QueryRow(ctx, `SELECT COUNT(*) `+timesheetFrom+where, args...).Scan(&n)
+	return n, err
+}
+
+func (r *TimesheetRepoPGX) Update(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, updateTimesheetSQL, ts.EmployeeID, ts.DepartmentID, ts.Month, ts.Year, ts.TotalWorkingDays, ts.ID)
+		if err != nil { return mapPGError(err) }
+		return mustAffectTag(tag)
+	})
+}
+
+func (r *TimesheetRepoPGX) Delete(ctx context.Context, id int64, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, deleteTimesheetSQL, id)
+		if err != nil { return err }
+		return mustAffectTag(tag)
+	})
+}
+
+func (r *TimesheetRepoPGX) ChangeStatus(ctx context.Context, ch *domain.StatusChange, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, changeStatusSQL, ch.ToStatus, ch.Actor, ch.TimesheetID, ch.FromStatus)
+		if err != nil { return err }
+		if tag.RowsAffected() == 0 {
+			var exists bool
+			if err := tx.QueryRow(ctx, timesheetExistsSQL, ch.TimesheetID).Scan(&exists); err != nil { return err }
+			if !exists { return domain.ErrNotFound }
+			return domain.ErrInvalidTransition
+		}
+		return tx.QueryRow(ctx, insertStatusChangeSQL, ch.TimesheetID, ch.FromStatus, ch.ToStatus, ch.Actor, ch.Reason).Scan(&ch.ID, &ch.CreatedAt)
+	})
+}
+
+func (r *TimesheetRepoPGX) StatusHistory(ctx context.Context, timesheetID int64) (_ []domain.StatusChange, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	rows, err := r.conn().Query(ctx, statusHistorySQL, timesheetID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.StatusChange
+	for rows.Next() {
+		var ch domain.StatusChange
+		if err := rows.Scan(&ch.ID, &ch.TimesheetID, &ch.FromStatus, &ch.ToStatus, &ch.Actor, &ch.Reason, &ch.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, ch)
+	}
+	return out, rows.Err()
+}
+
+func (r *TimesheetRepoPGX) History(ctx context.Context, timesheetID int64) (_ []domain.AuditEvent, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	rows, err := r.conn().Query(ctx, auditEventsSQL, timesheetID)
+	if err != nil { return nil, err }
+	defer rows.Close()
+
+	var out []domain.AuditEvent
+	for rows.Next() {
+		var ev domain.AuditEvent
+		if err := scanAuditEvent(rows, &ev); err != nil { return nil, err }
+		out = append(out, ev)
+	}
+	return out, rows.Err()
+}
+
+func (r *TimesheetRepoPGX) FindEntryByID(ctx context.Context, id int64) (_ *domain.TimesheetEntry, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	entries, err := pgxQueryEntries(ctx, r.conn(), entryByIDSQL, id)
+	if err != nil { return nil, err }
+	if len(entries) == 0 { return nil, domain.ErrNotFound }
+	return &entries[0], nil
+}
+
+func (r *TimesheetRepoPGX) AddEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (_ int64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	err = r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		args, err := insertEntryArgs(e)
+		if err != nil { return err }
+		if err := tx.QueryRow(ctx, insertEntrySQL, args...).Scan(&e.ID, &e.CreatedAt); err != nil { return mapPGError(err) }
+		if ev != nil { ev.EntityID = e.ID }
+		return pgxReplaceChildren(ctx, tx, e)
+	})
+	if err != nil { return 0, err }
+	return e.ID, nil
+}
+
+// AddEntries menulis entries, segmen, alokasi dan audit-nya masing-masing
+// dengan satu COPY per tabel. id diambil dulu dari sequence supaya baris anak
+// bisa merujuk entry-nya tanpa RETURNING.
+func (r *TimesheetRepoPGX) AddEntries(ctx context.Context, items []repository.NewEntry) (err error) {
+	if len(items) == 0 { return nil }
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, nil, func(tx pgx.Tx) error {
+		ids, now, err := reserveIDs(ctx, tx, "timesheet_entries", len(items))
+		if err != nil { return err }
+		entries := make([][]interface{}, len(items))
+		var segs []*domain.EntrySegment
+		var allocs []*domain.EntryAllocation
+		var events []*domain.AuditEvent
+		for i, it := range items {
+			e := it.Entry
+			e.ID, e.CreatedAt = ids[i], now
+			breakdown, err := jsonArg(e.Overtime)
+			if err != nil { return err }
+			entries[i] = []interface{}{e.ID, e.TimesheetID, e.WorkDate, e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Remarks,
+				e.Type, e.LeaveTypeID, breakdown, e.Flagged, nullIfEmpty(e.FlagReason), e.EndsNextDay, e.NightHours, e.Billable, e.ClientID}
+			for j := range e.Segments {
+				e.Segments[j].EntryID = e.ID
+				segs = append(segs, &e.Segments[j])
+			}
+			for j := range e.Allocations {
+				e.Allocations[j].EntryID = e.ID
+				allocs = append(allocs, &e.Allocations[j])
+			}
+			if it.Audit != nil {
+				it.Audit.EntityID = e.ID
+				events = append(events, it.Audit)
+			}
+		}
+		if err := copyRows(ctx, tx, "timesheet_entries", []string{"id", "timesheet_id", "work_date", "start_time", "end_time",
+			"total_hours", "overtime_hours", "remarks", "entry_type", "leave_type_id", "overtime_breakdown", "flagged", "flag_reason",
+			"ends_next_day", "night_hours", "billable", "client_id"}, entries); err != nil {
+			return err
+		}
+		if err := copySegments(ctx, tx, segs); err != nil { return err }
+		if err := copyAllocations(ctx, tx, allocs); err != nil { return err }
+		return copyAuditEvents(ctx, tx, events)
+	})
+}
+
+func (r *TimesheetRepoPGX) UpdateEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		args, err := updateEntryArgs(e)
+		if err != nil { return err }
+		tag, err := tx.Exec(ctx, updateEntrySQL, args...)
+		if err != nil { return mapPGError(err) }
+		if err := mustAffectTag(tag); err != nil { return err }
+		return pgxReplaceChildren(ctx, tx, e)
+	})
+}
+
+func (r *TimesheetRepoPGX) DeleteEntry(ctx context.Context, id int64, ev *domain.AuditEvent) (err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Write, &err)
+	defer done()
+	return r.withAudit(ctx, ev, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, deleteEntrySQL, id)
+		if err != nil { return err }
+		return mustAffectTag(tag)
+	})
+}
+
+func (r *TimesheetRepoPGX) WeekRegularHours(ctx context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (_ float64, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var h float64
+	if err := r.conn().QueryRow(ctx, weekRegularHoursSQL, employeeID, from, before, excludeEntryID).Scan(&h); err != nil {
+		return 0, err
+	}
+	return h, nil
+}
+
+func (r *TimesheetRepoPGX) Stats(ctx context.Context, timesheetID int64) (_ *domain.TimesheetStats, err error) {
+	ctx, done := withDeadline(ctx, r.Timeouts.Read, &err)
+	defer done()
+	var st domain.TimesheetStats
+	err = r.conn().QueryRow(ctx, statsSQL, timesheetID).Scan(&st.DaysFilled, &st.WorkedDays, &st.LeaveDays, &st.AbsentDays, &st.TotalHours, &st.OvertimeHours, &st.NightHours)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func mustAffectTag(tag pgconn.CommandTag) error {
+	if tag.RowsAffected() == 0 { return domain.ErrNotFound }
+	return nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" { return nil }
+	return s
+}
+
+func pgxInsertAuditEvent(ctx context.Context, tx pgx.Tx, ev *domain.AuditEvent) error {
+	return tx.QueryRow(ctx, insertAuditEventSQL, auditEventArgs(ev)...).Scan(&ev.ID, &ev.CreatedAt)
+}
+
+// pgxQueryEntries menjalankan query entry (entryCols) lalu memuat segmen &
+// alokasinya.
+func pgxQueryEntries(ctx context.Context, c pgxConn, q string, args ...interface{}) ([]domain.TimesheetEntry, error) {
+	rows, err := c.Query(ctx, q, args...)
+	if err != nil { return nil, err }
+	var entries []domain.TimesheetEntry
+	for rows.Next() {
+		var e domain.TimesheetEntry
+		if err := scanEntry(rows, &e); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil { return nil, err }
+	if len(entries) == 0 { return nil, nil }
+
+	ids, idx := entryIndex(entries)
+	rows, err = c.Query(ctx, segmentsSQL, ids)
+	if err != nil { return nil, err }
+	for rows.Next() {
+		var s domain.EntrySegment
+		if err := scanSegment(rows, &s); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		e := &entries[idx[s.EntryID]]
+		e.Segments = append(e.Segments, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil { return nil, err }
+
+	rows, err = c.Query(ctx, allocationsSQL, ids)
+	if err != nil { return nil, err }
+	defer rows.Close()
+	for rows.Next() {
+		var a domain.EntryAllocation
+		if err := rows.Scan(&a.ID, &a.EntryID, &a.ProjectID, &a.TaskID, &a.Hours); err != nil { return nil, err }
+		e := &entries[idx[a.EntryID]]
+		e.Allocations = append(e.Allocations, a)
+	}
+	return entries, rows.Err()
+}
+
+// pgxReplaceChildren: replaceSegments + replaceAllocations versi pgx.
+func pgxReplaceChildren(ctx context.Context, tx pgx.Tx, e *domain.TimesheetEntry) error {
+	if _, err := tx.Exec(ctx, deleteSegmentsSQL, e.ID); err != nil { return err }
+	for i := range e.Segments {
+		s := &e.Segments[i]
+		s.EntryID = e.ID
+		if err := tx.QueryRow(ctx, insertSegmentSQL, segmentArgs(e.ID, s)...).Scan(&s.ID); err != nil { return mapPGError(err) }
+	}
+	if _, err := tx.Exec(ctx, deleteAllocationsSQL, e.ID); err != nil { return err }
+	for i := range e.Allocations {
+		a := &e.Allocations[i]
+		a.EntryID = e.ID
+		if err := tx.QueryRow(ctx, insertAllocationSQL, e.ID, a.ProjectID, a.TaskID, a.Hours).Scan(&a.ID); err != nil {
+			return mapPGError(err)
+		}
+	}
+	return nil
+}
+
+// reserveIDs mengambil n id berikutnya dari sequence kolom id table, beserta
+// now() transaksi (sama dengan DEFAULT created_at baris yang di-COPY).
+func reserveIDs(ctx context.Context, tx pgx.Tx, table string, n int) ([]int64, time.Time, error) {
+	rows, err := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence($1, 'id')), now() FROM generate_series(1, $2)`, table, n)
+	if err != nil { return nil, time.Time{}, err }
+	defer rows.Close()
+	ids := make([]int64, 0, n)
+	var now time.Time
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id, &now); err != nil { return nil, time.Time{}, err }
+		ids = append(ids, id)
+	}
+	return ids, now, rows.Err()
+}
+
+func copyRows(ctx context.Context, tx pgx.Tx, table string, cols []string, rows [][]interface{}) error {
+	if len(rows) == 0 { return nil }
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{table}, cols, pgx.CopyFromRows(rows)); err != nil { return mapPGError(err) }
+	return nil
+}
+
+// COPY memakai format biner, jadi kolom TIME dikirim sebagai time.Time (bukan
+// string "15:04:05" seperti pada INSERT).
+func copySegments(ctx context.Context, tx pgx.Tx, segs []*domain.EntrySegment) error {
+	if len(segs) == 0 { return nil }
+	ids, _, err := reserveIDs(ctx, tx, "entry_segments", len(segs))
+	if err != nil { return err }
+	rows := make([][]interface{}, len(segs))
+	for i, s := range segs {
+		s.ID = ids[i]
+		rows[i] = []interface{}{s.ID, s.EntryID, s.Type, s.StartTime, s.EndTime, s.StartsNextDay, nullIfEmpty(s.Remarks), s.ProjectID, s.TaskID}
+	}
+	return copyRows(ctx, tx, "entry_segments",
+		[]string{"id", "entry_id", "segment_type", "start_time", "end_time", "starts_next_day", "remarks", "project_id", "task_id"}, rows)
+}
+
+func copyAllocations(ctx context.Context, tx pgx.Tx, allocs []*domain.EntryAllocation) error {
+	if len(allocs) == 0 { return nil }
+	ids, _, err := reserveIDs(ctx, tx, "entry_allocations", len(allocs))
+	if err != nil { return err }
+	rows := make([][]interface{}, len(allocs))
+	for i, a := range allocs {
+		a.ID = ids[i]
+		rows[i] = []interface{}{a.ID, a.EntryID, a.ProjectID, a.TaskID, a.Hours}
+	}
+	return copyRows(ctx, tx, "entry_allocations", []string{"id", "entry_id", "project_id", "task_id", "hours"}, rows)
+}
+
+func copyAuditEvents(ctx context.Context, tx pgx.Tx, events []*domain.AuditEvent) error {
+	if len(events) == 0 { return nil }
+	ids, now, err := reserveIDs(ctx, tx, "audit_events", len(events))
+	if err != nil { return err }
+	rows := make([][]interface{}, len(events))
+	for i, ev := range events {
+		ev.ID, ev.CreatedAt = ids[i], now
+		rows[i] = []interface{}{ev.ID, ev.TimesheetID, ev.Entity, ev.EntityID, ev.Action, ev.Actor, nullIfEmpty(ev.RequestID),
+			nullIfEmpty(string(ev.Before)), nullIfEmpty(string(ev.After))}
+	}
+	return copyRows(ctx, tx, "audit_events",
+		[]string{"id", "timesheet_id", "entity", "entity_id", "action", "actor", "request_id", "before", "after"}, rows)
+}
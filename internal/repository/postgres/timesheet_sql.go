@@ -0,0 +1,102 @@
+package postgres
+
+import "timesheet-api/internal/domain"
+
+// SQL & argumen yang dipakai bersama oleh TimesheetRepoPG (database/sql) dan
+// TimesheetRepoPGX (pgxpool), supaya kedua implementasi tidak bisa berbeda perilaku.
+const (
+	timesheetByIDSQL       = `SELECT ` + timesheetCols + ` ` + timesheetFrom + ` WHERE t.id=$1`
+	entryByIDSQL           = `SELECT ` + entryCols + ` ` + entryFrom + ` WHERE en.id=$1`
+	entriesByTimesheetSQL  = `SELECT ` + entryCols + ` ` + entryFrom + ` WHERE en.timesheet_id = $1 ORDER BY en.work_date ASC`
+	entriesByTimesheetsSQL = `SELECT ` + entryCols + ` ` + entryFrom + ` WHERE en.timesheet_id = ANY($1) ORDER BY en.timesheet_id, en.work_date ASC`
+
+	insertTimesheetSQL = `INSERT INTO timesheets (employee_id, department_id, month, year, total_working_days)
+	                      VALUES ($1,$2,$3,$4,$5) RETURNING id, status, created_at`
+	updateTimesheetSQL = `UPDATE timesheets SET employee_id=$1, department_id=$2, month=$3, year=$4, total_working_days=$5 WHERE id=$6`
+	deleteTimesheetSQL = `DELETE FROM timesheets WHERE id=$1`
+	timesheetExistsSQL = `SELECT EXISTS (SELECT 1 FROM timesheets WHERE id=$1)`
+
+	changeStatusSQL = `UPDATE timesheets SET status=$1, status_changed_at=NOW(), status_changed_by=$2
+	                   WHERE id=$3 AND status=$4`
+	insertStatusChangeSQL = `INSERT INTO timesheet_status_history (timesheet_id, from_status, to_status, actor, reason)
+	                         VALUES ($1,$2,$3,$4,NULLIF($5,'')) RETURNING id, created_at`
+	statusHistorySQL = `SELECT id, timesheet_id, from_status, to_status, actor, COALESCE(reason, ''), created_at
+	                    FROM timesheet_status_history WHERE timesheet_id=$1 ORDER BY created_at ASC, id ASC`
+
+	insertAuditEventSQL = `INSERT INTO audit_events (timesheet_id, entity, entity_id, action, actor, request_id, before, after)
+	                       VALUES ($1,$2,$3,$4,$5,NULLIF($6,''),NULLIF($7,'')::jsonb,NULLIF($8,'')::jsonb) RETURNING id, created_at`
+	auditEventsSQL = `SELECT id, timesheet_id, entity, entity_id, action, actor, COALESCE(request_id, ''),
+	                         COALESCE(before::text, ''), COALESCE(after::text, ''), created_at
+	                  FROM audit_events WHERE timesheet_id=$1 ORDER BY id ASC`
+
+	insertEntrySQL = `INSERT INTO timesheet_entries (timesheet_id, work_date, start_time, end_time, total_hours, overtime_hours, remarks,
+	                    entry_type, leave_type_id, overtime_breakdown, flagged, flag_reason, ends_next_day, night_hours, billable, client_id)
+	                  VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10::jsonb,$11,NULLIF($12,''),$13,$14,$15,$16) RETURNING id, created_at`
+	updateEntrySQL = `UPDATE timesheet_entries
+	                  SET work_date=$1, start_time=$2, end_time=$3, total_hours=$4, overtime_hours=$5, remarks=$6,
+	                      entry_type=$7, leave_type_id=$8, overtime_breakdown=$9::jsonb, flagged=$10, flag_reason=NULLIF($11,''),
+	                      ends_next_day=$12, night_hours=$13, billable=$14, client_id=$15
+	                  WHERE id=$16`
+	deleteEntrySQL = `DELETE FROM timesheet_entries WHERE id=$1`
+
+	// Entry lama tanpa breakdown: jam reguler = total - lembur.
+	weekRegularHoursSQL = `SELECT COALESCE(SUM(COALESCE((en.overtime_breakdown->>'regular_hours')::numeric,
+	                                                    COALESCE(en.total_hours, 0) - COALESCE(en.overtime_hours, 0))), 0)
+	                       FROM timesheet_entries en JOIN timesheets t ON t.id = en.timesheet_id
+	                       WHERE t.employee_id = $1 AND en.work_date >= $2 AND en.work_date < $3 AND en.id <> $4`
+
+	// Hari tidak hadir = entry absent, atau hari kerja (Senin–Jumat, bukan libur)
+	// sampai hari ini yang tidak terisi kerja/cuti jika total_working_days diisi.
+	// Hari kerja yang belum lewat tidak dihitung tidak hadir.
+	statsSQL = `
+	  WITH s AS (
+	    SELECT
+	      COUNT(*) FILTER (WHERE en.total_hours IS NOT NULL OR en.start_time IS NOT NULL OR en.end_time IS NOT NULL) AS days_filled,
+	      COUNT(DISTINCT en.work_date) FILTER (WHERE en.entry_type = 'work'
+	        AND (en.total_hours IS NOT NULL OR en.start_time IS NOT NULL)) AS worked,
+	      COUNT(DISTINCT en.work_date) FILTER (WHERE en.entry_type = 'work'
+	        AND (en.total_hours IS NOT NULL OR en.start_time IS NOT NULL)
+	        AND EXTRACT(ISODOW FROM en.work_date) < 6 AND h.id IS NULL AND en.work_date <= CURRENT_DATE) AS worked_workdays,
+	      COUNT(DISTINCT en.work_date) FILTER (WHERE en.entry_type = 'leave')  AS leave_days,
+	      COUNT(DISTINCT en.work_date) FILTER (WHERE en.entry_type = 'leave'
+	        AND EXTRACT(ISODOW FROM en.work_date) < 6 AND h.id IS NULL AND en.work_date <= CURRENT_DATE) AS leave_workdays,
+	      COUNT(DISTINCT en.work_date) FILTER (WHERE en.entry_type = 'absent') AS absent_days,
+	      COALESCE(SUM(en.total_hours), 0)    AS total_hours,
+	      COALESCE(SUM(en.overtime_hours), 0) AS overtime_hours,
+	      COALESCE(SUM(en.night_hours), 0)    AS night_hours
+	    FROM timesheet_entries en LEFT JOIN holidays h ON h.holiday_date = en.work_date
+	    WHERE en.timesheet_id = $1
+	  )
+	  SELECT s.days_filled, s.worked, s.leave_days,
+	         GREATEST(s.absent_days, CASE WHEN t.total_working_days IS NULL THEN 0
+	                                      ELSE LEAST(t.total_working_days, e.workdays) - s.worked_workdays - s.leave_workdays END),
+	         s.total_hours, s.overtime_hours, s.night_hours
+	  FROM s CROSS JOIN timesheets t
+	  CROSS JOIN LATERAL (
+	    SELECT COUNT(*) AS workdays
+	    FROM generate_series(make_date(t.year, t.month, 1),
+	                         LEAST(CURRENT_DATE, (make_date(t.year, t.month, 1) + INTERVAL '1 month - 1 day')::date),
+	                         INTERVAL '1 day') g(d)
+	    WHERE EXTRACT(ISODOW FROM g.d) < 6 AND NOT EXISTS (SELECT 1 FROM holidays hd WHERE hd.holiday_date = g.d::date)
+	  ) e
+	  WHERE t.id = $1
+	`
+)
+
+func insertEntryArgs(e *domain.TimesheetEntry) ([]interface{}, error) {
+	breakdown, err := jsonArg(e.Overtime)
+	if err != nil { return nil, err }
+	return []interface{}{e.TimesheetID, e.WorkDate, e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Remarks,
+		e.Type, e.LeaveTypeID, breakdown, e.Flagged, e.FlagReason, e.EndsNextDay, e.NightHours, e.Billable, e.ClientID}, nil
+}
+
+func updateEntryArgs(e *domain.TimesheetEntry) ([]interface{}, error) {
+	breakdown, err := jsonArg(e.Overtime)
+	if err != nil { return nil, err }
+	return []interface{}{e.WorkDate, e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Remarks,
+		e.Type, e.LeaveTypeID, breakdown, e.Flagged, e.FlagReason, e.EndsNextDay, e.NightHours, e.Billable, e.ClientID, e.ID}, nil
+}
+
+func auditEventArgs(ev *domain.AuditEvent) []interface{} {
+	return []interface{}{ev.TimesheetID, ev.Entity, ev.EntityID, ev.Action, ev.Actor, ev.RequestID, string(ev.Before), string(ev.After)}
+}
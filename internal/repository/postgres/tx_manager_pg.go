@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"timesheet-api/internal/repository"
+)
+
+// dbtx: operasi yang sama di *sql.DB dan *sql.Tx.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type TxManagerPG struct {
+	DB       *sql.DB
+	Timeouts QueryTimeouts // diteruskan ke repository di dalam transaksi
+}
+
+func NewTxManagerPG(db *sql.DB) *TxManagerPG { return &TxManagerPG{DB: db} }
+
+// WithTimeouts memasang batas waktu query untuk repository di dalam transaksi.
+func (m *TxManagerPG) WithTimeouts(t QueryTimeouts) *TxManagerPG {
+	m.Timeouts = t
+	return m
+}
+
+type txKey struct{}
+
+// txState: transaksi yang sedang berjalan, dibawa lewat ctx untuk pemanggilan bersarang.
+type txState struct {
+	tx         *sql.Tx
+	savepoints int // penomoran nama savepoint
+}
+
+func (m *TxManagerPG) WithinTx(ctx context.Context, fn func(ctx context.Context, repos repository.TxRepos) error) error {
+	if st, ok := ctx.Value(txKey{}).(*txState); ok {
+		return st.savepoint(ctx, func() error { return fn(ctx, st.repos(m)) })
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil { return err }
+	st := &txState{tx: tx}
+	committed := false
+	defer func() {
+		if !committed { _ = tx.Rollback() }
+	}()
+	if err := fn(context.WithValue(ctx, txKey{}, st), st.repos(m)); err != nil { return err }
+	if err := tx.Commit(); err != nil { return err }
+	committed = true
+	return nil
+}
+
+func (st *txState) repos(m *TxManagerPG) repository.TxRepos {
+	return repository.TxRepos{Timesheets: &TimesheetRepoPG{DB: m.DB, Timeouts: m.Timeouts, tx: st}}
+}
+
+// savepoint menjalankan fn di dalam SAVEPOINT: di-release jika berhasil,
+// di-rollback (hanya sampai savepoint itu) jika error atau panic.
+func (st *txState) savepoint(ctx context.Context, fn func() error) (err error) {
+	st.savepoints++
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+	if _, err := st.tx.ExecContext(ctx, `SAVEPOINT `+name); err != nil { return err }
+	done := false
+	defer func() {
+		// Tanpa ctx: rollback tetap jalan walau ctx sudah habis (timeout).
+		if !done { _, _ = st.tx.Exec(`ROLLBACK TO SAVEPOINT ` + name) }
+	}()
+	if err := fn(); err != nil { return err }
+	if _, err := st.tx.ExecContext(ctx, `RELEASE SAVEPOINT `+name); err != nil { return err }
+	done = true
+	return nil
+}
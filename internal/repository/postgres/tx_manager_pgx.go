@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"timesheet-api/internal/repository"
+)
+
+// TxManagerPGX: TxManager untuk TimesheetRepoPGX.
+type TxManagerPGX struct {
+	Pool     *pgxpool.Pool
+	Timeouts QueryTimeouts // diteruskan ke repository di dalam transaksi
+}
+
+func NewTxManagerPGX(pool *pgxpool.Pool) *TxManagerPGX { return &TxManagerPGX{Pool: pool} }
+
+// WithTimeouts memasang batas waktu query untuk repository di dalam transaksi.
+func (m *TxManagerPGX) WithTimeouts(t QueryTimeouts) *TxManagerPGX {
+	m.Timeouts = t
+	return m
+}
+
+type pgxTxKey struct{}
+
+// WithinTx: pemanggilan bersarang memakai Begin pada pgx.Tx yang dibawa ctx,
+// yang di pgx berupa savepoint.
+func (m *TxManagerPGX) WithinTx(ctx context.Context, fn func(ctx context.Context, repos repository.TxRepos) error) error {
+	var db pgxConn = m.Pool
+	if tx, ok := ctx.Value(pgxTxKey{}).(pgx.Tx); ok { db = tx }
+	return pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		repos := repository.TxRepos{Timesheets: &TimesheetRepoPGX{Pool: m.Pool, Timeouts: m.Timeouts, tx: tx}}
+		return fn(context.WithValue(ctx, pgxTxKey{}, tx), repos)
+	})
+}
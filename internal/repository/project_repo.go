@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// ProjectHoursFilter membatasi rekap jam project; From/To inklusif per work_date.
+type ProjectHoursFilter struct {
+	From *time.Time
+	To   *time.Time
+
+	Scope *Scope
+}
+
+type ProjectRepository interface {
+	Create(p *domain.Project) (int64, error)
+	FindByID(id int64) (*domain.Project, error)
+	List(activeOnly bool) ([]domain.Project, error)
+	Update(p *domain.Project) error
+	Delete(id int64) error
+
+	CreateTask(t *domain.Task) (int64, error)
+	FindTask(id int64) (*domain.Task, error)
+	ListTasks(projectID int64) ([]domain.Task, error)
+	UpdateTask(t *domain.Task) error
+	DeleteTask(id int64) error
+
+	// Hours merekap entry_allocations project per karyawan per bulan.
+	Hours(projectID int64, f ProjectHoursFilter) ([]domain.ProjectHours, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+type PunchRepository interface {
+	// LastOpen mengembalikan punch in terakhir karyawan yang belum ditutup punch out;
+	// (nil, nil) jika tidak ada.
+	LastOpen(employeeID int64) (*domain.Punch, error)
+	// OpenBefore mengembalikan semua punch in terbuka (semua karyawan) yang terjadi sebelum t.
+	OpenBefore(t time.Time) ([]domain.Punch, error)
+	// Record menyimpan p beserta entry hasilnya dan audit dalam satu transaksi:
+	// e.ID == 0 → entry baru, selain itu entry di-update; e nil → hanya punch.
+	// ErrInvalidTransition jika punch in saat masih terbuka atau punch out tanpa punch in;
+	// ErrTimeout jika melewati batas waktu tulis.
+	Record(ctx context.Context, p *domain.Punch, e *domain.TimesheetEntry, ev *domain.AuditEvent) error
+	List(employeeID int64, from, to time.Time) ([]domain.Punch, error)
+}
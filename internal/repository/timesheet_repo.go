@@ -1,24 +1,113 @@
 package repository
 
-import "timesheet-api/internal/domain"
+import (
+	"context"
+	"time"
+
+	"timesheet-api/internal/domain"
+)
+
+// Scope membatasi hasil list ke timesheet milik EmployeeID ATAU yang berada di
+// salah satu DepartmentIDs. Diisi oleh policy, bukan oleh client.
+type Scope struct {
+	EmployeeID    *int64
+	DepartmentIDs []int64
+}
 
 type Filter struct {
+	EmployeeID   *int64
 	EmployeeName string
+	Department   string // nama departemen, termasuk sub-departemennya
+	DepartmentID *int64 // termasuk sub-departemennya
 	Month        *int
 	Year         *int
+
+	Scope *Scope
+
+	// Sort: urutan hasil, hanya field di TimesheetSortFields. Kosong = terbaru
+	// dulu (year, month, id menurun). id selalu ditambahkan sebagai pemutus seri.
+	Sort []SortField
+	// PageSize 0 = tanpa batas. Page (mulai 1) untuk paging offset; jika Cursor
+	// diisi, halaman dimulai setelah baris cursor (keyset) dan Page diabaikan.
+	Page     int
+	PageSize int
+	Cursor   *Cursor
 }
 
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// TimesheetSortFields: field yang boleh dipakai di Filter.Sort.
+var TimesheetSortFields = map[string]bool{
+	"year": true, "month": true, "id": true, "employee_name": true, "department": true, "status": true, "created_at": true,
+}
+
+// Cursor: posisi keyset (year, month, id) baris terakhir halaman sebelumnya.
+// Hanya berlaku untuk urutan year, month, id dengan arah yang sama.
+type Cursor struct {
+	Year, Month int
+	ID          int64
+}
+
+// KeysetSort melaporkan apakah urutan sort bisa dipakai dengan Cursor, dan
+// arahnya.
+func KeysetSort(sort []SortField) (desc, ok bool) {
+	if len(sort) == 0 { return true, true }
+	if len(sort) != 3 { return false, false }
+	for i, name := range []string{"year", "month", "id"} {
+		if sort[i].Field != name || sort[i].Desc != sort[0].Desc { return false, false }
+	}
+	return sort[0].Desc, true
+}
+
+// NewEntry: satu entry untuk AddEntries beserta event audit-nya (nil = tanpa audit).
+type NewEntry struct {
+	Entry *domain.TimesheetEntry
+	Audit *domain.AuditEvent
+}
+
+// Semua method menerima ctx request: query berhenti jika client putus, dan
+// error karena batas waktu query dilaporkan sebagai domain.ErrTimeout.
+//
+// Semua method yang mengubah data menerima ev: event audit yang ditulis dalam
+// transaksi yang sama dengan perubahannya (nil = tanpa audit). Untuk create,
+// EntityID (dan TimesheetID) diisi repository setelah insert.
 type TimesheetRepository interface {
-	Create(ts *domain.Timesheet) (int64, error)
-	FindByID(id int64) (*domain.Timesheet, error)
-	List(f Filter) ([]domain.Timesheet, error)
-	Update(ts *domain.Timesheet) error
-	Delete(id int64) error
-
-	AddEntry(e *domain.TimesheetEntry) (int64, error)
-	UpdateEntry(e *domain.TimesheetEntry) error
-	DeleteEntry(id int64) error
-
-	// Tambahan untuk summary
-	Stats(timesheetID int64) (days int64, totalHours float64, overtimeHours float64, err error)
+	Create(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (int64, error)
+	FindByID(ctx context.Context, id int64) (*domain.Timesheet, error)
+	List(ctx context.Context, f Filter) ([]domain.Timesheet, error)
+	// Count: jumlah timesheet yang cocok dengan f, tanpa paging.
+	Count(ctx context.Context, f Filter) (int64, error)
+	// Stream seperti List, tapi tiap timesheet dikirim ke fn langsung dari cursor
+	// tanpa mengumpulkan semua hasil di memori. withEntries → Entries ikut diisi.
+	// Error dari fn menghentikan iterasi dan dikembalikan apa adanya.
+	// withEntries memakai dua koneksi sekaligus (cursor + entries), jadi pool
+	// harus mengizinkan minimal dua koneksi.
+	Stream(ctx context.Context, f Filter, withEntries bool, fn func(*domain.Timesheet) error) error
+	Update(ctx context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) error
+	Delete(ctx context.Context, id int64, ev *domain.AuditEvent) error
+
+	// ChangeStatus memindahkan status dari ch.FromStatus ke ch.ToStatus dan
+	// mencatat riwayatnya. ErrInvalidTransition jika status sudah berubah duluan.
+	ChangeStatus(ctx context.Context, ch *domain.StatusChange, ev *domain.AuditEvent) error
+	StatusHistory(ctx context.Context, timesheetID int64) ([]domain.StatusChange, error)
+	// History mengembalikan audit trail timesheet beserta entries-nya, urut kronologis.
+	History(ctx context.Context, timesheetID int64) ([]domain.AuditEvent, error)
+
+	FindEntryByID(ctx context.Context, id int64) (*domain.TimesheetEntry, error)
+	AddEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (int64, error)
+	// AddEntries menambahkan banyak entry sekaligus dalam satu transaksi: semua
+	// tersimpan atau tidak sama sekali. ID entry & EntityID audit diisi seperti AddEntry.
+	AddEntries(ctx context.Context, items []NewEntry) error
+	UpdateEntry(ctx context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) error
+	DeleteEntry(ctx context.Context, id int64, ev *domain.AuditEvent) error
+
+	// WeekRegularHours menjumlah jam reguler karyawan pada work_date di [from, before),
+	// lintas timesheet, untuk batas lembur mingguan.
+	WeekRegularHours(ctx context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (float64, error)
+
+	// Stats: ringkasan hari kerja/cuti/tidak hadir dan total jam.
+	Stats(ctx context.Context, timesheetID int64) (*domain.TimesheetStats, error)
 }
@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+// TxRepos: repository yang terikat ke satu transaksi TxManager.
+type TxRepos struct {
+	Timesheets TimesheetRepository
+}
+
+// TxManager menjalankan fn dalam satu transaksi: commit jika fn mengembalikan
+// nil, rollback jika error atau panic. ctx yang diberikan ke fn membawa
+// transaksinya; WithinTx bersarang dengan ctx itu memakai savepoint, jadi
+// kegagalan di dalam hanya membatalkan bagiannya sendiri. Repository dari
+// TxRepos tidak boleh dipakai dari goroutine lain atau setelah fn selesai.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context, repos TxRepos) error) error
+}
@@ -9,7 +9,9 @@ import (
 type PaginationMeta struct {
 	Page     int   `json:"page,omitempty"`
 	PageSize int   `json:"page_size,omitempty"`
-	Total    int64 `json:"total,omitempty"`
+	Total    int64 `json:"total"`
+	// NextCursor: cursor halaman berikutnya (paging keyset); kosong jika sudah habis.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ResponseMeta struct {
@@ -48,6 +50,10 @@ func write(c *gin.Context, code int, status, msg string, data interface{}, errDe
 func OK(c *gin.Context, data interface{}, msg string) {
 	write(c, http.StatusOK, http.StatusText(http.StatusOK), msg, data, nil, nil)
 }
+// OKPage: seperti OK, dengan meta pagination dan request id.
+func OKPage(c *gin.Context, data interface{}, msg string, p *PaginationMeta) {
+	write(c, http.StatusOK, http.StatusText(http.StatusOK), msg, data, nil, &ResponseMeta{RequestID: c.GetString("request_id"), Pagination: p})
+}
 func Created(c *gin.Context, data interface{}, msg string) {
 	write(c, http.StatusCreated, http.StatusText(http.StatusCreated), msg, data, nil, nil)
 }
@@ -83,3 +89,6 @@ func Internal(c *gin.Context, msg string) {
 func ServiceUnavailable(c *gin.Context, msg string) {
 	write(c, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable), msg, nil, nil, nil)
 }
+func GatewayTimeout(c *gin.Context, msg string) {
+	write(c, http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout), msg, nil, nil, nil)
+}
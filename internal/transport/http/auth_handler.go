@@ -0,0 +1,114 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+// AuthPublicPaths adalah route yang boleh diakses tanpa access token.
+var AuthPublicPaths = []string{"/auth/login", "/auth/refresh", "/auth/logout"}
+
+type AuthHandler struct{ svc *usecase.AuthService }
+func NewAuthHandler(s *usecase.AuthService) *AuthHandler { return &AuthHandler{svc: s} }
+
+func (h *AuthHandler) Register(r *gin.Engine) {
+	a := r.Group("/auth")
+	{
+		a.POST("/login", h.login)
+		a.POST("/refresh", h.refresh)
+		a.POST("/logout", h.logout)
+		a.POST("/logout-all", h.logoutAll)
+		a.GET("/me", h.me)
+	}
+
+	u := r.Group("/users", middleware.RequireRole(domain.RoleHRAdmin))
+	{
+		u.POST("", h.createUser)
+		u.GET("", h.listUsers)
+	}
+}
+
+type loginReq struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type refreshReq struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type createUserReq struct {
+	Username   string      `json:"username" binding:"required"`
+	Password   string      `json:"password" binding:"required"`
+	Role       domain.Role `json:"role"`
+	EmployeeID *int64      `json:"employee_id"`
+}
+
+func (h *AuthHandler) login(c *gin.Context) {
+	var req loginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	pair, err := h.svc.Login(req.Username, req.Password)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, pair, "Login success")
+}
+
+func (h *AuthHandler) refresh(c *gin.Context) {
+	var req refreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	pair, err := h.svc.Refresh(req.RefreshToken)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, pair, "Token refreshed")
+}
+
+func (h *AuthHandler) logout(c *gin.Context) {
+	var req refreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	if err := h.svc.Logout(req.RefreshToken); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+func (h *AuthHandler) logoutAll(c *gin.Context) {
+	p := middleware.CurrentPrincipal(c)
+	if p == nil { resp.Unauthorized(c, "Unauthenticated"); return }
+	if err := h.svc.LogoutAll(p.UserID); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+func (h *AuthHandler) me(c *gin.Context) {
+	p := middleware.CurrentPrincipal(c)
+	if p == nil { resp.Unauthorized(c, "Unauthenticated"); return }
+	u, err := h.svc.Me(p.UserID)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, u, "Success")
+}
+
+func (h *AuthHandler) createUser(c *gin.Context) {
+	var req createUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	u := domain.User{Username: req.Username, Role: req.Role, EmployeeID: req.EmployeeID, Active: true}
+	id, err := h.svc.CreateUser(&u, req.Password)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "User created")
+}
+
+func (h *AuthHandler) listUsers(c *gin.Context) {
+	items, err := h.svc.ListUsers()
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
@@ -0,0 +1,290 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type BillingHandler struct{ svc *usecase.BillingService }
+func NewBillingHandler(s *usecase.BillingService) *BillingHandler { return &BillingHandler{svc: s} }
+
+func (h *BillingHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	cl := r.Group("/clients")
+	{
+		cl.POST("", hr, h.createClient)
+		cl.GET("", h.listClients)
+		cl.GET("/:id", h.getClient)
+		cl.PUT("/:id", hr, h.updateClient)
+		cl.DELETE("/:id", hr, h.deleteClient)
+	}
+
+	rc := r.Group("/rate-cards", hr)
+	{
+		rc.POST("", h.createRate)
+		rc.GET("", h.listRates) // ?client_id=
+		rc.GET("/:id", h.getRate)
+		rc.PUT("/:id", h.updateRate)
+		rc.DELETE("/:id", h.deleteRate)
+	}
+
+	r.GET("/invoices/preview", hr, h.invoicePreview) // ?client_id=&from=&to=&format=json|csv|pdf
+}
+
+// ====== Clients ======
+
+type clientReq struct {
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Currency string `json:"currency"` // default IDR
+	Active   *bool  `json:"active"`   // default true
+}
+
+func (h *BillingHandler) bindClient(c *gin.Context, id int64) (*domain.Client, bool) {
+	var req clientReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	cl := &domain.Client{ID: id, Code: req.Code, Name: req.Name, Currency: req.Currency, Active: true}
+	if req.Active != nil { cl.Active = *req.Active }
+	return cl, true
+}
+
+func (h *BillingHandler) createClient(c *gin.Context) {
+	cl, ok := h.bindClient(c, 0)
+	if !ok { return }
+	id, err := h.svc.CreateClient(cl)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Client created")
+}
+
+func (h *BillingHandler) listClients(c *gin.Context) {
+	items, err := h.svc.ListClients()
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *BillingHandler) getClient(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	cl, err := h.svc.GetClient(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, cl, "Success")
+}
+
+func (h *BillingHandler) updateClient(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	cl, ok := h.bindClient(c, id)
+	if !ok { return }
+	if err := h.svc.UpdateClient(cl); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Client updated")
+}
+
+func (h *BillingHandler) deleteClient(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteClient(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// ====== Rate cards ======
+
+type rateReq struct {
+	ClientID      *int64  `json:"client_id"`  // kosong = semua klien
+	ProjectID     *int64  `json:"project_id"` // kosong = semua project
+	Position      string  `json:"position"`   // kosong = semua jabatan
+	HourlyRate    float64 `json:"hourly_rate"`
+	EffectiveFrom string  `json:"effective_from" binding:"required"` // YYYY-MM-DD
+	EffectiveTo   string  `json:"effective_to"`                      // kosong = masih berlaku
+}
+
+func (h *BillingHandler) bindRate(c *gin.Context, id int64) (*domain.RateCard, bool) {
+	var req rateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	from, err := usecase.ParseDate(req.EffectiveFrom)
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "effective_from", Message: "format YYYY-MM-DD"}}, "Invalid date")
+		return nil, false
+	}
+	rc := &domain.RateCard{ID: id, ClientID: req.ClientID, ProjectID: req.ProjectID, Position: req.Position,
+		HourlyRate: req.HourlyRate, EffectiveFrom: from}
+	if req.EffectiveTo != "" {
+		to, err := usecase.ParseDate(req.EffectiveTo)
+		if err != nil {
+			resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "effective_to", Message: "format YYYY-MM-DD"}}, "Invalid date")
+			return nil, false
+		}
+		rc.EffectiveTo = &to
+	}
+	return rc, true
+}
+
+func (h *BillingHandler) createRate(c *gin.Context) {
+	rc, ok := h.bindRate(c, 0)
+	if !ok { return }
+	id, err := h.svc.CreateRate(rc)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Rate card created")
+}
+
+func (h *BillingHandler) listRates(c *gin.Context) {
+	var clientID *int64
+	if v := c.Query("client_id"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil { clientID = &n }
+	}
+	items, err := h.svc.ListRates(clientID)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *BillingHandler) getRate(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	rc, err := h.svc.GetRate(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, rc, "Success")
+}
+
+func (h *BillingHandler) updateRate(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	rc, ok := h.bindRate(c, id)
+	if !ok { return }
+	if err := h.svc.UpdateRate(rc); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Rate card updated")
+}
+
+func (h *BillingHandler) deleteRate(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteRate(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// ====== Invoice preview ======
+
+func (h *BillingHandler) invoicePreview(c *gin.Context) {
+	clientID, err := strconv.ParseInt(c.Query("client_id"), 10, 64)
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "client_id", Message: "wajib diisi (angka)"}}, "Invalid client_id")
+		return
+	}
+	from, err1 := usecase.ParseDate(c.Query("from"))
+	to, err2 := usecase.ParseDate(c.Query("to"))
+	if err1 != nil || err2 != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "from/to", Message: "wajib diisi, format YYYY-MM-DD"}}, "Invalid date")
+		return
+	}
+	inv, err := h.svc.InvoicePreview(clientID, from, to)
+	if err != nil { mapError(c, err); return }
+
+	name := fmt.Sprintf("invoice_%s_%s_%s", slugify(inv.Client.Code), from.Format("20060102"), to.Format("20060102"))
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		resp.OK(c, inv, "Success")
+	case "csv":
+		var b bytes.Buffer
+		if err := writeInvoiceCSV(&b, inv); err != nil {
+			resp.Internal(c, "gagal membuat CSV")
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+name+".csv")
+		c.Data(200, "text/csv; charset=utf-8", b.Bytes())
+	case "pdf":
+		pdf := newTimesheetPDF()
+		renderInvoicePDF(pdf, inv)
+		var b bytes.Buffer
+		if err := pdf.Output(&b); err != nil {
+			resp.Internal(c, "gagal membuat PDF")
+			return
+		}
+		c.Header("Content-Disposition", "inline; filename="+name+".pdf")
+		c.Data(200, "application/pdf", b.Bytes())
+	default:
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "format", Message: "json, csv, atau pdf"}}, "Invalid format")
+	}
+}
+
+func writeInvoiceCSV(b *bytes.Buffer, inv *domain.InvoicePreview) error {
+	w := csv.NewWriter(b)
+	_ = w.Write([]string{"employee_id", "employee_name", "position", "project_code", "hours", "hourly_rate", "amount", "currency"})
+	for _, ln := range inv.Lines {
+		_ = w.Write([]string{strconv.FormatInt(ln.EmployeeID, 10), ln.EmployeeName, ln.Position, ln.ProjectCode,
+			money(ln.Hours), money(ln.HourlyRate), money(ln.Amount), inv.Client.Currency})
+	}
+	_ = w.Write([]string{"", "TOTAL", "", "", money(inv.TotalHours), "", money(inv.TotalAmount), inv.Client.Currency})
+	w.Flush()
+	return w.Error()
+}
+
+// renderInvoicePDF menulis pratinjau tagihan satu klien mulai dari halaman baru.
+func renderInvoicePDF(pdf *gofpdf.Fpdf, inv *domain.InvoicePreview) {
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.Cell(0, 8, "PRATINJAU TAGIHAN / INVOICE PREVIEW")
+	pdf.Ln(10)
+
+	pdf.SetFont("Helvetica", "", 11)
+	headerRow := func(label, val string) {
+		pdf.CellFormat(55, 6, label, "", 0, "", false, 0, "")
+		pdf.CellFormat(5, 6, ":", "", 0, "", false, 0, "")
+		pdf.CellFormat(0, 6, val, "", 1, "", false, 0, "")
+	}
+	headerRow("Klien / Client", fmt.Sprintf("%s - %s", inv.Client.Code, inv.Client.Name))
+	headerRow("Periode / Period", inv.From.Format("2006-01-02")+" s/d "+inv.To.Format("2006-01-02"))
+	headerRow("Mata Uang / Currency", inv.Client.Currency)
+	pdf.Ln(2)
+
+	cols := []struct {
+		Title string
+		Width float64
+	}{
+		{"Karyawan / Employee", 50},
+		{"Jabatan / Role", 34},
+		{"Project", 24},
+		{"Jam / Hrs", 20},
+		{"Tarif / Rate", 28},
+		{"Jumlah / Amount", 34},
+	}
+	pdf.SetFont("Helvetica", "B", 10)
+	for _, col := range cols {
+		pdf.CellFormat(col.Width, 8, col.Title, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFillColor(253, 226, 226) // baris tanpa tarif
+	for _, ln := range inv.Lines {
+		unrated := ln.RateCardID == nil
+		rate := money(ln.HourlyRate)
+		if unrated { rate = "-" }
+		pdf.CellFormat(cols[0].Width, 8, ln.EmployeeName, "1", 0, "L", unrated, 0, "")
+		pdf.CellFormat(cols[1].Width, 8, ln.Position, "1", 0, "L", unrated, 0, "")
+		pdf.CellFormat(cols[2].Width, 8, ln.ProjectCode, "1", 0, "C", unrated, 0, "")
+		pdf.CellFormat(cols[3].Width, 8, money(ln.Hours), "1", 0, "R", unrated, 0, "")
+		pdf.CellFormat(cols[4].Width, 8, rate, "1", 0, "R", unrated, 0, "")
+		pdf.CellFormat(cols[5].Width, 8, money(ln.Amount), "1", 1, "R", unrated, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(cols[0].Width+cols[1].Width+cols[2].Width, 8, "TOTAL", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[3].Width, 8, money(inv.TotalHours), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[4].Width, 8, "", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(cols[5].Width, 8, money(inv.TotalAmount), "1", 1, "R", false, 0, "")
+	if inv.UnratedHours > 0 {
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.MultiCell(0, 5, fmt.Sprintf("%.2f jam belum punya tarif (baris berwarna) / hours without a rate card (highlighted).", inv.UnratedHours), "", "L", false)
+	}
+}
+
+func money(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
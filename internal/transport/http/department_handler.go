@@ -0,0 +1,86 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type DepartmentHandler struct{ svc *usecase.DepartmentService }
+func NewDepartmentHandler(s *usecase.DepartmentService) *DepartmentHandler { return &DepartmentHandler{svc: s} }
+
+func (h *DepartmentHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	d := r.Group("/departments")
+	{
+		d.POST("", hr, h.createDepartment)
+		d.GET("", h.listDepartments)
+		d.GET("/:id", h.getDepartment)
+		d.GET("/:id/subtree", h.subtree)
+		d.PUT("/:id", hr, h.updateDepartment)
+		d.DELETE("/:id", hr, h.deleteDepartment)
+	}
+}
+
+type departmentReq struct {
+	Code             *string `json:"code"`
+	Name             string  `json:"name" binding:"required"`
+	ParentID         *int64  `json:"parent_id"`
+	ManagerID        *int64  `json:"manager_id"`
+	OvertimePolicyID *int64  `json:"overtime_policy_id"`
+}
+
+func (h *DepartmentHandler) createDepartment(c *gin.Context) {
+	var req departmentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	d := domain.Department{Code: req.Code, Name: req.Name, ParentID: req.ParentID, ManagerID: req.ManagerID, OvertimePolicyID: req.OvertimePolicyID}
+	id, err := h.svc.CreateDepartment(&d)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Department created")
+}
+
+func (h *DepartmentHandler) listDepartments(c *gin.Context) {
+	items, err := h.svc.ListDepartments()
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *DepartmentHandler) getDepartment(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	d, err := h.svc.GetDepartment(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, d, "Success")
+}
+
+func (h *DepartmentHandler) subtree(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	items, err := h.svc.Subtree(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *DepartmentHandler) updateDepartment(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req departmentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	d := domain.Department{ID: id, Code: req.Code, Name: req.Name, ParentID: req.ParentID, ManagerID: req.ManagerID, OvertimePolicyID: req.OvertimePolicyID}
+	if err := h.svc.UpdateDepartment(&d); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Department updated")
+}
+
+func (h *DepartmentHandler) deleteDepartment(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteDepartment(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
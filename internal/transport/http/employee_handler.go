@@ -0,0 +1,88 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type EmployeeHandler struct{ svc *usecase.EmployeeService }
+func NewEmployeeHandler(s *usecase.EmployeeService) *EmployeeHandler { return &EmployeeHandler{svc: s} }
+
+func (h *EmployeeHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	emp := r.Group("/employees")
+	{
+		emp.POST("", hr, h.createEmployee)
+		emp.GET("", h.listEmployees) // ?q=...&active=true
+		emp.GET("/:id", h.getEmployee)
+		emp.PUT("/:id", hr, h.updateEmployee)
+		emp.DELETE("/:id", hr, h.deleteEmployee)
+	}
+}
+
+type employeeReq struct {
+	Code   *string `json:"code"`
+	Name   string  `json:"name" binding:"required"`
+	Email        *string `json:"email"`
+	Position     *string `json:"position"`
+	DepartmentID *int64  `json:"department_id"`
+	Active       *bool   `json:"active"`
+}
+
+func (req employeeReq) toDomain(id int64) domain.Employee {
+	e := domain.Employee{ID: id, Code: req.Code, Name: req.Name, Email: req.Email, Position: req.Position, DepartmentID: req.DepartmentID, Active: true}
+	if req.Active != nil { e.Active = *req.Active }
+	return e
+}
+
+func (h *EmployeeHandler) createEmployee(c *gin.Context) {
+	var req employeeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	e := req.toDomain(0)
+	id, err := h.svc.CreateEmployee(&e)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Employee created")
+}
+
+func (h *EmployeeHandler) listEmployees(c *gin.Context) {
+	f := repository.EmployeeFilter{Name: c.Query("q")}
+	if v := c.Query("active"); v != "" { if b, err := strconv.ParseBool(v); err == nil { f.Active = &b } }
+	items, err := h.svc.ListEmployees(f)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *EmployeeHandler) getEmployee(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	e, err := h.svc.GetEmployee(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, e, "Success")
+}
+
+func (h *EmployeeHandler) updateEmployee(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req employeeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	e := req.toDomain(id)
+	if err := h.svc.UpdateEmployee(&e); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Employee updated")
+}
+
+func (h *EmployeeHandler) deleteEmployee(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteEmployee(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
@@ -1,7 +1,40 @@
 package http
 
-import "github.com/gin-gonic/gin"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+)
 
 type Routes interface {
 	Register(*gin.Engine)
 }
+
+// mapError menerjemahkan error domain ke response HTTP; dipakai semua handler.
+func mapError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		resp.BadRequest(c, fmt.Sprintf("%v", err), "Invalid input")
+	case errors.Is(err, domain.ErrUnauthorized):
+		resp.Unauthorized(c, "Unauthorized")
+	case errors.Is(err, domain.ErrForbidden):
+		resp.Forbidden(c, "Forbidden")
+	case errors.Is(err, domain.ErrNotFound):
+		resp.NotFound(c, "Not found")
+	case errors.Is(err, domain.ErrDuplicate):
+		resp.Conflict(c, "Duplicate")
+	case errors.Is(err, domain.ErrInUse):
+		resp.Conflict(c, "Still referenced by other data")
+	case errors.Is(err, domain.ErrLocked), errors.Is(err, domain.ErrInvalidTransition):
+		resp.Conflict(c, err.Error())
+	case errors.Is(err, domain.ErrTimeout):
+		resp.GatewayTimeout(c, "Query timeout")
+	default:
+		resp.Internal(c, "Internal error")
+	}
+}
+
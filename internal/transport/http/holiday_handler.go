@@ -0,0 +1,130 @@
+package http
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type HolidayHandler struct{ svc *usecase.HolidayService }
+func NewHolidayHandler(s *usecase.HolidayService) *HolidayHandler { return &HolidayHandler{svc: s} }
+
+func (h *HolidayHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	g := r.Group("/holidays")
+	{
+		g.POST("", hr, h.createHoliday)
+		g.GET("", h.listHolidays)             // ?year=2025[&month=7]
+		g.GET("/working-days", h.workingDays) // ?year=2025&month=7
+		g.POST("/import", hr, h.importICS)    // multipart "file" atau body text/calendar; ?kind=national
+		g.GET("/:id", h.getHoliday)
+		g.PUT("/:id", hr, h.updateHoliday)
+		g.DELETE("/:id", hr, h.deleteHoliday)
+	}
+}
+
+type holidayReq struct {
+	Date string             `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string             `json:"name" binding:"required"`
+	Kind domain.HolidayKind `json:"kind"` // default national
+}
+
+func (h *HolidayHandler) bindHoliday(c *gin.Context, id int64) (*domain.Holiday, bool) {
+	var req holidayReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	d, err := usecase.ParseDate(req.Date)
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "date", Message: "format YYYY-MM-DD"}}, "Invalid date")
+		return nil, false
+	}
+	return &domain.Holiday{ID: id, Date: d, Name: req.Name, Kind: req.Kind}, true
+}
+
+func (h *HolidayHandler) createHoliday(c *gin.Context) {
+	hd, ok := h.bindHoliday(c, 0)
+	if !ok { return }
+	id, err := h.svc.CreateHoliday(hd)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Holiday created")
+}
+
+func (h *HolidayHandler) listHolidays(c *gin.Context) {
+	year, month, ok := yearMonthQuery(c)
+	if !ok { return }
+	items, err := h.svc.ListHolidays(year, month)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *HolidayHandler) workingDays(c *gin.Context) {
+	year, month, ok := yearMonthQuery(c)
+	if !ok { return }
+	if month == nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "month", Message: "wajib diisi"}}, "Missing month")
+		return
+	}
+	n, err := h.svc.WorkingDays(year, *month)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"year": year, "month": *month, "working_days": n}, "Success")
+}
+
+func (h *HolidayHandler) importICS(c *gin.Context) {
+	var src io.Reader = c.Request.Body
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil { resp.BadRequest(c, nil, "Cannot read file"); return }
+		defer f.Close()
+		src = f
+	}
+	n, err := h.svc.ImportICS(src, domain.HolidayKind(c.Query("kind")))
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"imported": n}, "Holidays imported")
+}
+
+func (h *HolidayHandler) getHoliday(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	hd, err := h.svc.GetHoliday(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, hd, "Success")
+}
+
+func (h *HolidayHandler) updateHoliday(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	hd, ok := h.bindHoliday(c, id)
+	if !ok { return }
+	if err := h.svc.UpdateHoliday(hd); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Holiday updated")
+}
+
+func (h *HolidayHandler) deleteHoliday(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteHoliday(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// yearMonthQuery membaca ?year= (wajib) dan ?month= (opsional).
+func yearMonthQuery(c *gin.Context) (int, *int, bool) {
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "year", Message: "wajib diisi (angka)"}}, "Invalid year")
+		return 0, nil, false
+	}
+	if v := c.Query("month"); v != "" {
+		m, err := strconv.Atoi(v)
+		if err != nil {
+			resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "month", Message: "harus angka 1-12"}}, "Invalid month")
+			return 0, nil, false
+		}
+		return year, &m, true
+	}
+	return year, nil, true
+}
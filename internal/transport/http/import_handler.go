@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/importer"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+)
+
+type ImportHandler struct{ svc *usecase.ImportService }
+func NewImportHandler(s *usecase.ImportService) *ImportHandler { return &ImportHandler{svc: s} }
+
+func (h *ImportHandler) Register(r *gin.Engine) {
+	// multipart: file (csv/xlsx), profile (JSON pemetaan kolom, opsional), format (opsional)
+	// ?mode=dry_run (default, hanya validasi) | commit
+	r.POST("/timesheets/import", h.importEntries)
+}
+
+func (h *ImportHandler) importEntries(c *gin.Context) {
+	mode := c.DefaultQuery("mode", "dry_run")
+	if mode != "dry_run" && mode != "commit" {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "mode", Message: "dry_run atau commit"}}, "Invalid mode")
+		return
+	}
+	fh, err := c.FormFile("file")
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "file", Message: "wajib diisi (multipart)"}}, "Missing file")
+		return
+	}
+	var p importer.Profile
+	if raw := c.PostForm("profile"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &p); err != nil {
+			resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "profile", Message: err.Error()}}, "Invalid profile")
+			return
+		}
+	}
+	format := importer.Format(c.PostForm("format"))
+	if format == "" { format = importer.FormatOf(fh.Filename) }
+
+	f, err := fh.Open()
+	if err != nil { resp.BadRequest(c, nil, "Cannot read file"); return }
+	defer f.Close()
+	rows, err := importer.Read(format, f, p)
+	if err != nil { mapError(c, err); return }
+
+	res, err := h.svc.Import(c.Request.Context(), rows, mode == "dry_run")
+	if err != nil { mapError(c, err); return }
+	msg := "Dry run"
+	if !res.DryRun { msg = "Entries imported" }
+	resp.OK(c, res, msg)
+}
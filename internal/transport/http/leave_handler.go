@@ -0,0 +1,202 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type LeaveHandler struct{ svc *usecase.LeaveService }
+func NewLeaveHandler(s *usecase.LeaveService) *LeaveHandler { return &LeaveHandler{svc: s} }
+
+func (h *LeaveHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	lt := r.Group("/leave-types")
+	{
+		lt.POST("", hr, h.createType)
+		lt.GET("", h.listTypes)
+		lt.GET("/:id", h.getType)
+		lt.PUT("/:id", hr, h.updateType)
+		lt.DELETE("/:id", hr, h.deleteType)
+	}
+
+	lr := r.Group("/leave-requests")
+	{
+		lr.POST("", h.createRequest)
+		lr.GET("", h.listRequests) // ?employee_id=&status=&year=
+		lr.GET("/:id", h.getRequest)
+		lr.POST("/:id/approve", h.approveRequest) // body opsional: {"reason": "..."}
+		lr.POST("/:id/reject", h.rejectRequest)   // body: {"reason": "..."}
+		lr.POST("/:id/cancel", h.cancelRequest)
+	}
+
+	lb := r.Group("/leave-balances")
+	{
+		lb.GET("", h.balances) // ?employee_id=&year=
+		lb.PUT("", hr, h.setEntitlement)
+	}
+}
+
+// ====== Leave types ======
+
+type leaveTypeReq struct {
+	Code        string  `json:"code" binding:"required"`
+	Name        string  `json:"name" binding:"required"`
+	Paid        bool    `json:"paid"`
+	AnnualQuota float64 `json:"annual_quota"`
+}
+
+func (h *LeaveHandler) bindType(c *gin.Context, id int64) (*domain.LeaveType, bool) {
+	var req leaveTypeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	return &domain.LeaveType{ID: id, Code: req.Code, Name: req.Name, Paid: req.Paid, AnnualQuota: req.AnnualQuota}, true
+}
+
+func (h *LeaveHandler) createType(c *gin.Context) {
+	t, ok := h.bindType(c, 0)
+	if !ok { return }
+	id, err := h.svc.CreateType(t)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Leave type created")
+}
+
+func (h *LeaveHandler) listTypes(c *gin.Context) {
+	items, err := h.svc.ListTypes()
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *LeaveHandler) getType(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	t, err := h.svc.GetType(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, t, "Success")
+}
+
+func (h *LeaveHandler) updateType(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	t, ok := h.bindType(c, id)
+	if !ok { return }
+	if err := h.svc.UpdateType(t); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Leave type updated")
+}
+
+func (h *LeaveHandler) deleteType(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteType(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// ====== Leave requests ======
+
+type leaveRequestReq struct {
+	EmployeeID  int64  `json:"employee_id"` // kosong = diri sendiri
+	LeaveTypeID int64  `json:"leave_type_id" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate     string `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	Reason      string `json:"reason"`
+}
+
+func (h *LeaveHandler) createRequest(c *gin.Context) {
+	var req leaveRequestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	start, err1 := usecase.ParseDate(req.StartDate)
+	end, err2 := usecase.ParseDate(req.EndDate)
+	if err1 != nil || err2 != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "start_date/end_date", Message: "format YYYY-MM-DD"}}, "Invalid date")
+		return
+	}
+	lr := &domain.LeaveRequest{EmployeeID: req.EmployeeID, LeaveTypeID: req.LeaveTypeID, StartDate: start, EndDate: end, Reason: req.Reason}
+	id, err := h.svc.CreateRequest(c.Request.Context(), lr)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id, "days": lr.Days, "status": lr.Status}, "Leave request created")
+}
+
+func (h *LeaveHandler) listRequests(c *gin.Context) {
+	f := repository.LeaveFilter{Status: domain.LeaveStatus(c.Query("status"))}
+	if v := c.Query("employee_id"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil { f.EmployeeID = &id }
+	}
+	if v := c.Query("year"); v != "" {
+		if y, err := strconv.Atoi(v); err == nil { f.Year = &y }
+	}
+	items, err := h.svc.ListRequests(c.Request.Context(), f)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *LeaveHandler) getRequest(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	lr, err := h.svc.GetRequest(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, lr, "Success")
+}
+
+func (h *LeaveHandler) approveRequest(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req statusReq
+	_ = c.ShouldBindJSON(&req) // body opsional
+	if err := h.svc.Approve(c.Request.Context(), id, req.Reason); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.LeaveApproved}, "Leave request approved")
+}
+
+func (h *LeaveHandler) rejectRequest(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req statusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	if err := h.svc.Reject(c.Request.Context(), id, req.Reason); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.LeaveRejected}, "Leave request rejected")
+}
+
+func (h *LeaveHandler) cancelRequest(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.Cancel(c.Request.Context(), id); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.LeaveCancelled}, "Leave request cancelled")
+}
+
+// ====== Balances ======
+
+func (h *LeaveHandler) balances(c *gin.Context) {
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "year", Message: "wajib diisi (angka)"}}, "Invalid year")
+		return
+	}
+	employeeID, _ := strconv.ParseInt(c.Query("employee_id"), 10, 64) // kosong = diri sendiri
+	items, err := h.svc.Balances(c.Request.Context(), employeeID, year)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+type entitlementReq struct {
+	EmployeeID  int64   `json:"employee_id" binding:"required"`
+	LeaveTypeID int64   `json:"leave_type_id" binding:"required"`
+	Year        int     `json:"year" binding:"required"`
+	Entitled    float64 `json:"entitled"`
+}
+
+func (h *LeaveHandler) setEntitlement(c *gin.Context) {
+	var req entitlementReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	b := &domain.LeaveBalance{EmployeeID: req.EmployeeID, LeaveTypeID: req.LeaveTypeID, Year: req.Year, Entitled: req.Entitled}
+	if err := h.svc.SetEntitlement(b); err != nil { mapError(c, err); return }
+	resp.OK(c, b, "Leave entitlement updated")
+}
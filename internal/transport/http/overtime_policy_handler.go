@@ -0,0 +1,96 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type OvertimePolicyHandler struct{ svc *usecase.OvertimePolicyService }
+func NewOvertimePolicyHandler(s *usecase.OvertimePolicyService) *OvertimePolicyHandler { return &OvertimePolicyHandler{svc: s} }
+
+func (h *OvertimePolicyHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	p := r.Group("/overtime-policies")
+	{
+		p.POST("", hr, h.createPolicy)
+		p.GET("", h.listPolicies)
+		p.GET("/:id", h.getPolicy)
+		p.PUT("/:id", hr, h.updatePolicy)
+		p.DELETE("/:id", hr, h.deletePolicy)
+	}
+}
+
+type overtimePolicyReq struct {
+	Name                 string   `json:"name" binding:"required"`
+	DailyThresholdHours  *float64 `json:"daily_threshold_hours"`  // default 8
+	WeeklyThresholdHours *float64 `json:"weekly_threshold_hours"` // default 40
+	OvertimeMultiplier   float64  `json:"overtime_multiplier"`
+	WeekendMultiplier    float64  `json:"weekend_multiplier"`
+	HolidayMultiplier    float64  `json:"holiday_multiplier"`
+	BreakMinutes         int      `json:"break_minutes"`
+	BreakAfterHours      float64  `json:"break_after_hours"`
+	NightStart           string   `json:"night_start"` // default 22:00
+	NightEnd             string   `json:"night_end"`   // default 06:00
+	IsDefault            bool     `json:"is_default"`
+}
+
+func (req overtimePolicyReq) toDomain(id int64) domain.OvertimePolicy {
+	p := domain.OvertimePolicy{
+		ID: id, Name: req.Name, DailyThresholdHours: 8, WeeklyThresholdHours: 40,
+		OvertimeMultiplier: req.OvertimeMultiplier, WeekendMultiplier: req.WeekendMultiplier, HolidayMultiplier: req.HolidayMultiplier,
+		BreakMinutes: req.BreakMinutes, BreakAfterHours: req.BreakAfterHours, NightStart: req.NightStart, NightEnd: req.NightEnd,
+		IsDefault: req.IsDefault,
+	}
+	if req.DailyThresholdHours != nil { p.DailyThresholdHours = *req.DailyThresholdHours }
+	if req.WeeklyThresholdHours != nil { p.WeeklyThresholdHours = *req.WeeklyThresholdHours }
+	return p
+}
+
+func (h *OvertimePolicyHandler) createPolicy(c *gin.Context) {
+	var req overtimePolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	p := req.toDomain(0)
+	id, err := h.svc.CreatePolicy(&p)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Overtime policy created")
+}
+
+func (h *OvertimePolicyHandler) listPolicies(c *gin.Context) {
+	items, err := h.svc.ListPolicies()
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *OvertimePolicyHandler) getPolicy(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	p, err := h.svc.GetPolicy(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, p, "Success")
+}
+
+func (h *OvertimePolicyHandler) updatePolicy(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req overtimePolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	p := req.toDomain(id)
+	if err := h.svc.UpdatePolicy(&p); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Overtime policy updated")
+}
+
+func (h *OvertimePolicyHandler) deletePolicy(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeletePolicy(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
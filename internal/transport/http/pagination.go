@@ -0,0 +1,99 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/resp"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// pageFilter: listFilter ditambah sort & paging dari query string:
+// ?page=2&page_size=50 (offset) atau ?cursor=<next_cursor> (keyset), dan
+// ?sort=-year,employee_name ("-" = menurun).
+func pageFilter(c *gin.Context) (repository.Filter, []resp.ErrorDetail) {
+	f := listFilter(c)
+	var errs []resp.ErrorDetail
+	bad := func(field, msg string) {
+		errs = append(errs, resp.ErrorDetail{Type: "validation_error", Field: field, Message: msg})
+	}
+
+	var err error
+	if f.Sort, err = parseSort(c.Query("sort")); err != nil { bad("sort", err.Error()) }
+	f.Page, f.PageSize = 1, defaultPageSize
+	if v := c.Query("page"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 { bad("page", "harus bilangan bulat ≥ 1") } else { f.Page = n }
+	}
+	if v := c.Query("page_size"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 || n > maxPageSize {
+			bad("page_size", fmt.Sprintf("harus antara 1 dan %d", maxPageSize))
+		} else {
+			f.PageSize = n
+		}
+	}
+	if v := c.Query("cursor"); v != "" {
+		cur, ok := decodeCursor(v)
+		_, keyset := repository.KeysetSort(f.Sort)
+		switch {
+		case !ok:
+			bad("cursor", "tidak valid")
+		case c.Query("page") != "":
+			bad("page", "tidak bisa dipakai bersama cursor")
+		case !keyset:
+			bad("sort", "cursor hanya bisa dipakai dengan sort year,month,id (arah sama)")
+		default:
+			f.Cursor = cur
+		}
+	}
+	return f, errs
+}
+
+// parseSort: daftar field dipisah koma, diawali "-" untuk urutan menurun.
+func parseSort(v string) ([]repository.SortField, error) {
+	if v == "" { return nil, nil }
+	var out []repository.SortField
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		sf := repository.SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !repository.TimesheetSortFields[sf.Field] {
+			return nil, fmt.Errorf("field %q tidak bisa dipakai untuk sort", sf.Field)
+		}
+		out = append(out, sf)
+	}
+	return out, nil
+}
+
+// pageMeta: meta pagination untuk halaman items. NextCursor hanya diisi jika
+// urutannya cocok untuk keyset dan halaman ini penuh.
+func pageMeta(f repository.Filter, items []domain.Timesheet, total int64) *resp.PaginationMeta {
+	m := &resp.PaginationMeta{PageSize: f.PageSize, Total: total}
+	if f.Cursor == nil { m.Page = f.Page }
+	if _, keyset := repository.KeysetSort(f.Sort); keyset && len(items) > 0 && len(items) == f.PageSize {
+		last := items[len(items)-1]
+		m.NextCursor = encodeCursor(repository.Cursor{Year: last.Year, Month: last.Month, ID: last.ID})
+	}
+	return m
+}
+
+// Cursor dikirim ke client sebagai string opaque: base64 dari "year.month.id".
+func encodeCursor(cur repository.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d.%d", cur.Year, cur.Month, cur.ID)))
+}
+
+func decodeCursor(v string) (*repository.Cursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil { return nil, false }
+	var cur repository.Cursor
+	if n, err := fmt.Sscanf(string(raw), "%d.%d.%d", &cur.Year, &cur.Month, &cur.ID); err != nil || n != 3 { return nil, false }
+	return &cur, true
+}
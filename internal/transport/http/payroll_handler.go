@@ -0,0 +1,50 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/payroll"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type PayrollHandler struct{ svc *usecase.PayrollService }
+func NewPayrollHandler(s *usecase.PayrollService) *PayrollHandler { return &PayrollHandler{svc: s} }
+
+func (h *PayrollHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	// ?year=&month=&department_id=&status=approved&format=csv|json
+	r.GET("/payroll/export", hr, h.export)
+}
+
+func (h *PayrollHandler) export(c *gin.Context) {
+	f := listFilter(c)
+	if f.Month == nil || f.Year == nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "month/year", Message: "wajib diisi (angka)"}}, "Invalid period")
+		return
+	}
+	rows, err := h.svc.Export(c.Request.Context(), f, domain.TimesheetStatus(c.Query("status")))
+	if err != nil { mapError(c, err); return }
+
+	name := fmt.Sprintf("payroll_%d_%02d", *f.Year, *f.Month)
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		var b bytes.Buffer
+		if err := payroll.WriteCSV(&b, rows, h.svc.Columns()); err != nil {
+			resp.Internal(c, "gagal membuat CSV")
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+name+".csv")
+		c.Data(200, "text/csv; charset=utf-8", b.Bytes())
+	case "json":
+		c.Header("Content-Disposition", "attachment; filename="+name+".json")
+		c.JSON(200, payroll.Records(rows, h.svc.Columns()))
+	default:
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "format", Message: "csv atau json"}}, "Invalid format")
+	}
+}
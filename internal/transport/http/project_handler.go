@@ -0,0 +1,176 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type ProjectHandler struct{ svc *usecase.ProjectService }
+func NewProjectHandler(s *usecase.ProjectService) *ProjectHandler { return &ProjectHandler{svc: s} }
+
+func (h *ProjectHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	p := r.Group("/projects")
+	{
+		p.POST("", hr, h.createProject)
+		p.GET("", h.listProjects) // ?active=true
+		p.GET("/:id", h.getProject)
+		p.PUT("/:id", hr, h.updateProject)
+		p.DELETE("/:id", hr, h.deleteProject)
+		p.GET("/:id/tasks", h.listTasks)
+		p.POST("/:id/tasks", hr, h.createTask)
+	}
+
+	t := r.Group("/tasks")
+	{
+		t.GET("/:id", h.getTask)
+		t.PUT("/:id", hr, h.updateTask)
+		t.DELETE("/:id", hr, h.deleteTask)
+	}
+
+	r.GET("/reports/projects/:id/hours", h.hours) // ?from=YYYY-MM-DD&to=YYYY-MM-DD
+}
+
+// ====== Projects ======
+
+type projectReq struct {
+	Code        string `json:"code" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	ClientID    *int64 `json:"client_id"`
+	Active      *bool  `json:"active"` // default true
+}
+
+func (h *ProjectHandler) bindProject(c *gin.Context, id int64) (*domain.Project, bool) {
+	var req projectReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	p := &domain.Project{ID: id, Code: req.Code, Name: req.Name, Description: req.Description, ClientID: req.ClientID, Active: true}
+	if req.Active != nil { p.Active = *req.Active }
+	return p, true
+}
+
+func (h *ProjectHandler) createProject(c *gin.Context) {
+	p, ok := h.bindProject(c, 0)
+	if !ok { return }
+	id, err := h.svc.CreateProject(p)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Project created")
+}
+
+func (h *ProjectHandler) listProjects(c *gin.Context) {
+	items, err := h.svc.ListProjects(c.Query("active") == "true")
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *ProjectHandler) getProject(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	p, err := h.svc.GetProject(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, p, "Success")
+}
+
+func (h *ProjectHandler) updateProject(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	p, ok := h.bindProject(c, id)
+	if !ok { return }
+	if err := h.svc.UpdateProject(p); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Project updated")
+}
+
+func (h *ProjectHandler) deleteProject(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteProject(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// ====== Tasks ======
+
+type taskReq struct {
+	Name   string `json:"name" binding:"required"`
+	Active *bool  `json:"active"` // default true
+}
+
+func (h *ProjectHandler) bindTask(c *gin.Context, id, projectID int64) (*domain.Task, bool) {
+	var req taskReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return nil, false
+	}
+	t := &domain.Task{ID: id, ProjectID: projectID, Name: req.Name, Active: true}
+	if req.Active != nil { t.Active = *req.Active }
+	return t, true
+}
+
+func (h *ProjectHandler) createTask(c *gin.Context) {
+	projectID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	t, ok := h.bindTask(c, 0, projectID)
+	if !ok { return }
+	id, err := h.svc.CreateTask(t)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Task created")
+}
+
+func (h *ProjectHandler) listTasks(c *gin.Context) {
+	projectID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	items, err := h.svc.ListTasks(projectID)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *ProjectHandler) getTask(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	t, err := h.svc.GetTask(id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, t, "Success")
+}
+
+func (h *ProjectHandler) updateTask(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	t, ok := h.bindTask(c, id, 0)
+	if !ok { return }
+	if err := h.svc.UpdateTask(t); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Task updated")
+}
+
+func (h *ProjectHandler) deleteTask(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.DeleteTask(id); err != nil { mapError(c, err); return }
+	resp.NoContent(c)
+}
+
+// ====== Report ======
+
+func (h *ProjectHandler) hours(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	from, ok := optionalDate(c, "from")
+	if !ok { return }
+	to, ok := optionalDate(c, "to")
+	if !ok { return }
+	report, err := h.svc.Hours(c.Request.Context(), id, repository.ProjectHoursFilter{From: from, To: to})
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, report, "Success")
+}
+
+// optionalDate membaca query YYYY-MM-DD opsional; mengirim 400 sendiri jika formatnya salah.
+func optionalDate(c *gin.Context, name string) (*time.Time, bool) {
+	v := c.Query(name)
+	if v == "" { return nil, true }
+	d, err := usecase.ParseDate(v)
+	if err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: name, Message: "format YYYY-MM-DD"}}, "Invalid date")
+		return nil, false
+	}
+	return &d, true
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+	"timesheet-api/internal/usecase"
+	"timesheet-api/pkg/middleware"
+)
+
+type PunchHandler struct{ svc *usecase.PunchService }
+func NewPunchHandler(s *usecase.PunchService) *PunchHandler { return &PunchHandler{svc: s} }
+
+func (h *PunchHandler) Register(r *gin.Engine) {
+	hr := middleware.RequireRole(domain.RoleHRAdmin)
+	g := r.Group("/punch")
+	{
+		g.POST("/in", h.punchIn)
+		g.POST("/out", h.punchOut)
+		g.GET("/events", h.listPunches)          // ?employee_id=&from=YYYY-MM-DD&to=YYYY-MM-DD
+		g.POST("/auto-close", hr, h.autoClose) // untuk cron: tutup punch in yang lupa di-punch out
+	}
+}
+
+// punchReq: semua opsional; waktu punch selalu waktu server.
+type punchReq struct {
+	EmployeeID int64    `json:"employee_id"` // kosong = diri sendiri
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+	Device     string   `json:"device"`
+}
+
+type punchResponse struct {
+	Punch *domain.Punch          `json:"punch"`
+	Entry *domain.TimesheetEntry `json:"entry"`
+}
+
+func (h *PunchHandler) bindPunch(c *gin.Context) (usecase.PunchInput, bool) {
+	var req punchReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+			return usecase.PunchInput{}, false
+		}
+	}
+	if req.Device == "" { req.Device = c.GetHeader("User-Agent") }
+	return usecase.PunchInput{
+		EmployeeID: req.EmployeeID, Latitude: req.Latitude, Longitude: req.Longitude, Device: req.Device, IP: c.ClientIP(),
+	}, true
+}
+
+func (h *PunchHandler) punchIn(c *gin.Context) {
+	in, ok := h.bindPunch(c)
+	if !ok { return }
+	p, e, err := h.svc.PunchIn(c.Request.Context(), in)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, punchResponse{Punch: p, Entry: e}, "Punched in")
+}
+
+func (h *PunchHandler) punchOut(c *gin.Context) {
+	in, ok := h.bindPunch(c)
+	if !ok { return }
+	p, e, err := h.svc.PunchOut(c.Request.Context(), in)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, punchResponse{Punch: p, Entry: e}, "Punched out")
+}
+
+func (h *PunchHandler) listPunches(c *gin.Context) {
+	from, err1 := usecase.ParseDate(c.Query("from"))
+	to, err2 := usecase.ParseDate(c.Query("to"))
+	if err1 != nil || err2 != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "from/to", Message: "wajib diisi, format YYYY-MM-DD"}}, "Invalid date")
+		return
+	}
+	employeeID, _ := strconv.ParseInt(c.Query("employee_id"), 10, 64) // kosong = diri sendiri
+	items, err := h.svc.ListPunches(c.Request.Context(), employeeID, from, to.Add(24*time.Hour))
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *PunchHandler) autoClose(c *gin.Context) {
+	n, err := h.svc.AutoCloseStale(c.Request.Context())
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"closed": n}, "Stale punches closed")
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"archive/zip"
 	"bytes"
-	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,10 +27,23 @@ func (h *TimesheetHandler) Register(r *gin.Engine) {
 	{
 		ts.POST("", h.createTimesheet)
 		ts.GET("", h.listTimesheets)
+		ts.GET("/export.pdf", h.exportTimesheetsPDF) // filter sama dengan list, 1 PDF gabungan
+		ts.GET("/export.zip", h.exportTimesheetsZIP) // filter sama dengan list, 1 PDF per karyawan
+		ts.GET("/export.xlsx", h.exportTimesheetsXLSX) // filter sama dengan list, 1 sheet per karyawan
 		ts.GET("/:id", h.getTimesheet)
+		ts.GET("/:id/export.pdf", h.exportTimesheetPDF)
+		ts.GET("/:id/export.xlsx", h.exportTimesheetXLSX)
 		ts.PUT("/:id", h.updateTimesheet)
 		ts.DELETE("/:id", h.deleteTimesheet)
-		// JANGAN buat apa pun di bawah ts.POST("/:id/...") — itu yang bikin panic
+
+		// Sub-route wajib pakai nama param yang sama (:id); beda nama param → gin panic.
+		ts.POST("/:id/submit", h.submitTimesheet)
+		ts.POST("/:id/approve", h.approveTimesheet)
+		ts.POST("/:id/reject", h.rejectTimesheet)   // body: {"reason": "..."}
+		ts.POST("/:id/reopen", h.reopenTimesheet)   // body opsional: {"reason": "..."}
+		ts.GET("/:id/status-history", h.statusHistory)
+		ts.GET("/:id/history", h.history) // audit trail lengkap
+		ts.POST("/:id/recalculate-overtime", h.recalculateOvertime)
 	}
 
 	entries := r.Group("/entries")
@@ -41,47 +56,127 @@ func (h *TimesheetHandler) Register(r *gin.Engine) {
 
 // ====== Request/Response DTO ======
 
-type createTimesheetReq struct {
-	EmployeeName     string `json:"employee_name" binding:"required"`
-	Department       string `json:"department"`
+type updateTimesheetReq struct {
+	EmployeeID       int64  `json:"employee_id"`
+	EmployeeName     string `json:"employee_name"` // fallback jika employee_id kosong
+	DepartmentID     *int64 `json:"department_id"` // default: departemen karyawan
 	Month            int    `json:"month" binding:"required"`
 	Year             int    `json:"year" binding:"required"`
 	TotalWorkingDays *int   `json:"total_working_days"`
 }
-type updateTimesheetReq createTimesheetReq
+type createTimesheetReq struct {
+	updateTimesheetReq
+	Entries []entryReq `json:"entries"` // opsional; dibuat bersama timesheet, semua atau tidak sama sekali
+}
+
+type statusReq struct {
+	Reason string `json:"reason"`
+}
 
 type entryReq struct {
-	Date          string   `json:"date" binding:"required"`
-	StartTime     string   `json:"start_time"`
-	EndTime       string   `json:"end_time"`
-	TotalHours    *float64 `json:"total_hours"`
-	OvertimeHours *float64 `json:"overtime_hours"`
-	Remarks       string   `json:"remarks"`
+	Date          string           `json:"date" binding:"required"`
+	StartTime     string           `json:"start_time"`
+	EndTime       string           `json:"end_time"`
+	TotalHours    *float64         `json:"total_hours"`
+	OvertimeHours *float64         `json:"overtime_hours"`
+	Remarks       string           `json:"remarks"`
+	EntryType     domain.EntryType `json:"entry_type"`    // work (default), leave, absent
+	LeaveTypeID   *int64           `json:"leave_type_id"` // wajib untuk entry_type leave
+	// Segments, jika diisi, menggantikan start_time/end_time/total_hours.
+	Segments []segmentReq `json:"segments"`
+	// Allocations membagi total_hours ke project/task; untuk entry bersegmen
+	// isi project_id di segmennya saja.
+	Allocations []allocationReq `json:"allocations"`
+	Billable    bool            `json:"billable"`
+	ClientID    *int64          `json:"client_id"` // kosong = klien project alokasi
+}
+
+type segmentReq struct {
+	Type          domain.SegmentType `json:"type"` // work (default), break, travel, on_call
+	StartTime     string             `json:"start_time"`
+	EndTime       string             `json:"end_time"`
+	StartsNextDay bool               `json:"starts_next_day"`
+	Remarks       string             `json:"remarks"`
+	ProjectID     *int64             `json:"project_id"`
+	TaskID        *int64             `json:"task_id"`
+}
+
+type allocationReq struct {
+	ProjectID int64   `json:"project_id"`
+	TaskID    *int64  `json:"task_id"`
+	Hours     float64 `json:"hours"`
+}
+
+func toAllocations(reqs []allocationReq) []domain.EntryAllocation {
+	var out []domain.EntryAllocation
+	for _, r := range reqs {
+		out = append(out, domain.EntryAllocation{ProjectID: r.ProjectID, TaskID: r.TaskID, Hours: r.Hours})
+	}
+	return out
+}
+
+// parseSegments mengirim 400 sendiri jika ada jam yang tidak valid.
+func parseSegments(c *gin.Context, reqs []segmentReq) ([]domain.EntrySegment, bool) {
+	out := make([]domain.EntrySegment, 0, len(reqs))
+	for i, r := range reqs {
+		st, err1 := usecase.ParseTime(strings.ReplaceAll(r.StartTime, ".", ":"))
+		et, err2 := usecase.ParseTime(strings.ReplaceAll(r.EndTime, ".", ":"))
+		if err1 != nil || err2 != nil || st == nil || et == nil {
+			field := fmt.Sprintf("segments[%d]", i)
+			resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: field, Message: "format HH:MM atau HH:MM:SS"}}, "Invalid segment time")
+			return nil, false
+		}
+		out = append(out, domain.EntrySegment{Type: r.Type, StartTime: *st, EndTime: *et, StartsNextDay: r.StartsNextDay, Remarks: r.Remarks,
+			ProjectID: r.ProjectID, TaskID: r.TaskID})
+	}
+	return out, true
 }
 
 type entryResponse struct {
-	ID            int64    `json:"id"`
-	Date          string   `json:"date"`
-	DayName       string   `json:"day_name"`
-	StartTime     *string  `json:"start_time,omitempty"`
-	EndTime       *string  `json:"end_time,omitempty"`
-	TotalHours    *float64 `json:"total_hours,omitempty"`
-	OvertimeHours *float64 `json:"overtime_hours,omitempty"`
-	Remarks       string   `json:"remarks,omitempty"`
+	ID            int64                     `json:"id"`
+	Date          string                    `json:"date"`
+	DayName       string                    `json:"day_name"`
+	StartTime     *string                   `json:"start_time,omitempty"`
+	EndTime       *string                   `json:"end_time,omitempty"`
+	TotalHours    *float64                  `json:"total_hours,omitempty"`
+	OvertimeHours *float64                  `json:"overtime_hours,omitempty"`
+	Remarks       string                    `json:"remarks,omitempty"`
+	EntryType     domain.EntryType          `json:"entry_type"`
+	LeaveTypeID   *int64                    `json:"leave_type_id,omitempty"`
+	Overtime      *domain.OvertimeBreakdown `json:"overtime,omitempty"`
+	Holiday       string                    `json:"holiday,omitempty"`
+	EndsNextDay   bool                      `json:"ends_next_day"`
+	NightHours    *float64                  `json:"night_hours,omitempty"`
+	Segments      []segmentResponse         `json:"segments,omitempty"`
+	Allocations   []domain.EntryAllocation  `json:"allocations,omitempty"`
+	Billable      bool                      `json:"billable"`
+	ClientID      *int64                    `json:"client_id,omitempty"`
+	Flagged       bool                      `json:"flagged"`
+	FlagReason    string                    `json:"flag_reason,omitempty"`
+}
+type segmentResponse struct {
+	Type          domain.SegmentType `json:"type"`
+	StartTime     string             `json:"start_time"`
+	EndTime       string             `json:"end_time"`
+	StartsNextDay bool               `json:"starts_next_day,omitempty"`
+	Remarks       string             `json:"remarks,omitempty"`
+	ProjectID     *int64             `json:"project_id,omitempty"`
+	TaskID        *int64             `json:"task_id,omitempty"`
 }
 type timesheetResponse struct {
-	ID               int64           `json:"id"`
-	EmployeeName     string          `json:"employee_name"`
-	Department       string          `json:"department"`
-	Month            int             `json:"month"`
-	Year             int             `json:"year"`
-	TotalWorkingDays *int            `json:"total_working_days,omitempty"`
-	Summary          struct {
-		DaysFilled    int64   `json:"days_filled"`
-		TotalHours    float64 `json:"total_hours"`
-		OvertimeHours float64 `json:"overtime_hours"`
-	} `json:"summary"`
-	Entries []entryResponse `json:"entries"`
+	ID               int64                  `json:"id"`
+	EmployeeID       int64                  `json:"employee_id"`
+	EmployeeName     string                 `json:"employee_name"`
+	DepartmentID     *int64                 `json:"department_id,omitempty"`
+	Department       string                 `json:"department"`
+	Month            int                    `json:"month"`
+	Year             int                    `json:"year"`
+	TotalWorkingDays *int                   `json:"total_working_days,omitempty"`
+	Status           domain.TimesheetStatus `json:"status"`
+	StatusChangedAt  *time.Time             `json:"status_changed_at,omitempty"`
+	StatusChangedBy  *string                `json:"status_changed_by,omitempty"`
+	Summary          domain.TimesheetStats  `json:"summary"`
+	Entries          []entryResponse        `json:"entries"`
 }
 
 // ====== Handlers ======
@@ -93,55 +188,60 @@ func (h *TimesheetHandler) createTimesheet(c *gin.Context) {
 		return
 	}
 	ts := domain.Timesheet{
+		EmployeeID:       req.EmployeeID,
 		EmployeeName:     req.EmployeeName,
-		Department:       req.Department,
+		DepartmentID:     req.DepartmentID,
 		Month:            req.Month,
 		Year:             req.Year,
 		TotalWorkingDays: req.TotalWorkingDays,
 	}
-	id, err := h.svc.CreateTimesheet(&ts)
-	if err != nil { h.mapError(c, err); return }
+	for i, er := range req.Entries {
+		e, ok := newEntry(c, er, fmt.Sprintf("entries[%d].", i))
+		if !ok { return }
+		ts.Entries = append(ts.Entries, *e)
+	}
+	id, err := h.svc.CreateTimesheet(c.Request.Context(), &ts)
+	if err != nil { mapError(c, err); return }
 	resp.Created(c, gin.H{"id": id}, "Timesheet created")
 }
 
+// listTimesheets: JSON per halaman (default), atau di-stream utuh sebagai
+// CSV/NDJSON sesuai header Accept (lihat timesheet_stream.go). Accept yang tidak
+// cocok dengan format mana pun (mis. text/html) juga dijawab JSON.
 func (h *TimesheetHandler) listTimesheets(c *gin.Context) {
-	name := c.Query("employee_name")
-	var mptr, yptr *int
-	if v := c.Query("month"); v != "" { if n, err := strconv.Atoi(v); err == nil { mptr = &n } }
-	if v := c.Query("year");  v != "" { if n, err := strconv.Atoi(v); err == nil { yptr = &n } }
-	items, err := h.svc.ListTimesheets(repository.Filter{EmployeeName: name, Month: mptr, Year: yptr})
-	if err != nil { h.mapError(c, err); return }
-	resp.OK(c, items, "Success")
+	switch f := c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeNDJSON); f {
+	case mimeCSV, mimeNDJSON:
+		h.streamTimesheets(c, f)
+		return
+	}
+	f, errs := pageFilter(c)
+	if errs != nil { resp.BadRequest(c, errs, "Invalid query"); return }
+	items, total, err := h.svc.PageTimesheets(c.Request.Context(), f)
+	if err != nil { mapError(c, err); return }
+	resp.OKPage(c, items, "Success", pageMeta(f, items, total))
 }
 
 func (h *TimesheetHandler) getTimesheet(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	ts, err := h.svc.GetTimesheet(id)
-	if err != nil { h.mapError(c, err); return }
+	ts, err := h.svc.GetTimesheet(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
 
 	// Summary
-	days, th, oh, err := h.svc.Stats(id)
-	if err != nil { h.mapError(c, err); return }
+	stats, err := h.svc.Stats(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
 
 	// Map entries + day name
 	ers := make([]entryResponse, 0, len(ts.Entries))
 	for _, e := range ts.Entries {
-		var st, et *string
-		if e.StartTime != nil { s := e.StartTime.Format("15:04:05"); st = &s }
-		if e.EndTime   != nil { s := e.EndTime.Format("15:04:05");   et = &s }
-		dayName := indoDayName(e.WorkDate.Weekday())
-		ers = append(ers, entryResponse{
-			ID: e.ID, Date: e.WorkDate.Format("2006-01-02"), DayName: dayName,
-			StartTime: st, EndTime: et, TotalHours: e.TotalHours, OvertimeHours: e.OvertimeHours, Remarks: e.Remarks,
-		})
+		ers = append(ers, toEntryResponse(e))
 	}
 	out := timesheetResponse{
-		ID: ts.ID, EmployeeName: ts.EmployeeName, Department: ts.Department,
-		Month: ts.Month, Year: ts.Year, TotalWorkingDays: ts.TotalWorkingDays, Entries: ers,
+		ID: ts.ID, EmployeeID: ts.EmployeeID, EmployeeName: ts.EmployeeName,
+		DepartmentID: ts.DepartmentID, Department: ts.Department,
+		Month: ts.Month, Year: ts.Year, TotalWorkingDays: ts.TotalWorkingDays,
+		Status: ts.Status, StatusChangedAt: ts.StatusChangedAt, StatusChangedBy: ts.StatusChangedBy,
+		Summary: *stats, Entries: ers,
 	}
-	out.Summary.DaysFilled = days
-	out.Summary.TotalHours = th
-	out.Summary.OvertimeHours = oh
 	resp.OK(c, out, "Success")
 }
 
@@ -154,22 +254,74 @@ func (h *TimesheetHandler) updateTimesheet(c *gin.Context) {
 	}
 	ts := domain.Timesheet{
 		ID:               id,
+		EmployeeID:       req.EmployeeID,
 		EmployeeName:     req.EmployeeName,
-		Department:       req.Department,
+		DepartmentID:     req.DepartmentID,
 		Month:            req.Month,
 		Year:             req.Year,
 		TotalWorkingDays: req.TotalWorkingDays,
 	}
-	if err := h.svc.UpdateTimesheet(&ts); err != nil { h.mapError(c, err); return }
+	if err := h.svc.UpdateTimesheet(c.Request.Context(), &ts); err != nil { mapError(c, err); return }
 	resp.OK(c, gin.H{"id": id}, "Timesheet updated")
 }
 
 func (h *TimesheetHandler) deleteTimesheet(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err := h.svc.DeleteTimesheet(id); err != nil { h.mapError(c, err); return }
+	if err := h.svc.DeleteTimesheet(c.Request.Context(), id); err != nil { mapError(c, err); return }
 	resp.NoContent(c)
 }
 
+func (h *TimesheetHandler) submitTimesheet(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.Submit(c.Request.Context(), id); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.StatusSubmitted}, "Timesheet submitted")
+}
+
+func (h *TimesheetHandler) approveTimesheet(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.Approve(c.Request.Context(), id); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.StatusApproved}, "Timesheet approved")
+}
+
+func (h *TimesheetHandler) rejectTimesheet(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req statusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
+		return
+	}
+	if err := h.svc.Reject(c.Request.Context(), id, req.Reason); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.StatusRejected}, "Timesheet rejected")
+}
+
+func (h *TimesheetHandler) reopenTimesheet(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req statusReq
+	_ = c.ShouldBindJSON(&req) // body opsional
+	if err := h.svc.Reopen(c.Request.Context(), id, req.Reason); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id, "status": domain.StatusReopened}, "Timesheet reopened")
+}
+
+func (h *TimesheetHandler) statusHistory(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	items, err := h.svc.StatusHistory(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *TimesheetHandler) history(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	items, err := h.svc.History(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
+	resp.OK(c, items, "Success")
+}
+
+func (h *TimesheetHandler) recalculateOvertime(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := h.svc.RecalculateOvertime(c.Request.Context(), id); err != nil { mapError(c, err); return }
+	resp.OK(c, gin.H{"id": id}, "Overtime recalculated")
+}
+
 func (h *TimesheetHandler) addEntry(c *gin.Context) {
 	// Ambil timesheet_id dari QUERY (bukan nested route)
 	tsIDStr := c.Query("timesheet_id")
@@ -188,35 +340,50 @@ func (h *TimesheetHandler) addEntry(c *gin.Context) {
 		resp.Unprocessable(c, []resp.ErrorDetail{{Type: "validation_error", Field: "body", Message: err.Error()}}, "Invalid payload")
 		return
 	}
+	e, ok := newEntry(c, req, "")
+	if !ok { return }
+	e.TimesheetID = tsID
+	id, err := h.svc.AddEntry(c.Request.Context(), e)
+	if err != nil { mapError(c, err); return }
+	resp.Created(c, gin.H{"id": id}, "Entry created")
+}
+
+// newEntry mengubah entryReq menjadi entry baru; error validasi ditulis ke
+// response dengan nama field diawali prefix (mis. "entries[0].").
+func newEntry(c *gin.Context, req entryReq, prefix string) (*domain.TimesheetEntry, bool) {
 	st := strings.ReplaceAll(req.StartTime, ".", ":")
 	et := strings.ReplaceAll(req.EndTime, ".", ":")
 
 	d, err := usecase.ParseDate(req.Date)
 	if err != nil {
-		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "date", Message: "format YYYY-MM-DD"}}, "Invalid date")
-		return
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: prefix + "date", Message: "format YYYY-MM-DD"}}, "Invalid date")
+		return nil, false
 	}
 	stp, err := usecase.ParseTime(st); if err != nil && st != "" {
-		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "start_time", Message: "format HH:MM atau HH:MM:SS"}}, "Invalid start_time")
-		return
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: prefix + "start_time", Message: "format HH:MM atau HH:MM:SS"}}, "Invalid start_time")
+		return nil, false
 	}
 	etp, err := usecase.ParseTime(et); if err != nil && et != "" {
-		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "end_time", Message: "format HH:MM atau HH:MM:SS"}}, "Invalid end_time")
-		return
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: prefix + "end_time", Message: "format HH:MM atau HH:MM:SS"}}, "Invalid end_time")
+		return nil, false
 	}
+	segments, ok := parseSegments(c, req.Segments)
+	if !ok { return nil, false }
 
-	e := domain.TimesheetEntry{
-		TimesheetID:   tsID,
+	return &domain.TimesheetEntry{
 		WorkDate:      d,
 		StartTime:     stp,
 		EndTime:       etp,
 		TotalHours:    req.TotalHours,
 		OvertimeHours: req.OvertimeHours,
 		Remarks:       req.Remarks,
-	}
-	id, err := h.svc.AddEntry(&e)
-	if err != nil { h.mapError(c, err); return }
-	resp.Created(c, gin.H{"id": id}, "Entry created")
+		Type:          req.EntryType,
+		LeaveTypeID:   req.LeaveTypeID,
+		Segments:      segments,
+		Allocations:   toAllocations(req.Allocations),
+		Billable:      req.Billable,
+		ClientID:      req.ClientID,
+	}, true
 }
 
 func (h *TimesheetHandler) updateEntry(c *gin.Context) {
@@ -245,6 +412,8 @@ func (h *TimesheetHandler) updateEntry(c *gin.Context) {
 		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "end_time", Message: "format HH:MM atau HH:MM:SS"}}, "Invalid end_time")
 		return
 	}
+	segments, ok := parseSegments(c, req.Segments)
+	if !ok { return }
 
 	e := domain.TimesheetEntry{
 		ID:            entryID,
@@ -254,14 +423,20 @@ func (h *TimesheetHandler) updateEntry(c *gin.Context) {
 		TotalHours:    req.TotalHours,
 		OvertimeHours: req.OvertimeHours,
 		Remarks:       req.Remarks,
+		Type:          req.EntryType,
+		LeaveTypeID:   req.LeaveTypeID,
+		Segments:      segments,
+		Allocations:   toAllocations(req.Allocations),
+		Billable:      req.Billable,
+		ClientID:      req.ClientID,
 	}
-	if err := h.svc.UpdateEntry(&e); err != nil { h.mapError(c, err); return }
+	if err := h.svc.UpdateEntry(c.Request.Context(), &e); err != nil { mapError(c, err); return }
 	resp.OK(c, gin.H{"id": entryID}, "Entry updated")
 }
 
 func (h *TimesheetHandler) deleteEntry(c *gin.Context) {
 	entryID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	if err := h.svc.DeleteEntry(entryID); err != nil { h.mapError(c, err); return }
+	if err := h.svc.DeleteEntry(c.Request.Context(), entryID); err != nil { mapError(c, err); return }
 	resp.NoContent(c)
 }
 
@@ -269,14 +444,137 @@ func (h *TimesheetHandler) deleteEntry(c *gin.Context) {
 
 func (h *TimesheetHandler) exportTimesheetPDF(c *gin.Context) {
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	ts, err := h.svc.GetTimesheet(id)
-	if err != nil { h.mapError(c, err); return }
+	ts, err := h.svc.GetTimesheet(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
 
+	pdf := newTimesheetPDF()
+	renderTimesheetPDF(pdf, ts)
+
+	var b bytes.Buffer
+	if err := pdf.Output(&b); err != nil {
+		resp.Internal(c, "gagal membuat PDF")
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=timesheet_%d_%02d_%d.pdf", ts.Year, ts.Month, ts.ID))
+	c.Data(200, "application/pdf", b.Bytes())
+}
+
+// exportTimesheetsPDF menggabungkan semua timesheet hasil filter ke satu PDF
+// (satu karyawan per halaman) untuk dicetak HR di akhir bulan.
+func (h *TimesheetHandler) exportTimesheetsPDF(c *gin.Context) {
+	items, err := h.svc.ExportTimesheets(c.Request.Context(), listFilter(c))
+	if err != nil { mapError(c, err); return }
+	if len(items) == 0 {
+		resp.NotFound(c, "Tidak ada timesheet untuk filter ini")
+		return
+	}
+
+	pdf := newTimesheetPDF()
+	for i := range items {
+		renderTimesheetPDF(pdf, &items[i])
+	}
+
+	var b bytes.Buffer
+	if err := pdf.Output(&b); err != nil {
+		resp.Internal(c, "gagal membuat PDF")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+batchFileName(c, "pdf"))
+	c.Data(200, "application/pdf", b.Bytes())
+}
+
+// exportTimesheetsZIP sama seperti exportTimesheetsPDF, tapi dipecah satu PDF
+// per karyawan di dalam satu arsip ZIP; semua timesheet karyawan itu (urut
+// periode) ada di PDF-nya, seperti satu sheet per karyawan di XLSX.
+func (h *TimesheetHandler) exportTimesheetsZIP(c *gin.Context) {
+	items, err := h.svc.ExportTimesheets(c.Request.Context(), listFilter(c))
+	if err != nil { mapError(c, err); return }
+	if len(items) == 0 {
+		resp.NotFound(c, "Tidak ada timesheet untuk filter ini")
+		return
+	}
+
+	var order []int64
+	byEmployee := map[int64][]*domain.Timesheet{}
+	for i := range items {
+		ts := &items[i]
+		if _, ok := byEmployee[ts.EmployeeID]; !ok { order = append(order, ts.EmployeeID) }
+		byEmployee[ts.EmployeeID] = append(byEmployee[ts.EmployeeID], ts)
+	}
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, empID := range order {
+		sheets := byEmployee[empID]
+		sort.SliceStable(sheets, func(i, j int) bool {
+			if sheets[i].Year != sheets[j].Year { return sheets[i].Year < sheets[j].Year }
+			return sheets[i].Month < sheets[j].Month
+		})
+		pdf := newTimesheetPDF()
+		for _, ts := range sheets {
+			renderTimesheetPDF(pdf, ts)
+		}
+
+		name := fmt.Sprintf("timesheet_%s_%d.pdf", slugify(sheets[0].EmployeeName), empID)
+		w, err := zw.Create(name)
+		if err != nil { resp.Internal(c, "gagal membuat ZIP"); return }
+		if err := pdf.Output(w); err != nil {
+			resp.Internal(c, "gagal membuat PDF")
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		resp.Internal(c, "gagal membuat ZIP")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+batchFileName(c, "zip"))
+	c.Data(200, "application/zip", b.Bytes())
+}
+
+func (h *TimesheetHandler) exportTimesheetXLSX(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ts, err := h.svc.GetTimesheet(c.Request.Context(), id)
+	if err != nil { mapError(c, err); return }
+	h.writeXLSX(c, fmt.Sprintf("timesheet_%d_%02d_%d.xlsx", ts.Year, ts.Month, ts.ID), []domain.Timesheet{*ts})
+}
+
+// exportTimesheetsXLSX: satu workbook untuk semua timesheet hasil filter,
+// satu sheet per karyawan (beberapa bulan ditulis berurutan di sheet yang sama).
+func (h *TimesheetHandler) exportTimesheetsXLSX(c *gin.Context) {
+	items, err := h.svc.ExportTimesheets(c.Request.Context(), listFilter(c))
+	if err != nil { mapError(c, err); return }
+	if len(items) == 0 {
+		resp.NotFound(c, "Tidak ada timesheet untuk filter ini")
+		return
+	}
+	h.writeXLSX(c, batchFileName(c, "xlsx"), items)
+}
+
+func (h *TimesheetHandler) writeXLSX(c *gin.Context, name string, items []domain.Timesheet) {
+	x, err := newTimesheetXLSX()
+	if err != nil { resp.Internal(c, "gagal membuat XLSX"); return }
+	for i := range items {
+		if err := x.add(&items[i]); err != nil { resp.Internal(c, "gagal membuat XLSX"); return }
+	}
+	var b bytes.Buffer
+	if err := x.write(&b); err != nil {
+		resp.Internal(c, "gagal membuat XLSX")
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Data(200, xlsxContentType, b.Bytes())
+}
+
+func newTimesheetPDF() *gofpdf.Fpdf {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(10, 12, 10)
-	pdf.AddPage()
 	pdf.SetAutoPageBreak(true, 10)
+	return pdf
+}
 
+// renderTimesheetPDF menulis satu timesheet mulai dari halaman baru.
+func renderTimesheetPDF(pdf *gofpdf.Fpdf, ts *domain.Timesheet) {
+	pdf.AddPage()
 	// Title
 	pdf.SetFont("Helvetica", "B", 16)
 	pdf.Cell(0, 8, "ABSENSI KEHADIRAN / TIME SHEET")
@@ -319,25 +617,31 @@ func (h *TimesheetHandler) exportTimesheetPDF(c *gin.Context) {
 
 	// Body
 	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFillColor(253, 226, 226) // baris hari libur: latar merah muda + nama libur di keterangan
 	var totalHrs, totalOT float64
 	for _, e := range ts.Entries {
 		date := e.WorkDate.Format("2006-01-02")
 		day := indoDayName(e.WorkDate.Weekday())
+		holiday := e.Holiday != ""
 		var st, et string
 		if e.StartTime != nil { st = e.StartTime.Format("15:04") } else { st = "-" }
 		if e.EndTime != nil   { et = e.EndTime.Format("15:04")   } else { et = "-" }
+		if e.EndsNextDay { et += " (+1)" }
 		var th, ot string
 		if e.TotalHours != nil { th = fmt.Sprintf("%.2f", *e.TotalHours); totalHrs += *e.TotalHours } else { th = "-" }
 		if e.OvertimeHours != nil { ot = fmt.Sprintf("%.2f", *e.OvertimeHours); totalOT += *e.OvertimeHours } else { ot = "-" }
 		remarks := e.Remarks
+		if holiday {
+			remarks = strings.TrimSpace("Libur: " + e.Holiday + ". " + remarks)
+		}
 
-		pdf.CellFormat(cols[0].Width, 8, date, "1", 0, "C", false, 0, "")
-		pdf.CellFormat(cols[1].Width, 8, day,  "1", 0, "C", false, 0, "")
-		pdf.CellFormat(cols[2].Width, 8, st,   "1", 0, "C", false, 0, "")
-		pdf.CellFormat(cols[3].Width, 8, et,   "1", 0, "C", false, 0, "")
-		pdf.CellFormat(cols[4].Width, 8, th,   "1", 0, "C", false, 0, "")
-		pdf.CellFormat(cols[5].Width, 8, ot,   "1", 0, "C", false, 0, "")
-		pdf.MultiCell(cols[6].Width, 8, remarks, "1", "L", false)
+		pdf.CellFormat(cols[0].Width, 8, date, "1", 0, "C", holiday, 0, "")
+		pdf.CellFormat(cols[1].Width, 8, day,  "1", 0, "C", holiday, 0, "")
+		pdf.CellFormat(cols[2].Width, 8, st,   "1", 0, "C", holiday, 0, "")
+		pdf.CellFormat(cols[3].Width, 8, et,   "1", 0, "C", holiday, 0, "")
+		pdf.CellFormat(cols[4].Width, 8, th,   "1", 0, "C", holiday, 0, "")
+		pdf.CellFormat(cols[5].Width, 8, ot,   "1", 0, "C", holiday, 0, "")
+		pdf.MultiCell(cols[6].Width, 8, remarks, "1", "L", holiday)
 	}
 
 	// Total
@@ -346,30 +650,36 @@ func (h *TimesheetHandler) exportTimesheetPDF(c *gin.Context) {
 	pdf.CellFormat(cols[4].Width, 8, fmt.Sprintf("%.2f", totalHrs), "1", 0, "C", false, 0, "")
 	pdf.CellFormat(cols[5].Width, 8, fmt.Sprintf("%.2f", totalOT),  "1", 0, "C", false, 0, "")
 	pdf.CellFormat(cols[6].Width, 8, "", "1", 1, "L", false, 0, "")
-
-	var b bytes.Buffer
-	if err := pdf.Output(&b); err != nil {
-		resp.Internal(c, "gagal membuat PDF")
-		return
-	}
-	c.Header("Content-Type", "application/pdf")
-	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=timesheet_%d_%02d_%d.pdf", ts.Year, ts.Month, ts.ID))
-	c.Data(200, "application/pdf", b.Bytes())
 }
 
 // ====== Helpers ======
 
-func (h *TimesheetHandler) mapError(c *gin.Context, err error) {
-	switch {
-	case errors.Is(err, domain.ErrInvalidInput):
-		resp.BadRequest(c, fmt.Sprintf("%v", err), "Invalid input")
-	case errors.Is(err, domain.ErrNotFound):
-		resp.NotFound(c, "Not found")
-	case errors.Is(err, domain.ErrDuplicate):
-		resp.Conflict(c, "Duplicate")
-	default:
-		resp.Internal(c, "Internal error")
+// listFilter membaca query filter yang dipakai bersama oleh list & export batch.
+func listFilter(c *gin.Context) repository.Filter {
+	f := repository.Filter{
+		EmployeeName: c.Query("employee_name"),
+		Department:   c.Query("department"),
 	}
+	if v := c.Query("employee_id"); v != "" { if n, err := strconv.ParseInt(v, 10, 64); err == nil { f.EmployeeID = &n } }
+	if v := c.Query("department_id"); v != "" { if n, err := strconv.ParseInt(v, 10, 64); err == nil { f.DepartmentID = &n } }
+	if v := c.Query("month"); v != "" { if n, err := strconv.Atoi(v); err == nil { f.Month = &n } }
+	if v := c.Query("year");  v != "" { if n, err := strconv.Atoi(v); err == nil { f.Year = &n } }
+	return f
+}
+
+func batchFileName(c *gin.Context, ext string) string {
+	name := "timesheets"
+	if v := c.Query("year"); v != "" { name += "_" + v }
+	if v := c.Query("month"); v != "" { name += "_" + v }
+	return name + "." + ext
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	s = strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(s), "_"), "_")
+	if s == "" { return "unknown" }
+	return s
 }
 
 func indoMonth(m int) string {
@@ -377,6 +687,31 @@ func indoMonth(m int) string {
 	if m >= 1 && m <= 12 { return names[m] }
 	return fmt.Sprintf("Bulan-%d", m)
 }
+
+func toEntryResponse(e domain.TimesheetEntry) entryResponse {
+	var st, et *string
+	if e.StartTime != nil { s := e.StartTime.Format("15:04:05"); st = &s }
+	if e.EndTime   != nil { s := e.EndTime.Format("15:04:05");   et = &s }
+	var segs []segmentResponse
+	for _, sg := range e.Segments {
+		segs = append(segs, segmentResponse{Type: sg.Type, StartTime: sg.StartTime.Format("15:04:05"), EndTime: sg.EndTime.Format("15:04:05"),
+			StartsNextDay: sg.StartsNextDay, Remarks: sg.Remarks, ProjectID: sg.ProjectID, TaskID: sg.TaskID})
+	}
+	return entryResponse{
+		ID: e.ID, Date: e.WorkDate.Format("2006-01-02"), DayName: dayLabel(e),
+		StartTime: st, EndTime: et, TotalHours: e.TotalHours, OvertimeHours: e.OvertimeHours, Remarks: e.Remarks,
+		EntryType: e.Type, LeaveTypeID: e.LeaveTypeID, Overtime: e.Overtime, Holiday: e.Holiday,
+		EndsNextDay: e.EndsNextDay, NightHours: e.NightHours, Segments: segs, Allocations: e.Allocations, Billable: e.Billable, ClientID: e.ClientID,
+		Flagged: e.Flagged, FlagReason: e.FlagReason,
+	}
+}
+
+// dayLabel: nama hari, ditandai "(Libur)" jika tanggalnya ada di kalender libur.
+func dayLabel(e domain.TimesheetEntry) string {
+	if e.Holiday != "" { return indoDayName(e.WorkDate.Weekday()) + " (Libur)" }
+	return indoDayName(e.WorkDate.Weekday())
+}
+
 func indoDayName(w time.Weekday) string {
 	switch w {
 	case time.Monday: return "Senin"
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+)
+
+const (
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// streamFlushEvery: jumlah timesheet sebelum buffer response di-flush ke client.
+const streamFlushEvery = 100
+
+// rowEncoder menulis satu format stream; flush mengosongkan buffer encoder
+// (bukan koneksi).
+type rowEncoder interface {
+	header() error
+	write(ts *domain.Timesheet) error
+	flush() error
+}
+
+// streamTimesheets menulis hasil list langsung dari cursor repository, tanpa
+// mengumpulkan semua timesheet di memori (tanpa paging; ?sort tetap berlaku).
+// ?include=entries → entries ikut.
+// Header response baru dikirim saat baris pertama siap, jadi error sebelum itu
+// (akses, query) masih dijawab dengan status yang sesuai.
+func (h *TimesheetHandler) streamTimesheets(c *gin.Context, format string) {
+	f := listFilter(c)
+	var err error
+	if f.Sort, err = parseSort(c.Query("sort")); err != nil {
+		resp.BadRequest(c, []resp.ErrorDetail{{Type: "validation_error", Field: "sort", Message: err.Error()}}, "Invalid query")
+		return
+	}
+	withEntries := c.Query("include") == "entries"
+	var enc rowEncoder
+	if format == mimeCSV {
+		enc = &csvEncoder{w: csv.NewWriter(c.Writer), withEntries: withEntries}
+	} else {
+		enc = &ndjsonEncoder{enc: json.NewEncoder(c.Writer)}
+	}
+
+	started := false
+	start := func() error {
+		if started { return nil }
+		started = true
+		c.Header("Content-Type", format+"; charset=utf-8")
+		if format == mimeCSV { c.Header("Content-Disposition", `attachment; filename="`+batchFileName(c, "csv")+`"`) }
+		c.Status(200)
+		return enc.header()
+	}
+	n := 0
+	err = h.svc.StreamTimesheets(c.Request.Context(), f, withEntries, func(ts *domain.Timesheet) error {
+		if err := start(); err != nil { return err }
+		if err := enc.write(ts); err != nil { return err }
+		if n++; n%streamFlushEvery == 0 {
+			if err := enc.flush(); err != nil { return err }
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil { err = start() }
+	if err == nil { err = enc.flush() }
+	if err != nil && !started { mapError(c, err); return }
+	if err != nil {
+		// Status sudah terkirim; response berhenti di baris terakhir yang berhasil.
+		_ = c.Error(err)
+	}
+}
+
+// csvTimesheetCols: satu baris per timesheet, atau per entry jika entries
+// diminta (kolom timesheet diulang, kolom entry kosong untuk timesheet tanpa entry).
+var (
+	csvTimesheetCols = []string{"timesheet_id", "employee_id", "employee_name", "department_id", "department",
+		"year", "month", "status", "total_working_days"}
+	csvEntryCols = []string{"entry_id", "date", "entry_type", "start_time", "end_time", "ends_next_day",
+		"total_hours", "overtime_hours", "night_hours", "billable", "client_id", "holiday", "remarks"}
+)
+
+type csvEncoder struct {
+	w           *csv.Writer
+	withEntries bool
+}
+
+func (e *csvEncoder) header() error {
+	cols := csvTimesheetCols
+	if e.withEntries { cols = append(append([]string(nil), cols...), csvEntryCols...) }
+	return e.w.Write(cols)
+}
+
+func (e *csvEncoder) write(ts *domain.Timesheet) error {
+	head := []string{strconv.FormatInt(ts.ID, 10), strconv.FormatInt(ts.EmployeeID, 10), ts.EmployeeName,
+		optInt(ts.DepartmentID), ts.Department, strconv.Itoa(ts.Year), strconv.Itoa(ts.Month), string(ts.Status), ""}
+	if ts.TotalWorkingDays != nil { head[8] = strconv.Itoa(*ts.TotalWorkingDays) }
+	if !e.withEntries { return e.w.Write(head) }
+	if len(ts.Entries) == 0 { return e.w.Write(append(head, make([]string, len(csvEntryCols))...)) }
+
+	row := make([]string, 0, len(head)+len(csvEntryCols))
+	for _, en := range ts.Entries {
+		row = append(append(row[:0], head...),
+			strconv.FormatInt(en.ID, 10), en.WorkDate.Format("2006-01-02"), string(en.Type),
+			optClock(en.StartTime), optClock(en.EndTime), strconv.FormatBool(en.EndsNextDay),
+			optFloat(en.TotalHours), optFloat(en.OvertimeHours), optFloat(en.NightHours),
+			strconv.FormatBool(en.Billable), optInt(en.ClientID), en.Holiday, en.Remarks)
+		if err := e.w.Write(row); err != nil { return err }
+	}
+	return nil
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// timesheetLine: satu baris NDJSON. Entries (jika diminta) berformat sama
+// dengan GET /timesheets/:id dan menimpa field entries milik domain.Timesheet.
+type timesheetLine struct {
+	*domain.Timesheet
+	Entries []entryResponse `json:"entries,omitempty"`
+}
+
+// ndjsonEncoder menulis langsung ke response (json.Encoder tidak menyangga).
+type ndjsonEncoder struct{ enc *json.Encoder }
+
+func (e *ndjsonEncoder) header() error { return nil }
+
+func (e *ndjsonEncoder) write(ts *domain.Timesheet) error {
+	line := timesheetLine{Timesheet: ts}
+	for _, en := range ts.Entries {
+		line.Entries = append(line.Entries, toEntryResponse(en))
+	}
+	return e.enc.Encode(line)
+}
+
+func (e *ndjsonEncoder) flush() error { return nil }
+
+func optInt(v *int64) string {
+	if v == nil { return "" }
+	return strconv.FormatInt(*v, 10)
+}
+
+func optFloat(v *float64) string {
+	if v == nil { return "" }
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func optClock(v *time.Time) string {
+	if v == nil { return "" }
+	return v.Format("15:04:05")
+}
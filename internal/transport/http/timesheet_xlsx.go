@@ -0,0 +1,244 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"timesheet-api/internal/domain"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// xlsxCols: kolom tabel sama dengan PDF (lebar dalam satuan karakter Excel).
+var xlsxCols = []struct {
+	Title string
+	Width float64
+}{
+	{"Tanggal / Date", 14},
+	{"Hari / Day", 14},
+	{"Mulai / Start", 13},
+	{"Selesai / End", 16},
+	{"Jam / Hrs", 11},
+	{"Lembur / OT", 13},
+	{"Keterangan / Remarks", 45},
+}
+
+// timesheetXLSX menulis timesheet ke workbook dengan tata letak yang sama
+// dengan PDF. Satu sheet per karyawan; timesheet berikutnya milik karyawan
+// yang sama ditulis di bawahnya.
+type timesheetXLSX struct {
+	f      *excelize.File
+	sheets map[int64]string // employee_id → nama sheet
+	used   map[string]bool  // nama sheet (lowercase), Excel tidak membedakan huruf besar/kecil
+	last   map[string]int   // baris terakhir yang terisi per sheet
+	styles map[string]int   // jenis sel → style; akhiran "/libur" untuk baris hari libur
+}
+
+func newTimesheetXLSX() (*timesheetXLSX, error) {
+	x := &timesheetXLSX{f: excelize.NewFile(), sheets: map[int64]string{}, used: map[string]bool{},
+		last: map[string]int{}, styles: map[string]int{}}
+	// Total berupa formula; minta Excel menghitung ulang saat file dibuka.
+	full := true
+	if err := x.f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &full}); err != nil { return nil, err }
+
+	var border []excelize.Border
+	for _, side := range []string{"left", "top", "right", "bottom"} {
+		border = append(border, excelize.Border{Type: side, Color: "000000", Style: 1})
+	}
+	center := &excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	bold := &excelize.Font{Bold: true}
+	dateFmt, clockFmt, nextDayFmt, hoursFmt := "yyyy-mm-dd", "hh:mm", `hh:mm" (+1)"`, "0.00"
+	header := map[string]*excelize.Style{
+		"title": {Font: &excelize.Font{Bold: true, Size: 16}},
+		"head":  {Font: bold, Border: border, Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true}},
+		"total": {Font: bold, Border: border, Alignment: &excelize.Alignment{Horizontal: "right"}},
+		"sum":   {Font: bold, Border: border, Alignment: center, CustomNumFmt: &hoursFmt},
+	}
+	body := map[string]*excelize.Style{
+		"text":    {Border: border, Alignment: center},
+		"remarks": {Border: border, Alignment: &excelize.Alignment{Vertical: "center", WrapText: true}},
+		"date":    {Border: border, Alignment: center, CustomNumFmt: &dateFmt},
+		"clock":   {Border: border, Alignment: center, CustomNumFmt: &clockFmt},
+		"nextday": {Border: border, Alignment: center, CustomNumFmt: &nextDayFmt},
+		"hours":   {Border: border, Alignment: center, CustomNumFmt: &hoursFmt},
+	}
+	for k, s := range header {
+		id, err := x.f.NewStyle(s)
+		if err != nil { return nil, err }
+		x.styles[k] = id
+	}
+	for k, s := range body {
+		id, err := x.f.NewStyle(s)
+		if err != nil { return nil, err }
+		x.styles[k] = id
+		// Baris hari libur: latar merah muda, sama seperti PDF.
+		holiday := *s
+		holiday.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FDE2E2"}}
+		if id, err = x.f.NewStyle(&holiday); err != nil { return nil, err }
+		x.styles[k+"/libur"] = id
+	}
+	return x, nil
+}
+
+// sheet mengembalikan sheet karyawan ts, membuatnya jika belum ada.
+func (x *timesheetXLSX) sheet(ts *domain.Timesheet) (string, error) {
+	if name, ok := x.sheets[ts.EmployeeID]; ok { return name, nil }
+	name := sheetName(ts.EmployeeName, x.used)
+	var err error
+	if len(x.sheets) == 0 {
+		err = x.f.SetSheetName(x.f.GetSheetName(0), name)
+	} else {
+		_, err = x.f.NewSheet(name)
+	}
+	if err != nil { return "", err }
+	for i, col := range xlsxCols {
+		c, _ := excelize.ColumnNumberToName(i + 1)
+		if err := x.f.SetColWidth(name, c, c, col.Width); err != nil { return "", err }
+	}
+	x.sheets[ts.EmployeeID], x.used[strings.ToLower(name)] = name, true
+	return name, nil
+}
+
+// add menulis satu timesheet: judul, info karyawan, tabel entry, lalu baris
+// TOTAL berisi formula SUM atas kolom jam & lembur.
+func (x *timesheetXLSX) add(ts *domain.Timesheet) error {
+	name, err := x.sheet(ts)
+	if err != nil { return err }
+	w := &sheetWriter{f: x.f, name: name}
+	row := x.last[name] + 1
+	if row > 1 { row += 2 } // jarak antar timesheet di sheet yang sama
+
+	w.cell(1, row, "ABSENSI KEHADIRAN / TIME SHEET", x.styles["title"])
+	w.merge(1, row, len(xlsxCols), row)
+	row += 2
+	info := func(label, val string) {
+		w.cell(1, row, label, 0)
+		w.merge(1, row, 3, row)
+		w.cell(4, row, ": "+val, 0)
+		w.merge(4, row, len(xlsxCols), row)
+		row++
+	}
+	info("Nama Karyawan / Employee Name", ts.EmployeeName)
+	info("Divisi / Department", ts.Department)
+	info("Periode", fmt.Sprintf("%s %d", indoMonth(ts.Month), ts.Year))
+	if ts.TotalWorkingDays != nil {
+		info("Total Hari Kerja / Total Working Day", fmt.Sprintf("%d Hari", *ts.TotalWorkingDays))
+	}
+	row++
+
+	for i, col := range xlsxCols {
+		w.cell(i+1, row, col.Title, x.styles["head"])
+	}
+	first := row + 1
+	for _, e := range ts.Entries {
+		row++
+		suffix := ""
+		if e.Holiday != "" { suffix = "/libur" }
+		st := func(kind string) int { return x.styles[kind+suffix] }
+
+		w.cell(1, row, e.WorkDate, st("date"))
+		w.cell(2, row, indoDayName(e.WorkDate.Weekday()), st("text"))
+		if e.StartTime != nil { w.cell(3, row, dayFraction(*e.StartTime), st("clock")) } else { w.cell(3, row, "-", st("text")) }
+		switch {
+		case e.EndTime == nil:
+			w.cell(4, row, "-", st("text"))
+		case e.EndsNextDay:
+			// +1 hari supaya selisih Selesai − Mulai di Excel tetap benar.
+			w.cell(4, row, 1+dayFraction(*e.EndTime), st("nextday"))
+		default:
+			w.cell(4, row, dayFraction(*e.EndTime), st("clock"))
+		}
+		if e.TotalHours != nil { w.cell(5, row, *e.TotalHours, st("hours")) } else { w.cell(5, row, "-", st("text")) }
+		if e.OvertimeHours != nil { w.cell(6, row, *e.OvertimeHours, st("hours")) } else { w.cell(6, row, "-", st("text")) }
+		remarks := e.Remarks
+		if e.Holiday != "" {
+			remarks = strings.TrimSpace("Libur: " + e.Holiday + ". " + remarks)
+		}
+		w.cell(7, row, remarks, st("remarks"))
+	}
+
+	row++
+	w.cell(1, row, "TOTAL", x.styles["total"])
+	for c := 2; c <= 4; c++ { w.cell(c, row, nil, x.styles["total"]) }
+	w.merge(1, row, 4, row)
+	for _, col := range []string{"E", "F"} {
+		c, _ := excelize.ColumnNameToNumber(col)
+		if row == first {
+			w.cell(c, row, 0, x.styles["sum"])
+		} else {
+			w.formula(c, row, fmt.Sprintf("SUM(%s%d:%s%d)", col, first, col, row-1), x.styles["sum"])
+		}
+	}
+	w.cell(7, row, nil, x.styles["total"])
+	x.last[name] = row
+	return w.err
+}
+
+func (x *timesheetXLSX) write(out io.Writer) error {
+	x.f.SetActiveSheet(0)
+	return x.f.Write(out)
+}
+
+// sheetWriter menyimpan error pertama supaya rangkaian SetCell tidak perlu
+// dicek satu per satu.
+type sheetWriter struct {
+	f    *excelize.File
+	name string
+	err  error
+}
+
+func (w *sheetWriter) ref(col, row int) string {
+	ref, err := excelize.CoordinatesToCellName(col, row)
+	if err != nil && w.err == nil { w.err = err }
+	return ref
+}
+
+func (w *sheetWriter) cell(col, row int, v interface{}, style int) {
+	if w.err != nil { return }
+	ref := w.ref(col, row)
+	if v != nil { w.err = w.f.SetCellValue(w.name, ref, v) }
+	if w.err == nil && style > 0 { w.err = w.f.SetCellStyle(w.name, ref, ref, style) }
+}
+
+func (w *sheetWriter) formula(col, row int, formula string, style int) {
+	if w.err != nil { return }
+	ref := w.ref(col, row)
+	if w.err = w.f.SetCellFormula(w.name, ref, formula); w.err == nil && style > 0 {
+		w.err = w.f.SetCellStyle(w.name, ref, ref, style)
+	}
+}
+
+func (w *sheetWriter) merge(col1, row1, col2, row2 int) {
+	if w.err != nil { return }
+	w.err = w.f.MergeCell(w.name, w.ref(col1, row1), w.ref(col2, row2))
+}
+
+// dayFraction: jam sebagai pecahan hari, format waktu Excel.
+func dayFraction(t time.Time) float64 {
+	return float64(t.Hour()*3600+t.Minute()*60+t.Second()) / 86400
+}
+
+var sheetNameReplacer = strings.NewReplacer(":", " ", `\`, " ", "/", " ", "?", " ", "*", " ", "[", "(", "]", ")")
+
+// sheetName: nama karyawan yang valid sebagai nama sheet Excel (maks. 31
+// karakter, tanpa :\/?*[]), diberi akhiran " (2)", " (3)", … jika sudah dipakai.
+func sheetName(employee string, used map[string]bool) string {
+	base := strings.Trim(strings.Join(strings.Fields(sheetNameReplacer.Replace(employee)), " "), "'")
+	if base == "" { base = "Karyawan" }
+	for n := 1; ; n++ {
+		suffix := ""
+		if n > 1 { suffix = fmt.Sprintf(" (%d)", n) }
+		name := base
+		for utf8.RuneCountInString(name)+len(suffix) > 31 {
+			_, size := utf8.DecodeLastRuneInString(name)
+			name = name[:len(name)-size]
+		}
+		name = strings.TrimSpace(name) + suffix
+		if !used[strings.ToLower(name)] { return name }
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/audit"
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+)
+
+// newAuditEvent menyiapkan event audit dengan actor & request ID dari ctx.
+// before/after adalah snapshot (lihat timesheetSnapshot/entrySnapshot); yang
+// disimpan hanya field yang berubah.
+func newAuditEvent(ctx context.Context, entity, action string, timesheetID, entityID int64, before, after map[string]interface{}) (*domain.AuditEvent, error) {
+	b, a, err := audit.Diff(before, after)
+	if err != nil { return nil, err }
+	ev := &domain.AuditEvent{
+		TimesheetID: timesheetID,
+		Entity:      entity,
+		EntityID:    entityID,
+		Action:      action,
+		RequestID:   audit.RequestIDFrom(ctx),
+		Before:      b,
+		After:       a,
+	}
+	if p := auth.PrincipalFrom(ctx); p != nil {
+		ev.Actor = p.Username
+	}
+	return ev, nil
+}
+
+// timesheetSnapshot hanya memuat field yang bisa diubah lewat UpdateTimesheet.
+func timesheetSnapshot(ts *domain.Timesheet) map[string]interface{} {
+	return map[string]interface{}{
+		"employee_id":        ts.EmployeeID,
+		"department_id":      ts.DepartmentID,
+		"month":              ts.Month,
+		"year":               ts.Year,
+		"total_working_days": ts.TotalWorkingDays,
+	}
+}
+
+func entrySnapshot(e *domain.TimesheetEntry) map[string]interface{} {
+	var segments []string
+	for _, sg := range e.Segments {
+		segments = append(segments, fmt.Sprintf("%s %s-%s", sg.Type, sg.StartTime.Format("15:04"), sg.EndTime.Format("15:04")))
+	}
+	var allocations []string
+	for _, a := range e.Allocations {
+		target := fmt.Sprintf("project %d", a.ProjectID)
+		if a.TaskID != nil { target += fmt.Sprintf(" task %d", *a.TaskID) }
+		allocations = append(allocations, fmt.Sprintf("%s: %.2f", target, a.Hours))
+	}
+	return map[string]interface{}{
+		"segments":       segments,
+		"allocations":    allocations,
+		"date":           e.WorkDate.Format("2006-01-02"),
+		"start_time":     clock(e.StartTime),
+		"end_time":       clock(e.EndTime),
+		"total_hours":    e.TotalHours,
+		"overtime_hours": e.OvertimeHours,
+		"remarks":        e.Remarks,
+		"entry_type":     e.Type,
+		"leave_type_id":  e.LeaveTypeID,
+		"billable":       e.Billable,
+		"client_id":      e.ClientID,
+	}
+}
+
+func clock(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("15:04:05")
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type AuthService struct {
+	users      repository.UserRepository
+	refresh    repository.RefreshTokenRepository
+	tokens     *auth.TokenManager
+	refreshTTL time.Duration
+}
+
+func NewAuthService(ur repository.UserRepository, rr repository.RefreshTokenRepository, tm *auth.TokenManager, refreshTTL time.Duration) *AuthService {
+	if refreshTTL <= 0 { refreshTTL = 30 * 24 * time.Hour }
+	return &AuthService{users: ur, refresh: rr, tokens: tm, refreshTTL: refreshTTL}
+}
+
+// dummyPasswordHash: hash bcrypt (cost default) yang dicek saat user tidak ada
+// atau nonaktif, supaya waktu jawab Login tidak membocorkan username yang valid.
+const dummyPasswordHash = "$2a$10$YOaIumj1ImTJg7W3blWj6.BWJe7rdyQW9/hl3zwgWmTNKGMOf.yOC"
+
+// Login memverifikasi kredensial dan membuka sesi (refresh token family) baru.
+func (s *AuthService) Login(username, password string) (*domain.TokenPair, error) {
+	u, err := s.users.FindByUsername(strings.TrimSpace(username))
+	if err != nil && err != domain.ErrNotFound { return nil, err }
+	if err == domain.ErrNotFound || !u.Active {
+		auth.CheckPassword(dummyPasswordHash, password)
+		return nil, domain.ErrUnauthorized
+	}
+	if !auth.CheckPassword(u.PasswordHash, password) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	family, _, err := auth.NewOpaqueToken()
+	if err != nil { return nil, err }
+	raw, rt, err := s.newRefreshToken(u.ID, family)
+	if err != nil { return nil, err }
+	if _, err := s.refresh.Create(rt); err != nil { return nil, err }
+	return s.pair(u, raw, rt)
+}
+
+// Refresh menukar refresh token dengan pasangan token baru (rotasi). Token lama
+// yang dipakai ulang dianggap bocor: seluruh sesinya dicabut.
+func (s *AuthService) Refresh(raw string) (*domain.TokenPair, error) {
+	cur, err := s.refresh.FindByHash(auth.HashToken(raw))
+	if err == domain.ErrNotFound {
+		return nil, domain.ErrUnauthorized
+	}
+	if err != nil { return nil, err }
+	if cur.RevokedAt != nil {
+		if err := s.refresh.RevokeFamily(cur.FamilyID); err != nil { return nil, err }
+		return nil, fmt.Errorf("%w: refresh token sudah dipakai", domain.ErrUnauthorized)
+	}
+	if time.Now().After(cur.ExpiresAt) {
+		return nil, fmt.Errorf("%w: refresh token kedaluwarsa", domain.ErrUnauthorized)
+	}
+
+	u, err := s.users.FindByID(cur.UserID)
+	if err != nil { return nil, err }
+	if !u.Active {
+		_ = s.refresh.RevokeFamily(cur.FamilyID)
+		return nil, domain.ErrUnauthorized
+	}
+
+	nextRaw, next, err := s.newRefreshToken(u.ID, cur.FamilyID)
+	if err != nil { return nil, err }
+	if err := s.refresh.Rotate(cur.ID, next); err != nil {
+		if err == domain.ErrUnauthorized {
+			_ = s.refresh.RevokeFamily(cur.FamilyID)
+		}
+		return nil, err
+	}
+	return s.pair(u, nextRaw, next)
+}
+
+// Logout mencabut sesi milik refresh token raw.
+func (s *AuthService) Logout(raw string) error {
+	cur, err := s.refresh.FindByHash(auth.HashToken(raw))
+	if err == domain.ErrNotFound {
+		return nil
+	}
+	if err != nil { return err }
+	return s.refresh.RevokeFamily(cur.FamilyID)
+}
+
+// LogoutAll mencabut semua sesi user.
+func (s *AuthService) LogoutAll(userID int64) error { return s.refresh.RevokeUser(userID) }
+
+func (s *AuthService) Me(userID int64) (*domain.User, error) { return s.users.FindByID(userID) }
+
+func (s *AuthService) CreateUser(u *domain.User, password string) (int64, error) {
+	u.Username = strings.TrimSpace(u.Username)
+	if u.Username == "" || len(password) < 8 {
+		return 0, fmt.Errorf("%w: username wajib, password minimal 8 karakter", domain.ErrInvalidInput)
+	}
+	if u.Role == "" { u.Role = domain.RoleEmployee }
+	if !u.Role.Valid() {
+		return 0, fmt.Errorf("%w: role %q tidak dikenal", domain.ErrInvalidInput, u.Role)
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil { return 0, err }
+	u.PasswordHash = hash
+	return s.users.Create(u)
+}
+
+func (s *AuthService) ListUsers() ([]domain.User, error) { return s.users.List() }
+
+// EnsureAdmin membuat user hr_admin awal jika username tersebut belum ada.
+func (s *AuthService) EnsureAdmin(username, password string) error {
+	if username == "" || password == "" { return nil }
+	if _, err := s.users.FindByUsername(username); err == nil {
+		return nil
+	} else if err != domain.ErrNotFound {
+		return err
+	}
+	_, err := s.CreateUser(&domain.User{Username: username, Role: domain.RoleHRAdmin, Active: true}, password)
+	return err
+}
+
+func (s *AuthService) newRefreshToken(userID int64, family string) (string, *domain.RefreshToken, error) {
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil { return "", nil, err }
+	return raw, &domain.RefreshToken{
+		UserID:    userID,
+		TokenHash: hash,
+		FamilyID:  family,
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}, nil
+}
+
+func (s *AuthService) pair(u *domain.User, refreshRaw string, rt *domain.RefreshToken) (*domain.TokenPair, error) {
+	access, err := s.tokens.Issue(domain.Principal{UserID: u.ID, Username: u.Username, Role: u.Role, EmployeeID: u.EmployeeID})
+	if err != nil { return nil, err }
+	return &domain.TokenPair{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(s.tokens.AccessTTL().Seconds()),
+		RefreshToken:     refreshRaw,
+		RefreshExpiresAt: rt.ExpiresAt,
+	}, nil
+}
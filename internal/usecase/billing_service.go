@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// BillingService mengelola klien, rate card, dan pratinjau tagihan dari jam
+// entry billable.
+type BillingService struct {
+	repo repository.BillingRepository
+}
+
+func NewBillingService(r repository.BillingRepository) *BillingService { return &BillingService{repo: r} }
+
+// ====== Clients ======
+
+func (s *BillingService) CreateClient(c *domain.Client) (int64, error) {
+	if err := validateClient(c); err != nil { return 0, err }
+	return s.repo.CreateClient(c)
+}
+func (s *BillingService) GetClient(id int64) (*domain.Client, error) { return s.repo.FindClient(id) }
+func (s *BillingService) ListClients() ([]domain.Client, error)      { return s.repo.ListClients() }
+func (s *BillingService) UpdateClient(c *domain.Client) error {
+	if c.ID == 0 { return domain.ErrInvalidInput }
+	if err := validateClient(c); err != nil { return err }
+	return s.repo.UpdateClient(c)
+}
+func (s *BillingService) DeleteClient(id int64) error { return s.repo.DeleteClient(id) }
+
+func validateClient(c *domain.Client) error {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	c.Name = strings.TrimSpace(c.Name)
+	c.Currency = strings.ToUpper(strings.TrimSpace(c.Currency))
+	if c.Currency == "" { c.Currency = "IDR" }
+	if c.Code == "" || c.Name == "" {
+		return fmt.Errorf("%w: code dan name wajib diisi", domain.ErrInvalidInput)
+	}
+	if len(c.Currency) != 3 { return fmt.Errorf("%w: currency harus kode 3 huruf", domain.ErrInvalidInput) }
+	return nil
+}
+
+// ====== Rate cards ======
+
+func (s *BillingService) CreateRate(r *domain.RateCard) (int64, error) {
+	if err := validateRate(r); err != nil { return 0, err }
+	return s.repo.CreateRate(r)
+}
+func (s *BillingService) GetRate(id int64) (*domain.RateCard, error) { return s.repo.FindRate(id) }
+func (s *BillingService) ListRates(clientID *int64) ([]domain.RateCard, error) {
+	return s.repo.ListRates(clientID)
+}
+func (s *BillingService) UpdateRate(r *domain.RateCard) error {
+	if r.ID == 0 { return domain.ErrInvalidInput }
+	if err := validateRate(r); err != nil { return err }
+	return s.repo.UpdateRate(r)
+}
+func (s *BillingService) DeleteRate(id int64) error { return s.repo.DeleteRate(id) }
+
+func validateRate(r *domain.RateCard) error {
+	r.Position = strings.TrimSpace(r.Position)
+	if r.HourlyRate < 0 { return fmt.Errorf("%w: hourly_rate tidak boleh negatif", domain.ErrInvalidInput) }
+	if r.EffectiveFrom.IsZero() { return fmt.Errorf("%w: effective_from wajib diisi", domain.ErrInvalidInput) }
+	if r.EffectiveTo != nil && r.EffectiveTo.Before(r.EffectiveFrom) {
+		return fmt.Errorf("%w: effective_to sebelum effective_from", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// ====== Invoice ======
+
+// InvoicePreview mengalikan jam billable klien di [from, to] dengan tarif yang
+// berlaku di tanggal kerjanya. Baris dikelompokkan per karyawan, project, dan tarif.
+func (s *BillingService) InvoicePreview(clientID int64, from, to time.Time) (*domain.InvoicePreview, error) {
+	if to.Before(from) { return nil, fmt.Errorf("%w: to sebelum from", domain.ErrInvalidInput) }
+	client, err := s.repo.FindClient(clientID)
+	if err != nil { return nil, err }
+	rates, err := s.repo.RatesFor(clientID, from, to)
+	if err != nil { return nil, err }
+	items, err := s.repo.BillableItems(clientID, from, to)
+	if err != nil { return nil, err }
+
+	type key struct {
+		employee int64
+		project  int64
+		rate     int64
+	}
+	out := &domain.InvoicePreview{Client: *client, From: from, To: to, Lines: []domain.InvoiceLine{}}
+	idx := map[key]int{}
+	for _, it := range items {
+		k := key{employee: it.EmployeeID}
+		if it.ProjectID != nil { k.project = *it.ProjectID }
+		rc := pickRate(rates, clientID, it)
+		if rc != nil { k.rate = rc.ID }
+		i, ok := idx[k]
+		if !ok {
+			ln := domain.InvoiceLine{EmployeeID: it.EmployeeID, EmployeeName: it.EmployeeName, Position: it.Position,
+				ProjectID: it.ProjectID, ProjectCode: it.ProjectCode}
+			if rc != nil { ln.RateCardID, ln.HourlyRate = &rc.ID, rc.HourlyRate }
+			i = len(out.Lines)
+			idx[k] = i
+			out.Lines = append(out.Lines, ln)
+		}
+		out.Lines[i].Hours += it.Hours
+		if rc == nil { out.UnratedHours += it.Hours }
+	}
+	sort.SliceStable(out.Lines, func(a, b int) bool {
+		la, lb := out.Lines[a], out.Lines[b]
+		if la.EmployeeName != lb.EmployeeName { return la.EmployeeName < lb.EmployeeName }
+		return la.ProjectCode < lb.ProjectCode
+	})
+	for i := range out.Lines {
+		ln := &out.Lines[i]
+		ln.Hours = round2(ln.Hours)
+		ln.Amount = round2(ln.Hours * ln.HourlyRate)
+		out.TotalHours += ln.Hours
+		out.TotalAmount += ln.Amount
+	}
+	out.TotalHours, out.TotalAmount, out.UnratedHours = round2(out.TotalHours), round2(out.TotalAmount), round2(out.UnratedHours)
+	return out, nil
+}
+
+// pickRate memilih rate card yang berlaku di tanggal item dan paling spesifik:
+// project (4) > jabatan (2) > klien (1); seri → effective_from terbaru.
+func pickRate(rates []domain.RateCard, clientID int64, it domain.BillableItem) *domain.RateCard {
+	var best *domain.RateCard
+	bestScore := -1
+	for i := range rates {
+		r := &rates[i]
+		if !r.Covers(it.WorkDate) { continue }
+		score := 0
+		if r.ClientID != nil {
+			if *r.ClientID != clientID { continue }
+			score++
+		}
+		if r.ProjectID != nil {
+			if it.ProjectID == nil || *r.ProjectID != *it.ProjectID { continue }
+			score += 4
+		}
+		if r.Position != "" {
+			if !strings.EqualFold(r.Position, it.Position) { continue }
+			score += 2
+		}
+		if score > bestScore || (score == bestScore && r.EffectiveFrom.After(best.EffectiveFrom)) {
+			best, bestScore = r, score
+		}
+	}
+	return best
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
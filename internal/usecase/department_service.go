@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type DepartmentService struct {
+	repo      repository.DepartmentRepository
+	employees repository.EmployeeRepository
+	policies  repository.OvertimePolicyRepository
+}
+
+func NewDepartmentService(r repository.DepartmentRepository, er repository.EmployeeRepository, pr repository.OvertimePolicyRepository) *DepartmentService {
+	return &DepartmentService{repo: r, employees: er, policies: pr}
+}
+
+func (s *DepartmentService) CreateDepartment(d *domain.Department) (int64, error) {
+	if err := s.validate(d); err != nil { return 0, err }
+	return s.repo.Create(d)
+}
+func (s *DepartmentService) GetDepartment(id int64) (*domain.Department, error) { return s.repo.FindByID(id) }
+func (s *DepartmentService) ListDepartments() ([]domain.Department, error) { return s.repo.List() }
+func (s *DepartmentService) UpdateDepartment(d *domain.Department) error {
+	if d.ID == 0 { return domain.ErrInvalidInput }
+	if err := s.validate(d); err != nil { return err }
+	return s.repo.Update(d)
+}
+func (s *DepartmentService) DeleteDepartment(id int64) error { return s.repo.Delete(id) }
+
+// Subtree mengembalikan departemen id beserta semua sub-departemennya.
+func (s *DepartmentService) Subtree(id int64) ([]domain.Department, error) {
+	ids, err := s.repo.Subtree(id)
+	if err != nil { return nil, err }
+	in := make(map[int64]bool, len(ids))
+	for _, v := range ids { in[v] = true }
+
+	all, err := s.repo.List()
+	if err != nil { return nil, err }
+	out := make([]domain.Department, 0, len(ids))
+	for _, d := range all {
+		if in[d.ID] { out = append(out, d) }
+	}
+	return out, nil
+}
+
+func (s *DepartmentService) validate(d *domain.Department) error {
+	d.Name = strings.TrimSpace(d.Name)
+	if d.Name == "" { return domain.ErrInvalidInput }
+	d.Code = trimOrNil(d.Code)
+
+	if d.ParentID != nil {
+		if _, err := s.repo.FindByID(*d.ParentID); err == domain.ErrNotFound {
+			return fmt.Errorf("%w: parent_id %d tidak ditemukan", domain.ErrInvalidInput, *d.ParentID)
+		} else if err != nil {
+			return err
+		}
+		// Parent tidak boleh dirinya sendiri atau salah satu turunannya.
+		if d.ID != 0 {
+			sub, err := s.repo.Subtree(d.ID)
+			if err != nil { return err }
+			for _, v := range sub {
+				if v == *d.ParentID {
+					return fmt.Errorf("%w: parent_id membentuk siklus", domain.ErrInvalidInput)
+				}
+			}
+		}
+	}
+	if d.ManagerID != nil {
+		if _, err := s.employees.FindByID(*d.ManagerID); err == domain.ErrNotFound {
+			return fmt.Errorf("%w: manager_id %d tidak ditemukan", domain.ErrInvalidInput, *d.ManagerID)
+		} else if err != nil {
+			return err
+		}
+	}
+	if d.OvertimePolicyID != nil {
+		if _, err := s.policies.FindByID(*d.OvertimePolicyID); err == domain.ErrNotFound {
+			return fmt.Errorf("%w: overtime_policy_id %d tidak ditemukan", domain.ErrInvalidInput, *d.OvertimePolicyID)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
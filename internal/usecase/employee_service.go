@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type EmployeeService struct {
+	repo        repository.EmployeeRepository
+	departments repository.DepartmentRepository
+}
+
+func NewEmployeeService(r repository.EmployeeRepository, dr repository.DepartmentRepository) *EmployeeService {
+	return &EmployeeService{repo: r, departments: dr}
+}
+
+func (s *EmployeeService) CreateEmployee(e *domain.Employee) (int64, error) {
+	if err := s.validate(e); err != nil { return 0, err }
+	return s.repo.Create(e)
+}
+func (s *EmployeeService) GetEmployee(id int64) (*domain.Employee, error) { return s.repo.FindByID(id) }
+func (s *EmployeeService) ListEmployees(f repository.EmployeeFilter) ([]domain.Employee, error) {
+	return s.repo.List(f)
+}
+func (s *EmployeeService) UpdateEmployee(e *domain.Employee) error {
+	if e.ID == 0 { return domain.ErrInvalidInput }
+	if err := s.validate(e); err != nil { return err }
+	return s.repo.Update(e)
+}
+func (s *EmployeeService) DeleteEmployee(id int64) error { return s.repo.Delete(id) }
+
+func (s *EmployeeService) validate(e *domain.Employee) error {
+	e.Name = strings.TrimSpace(e.Name)
+	if e.Name == "" { return domain.ErrInvalidInput }
+	e.Code = trimOrNil(e.Code)
+	e.Email = trimOrNil(e.Email)
+	e.Position = trimOrNil(e.Position)
+	if e.DepartmentID != nil {
+		if _, err := s.departments.FindByID(*e.DepartmentID); err == domain.ErrNotFound {
+			return fmt.Errorf("%w: department_id %d tidak ditemukan", domain.ErrInvalidInput, *e.DepartmentID)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func trimOrNil(s *string) *string {
+	if s == nil { return nil }
+	v := strings.TrimSpace(*s)
+	if v == "" { return nil }
+	return &v
+}
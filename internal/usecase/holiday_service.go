@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"timesheet-api/internal/calendar"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type HolidayService struct {
+	repo repository.HolidayRepository
+}
+
+func NewHolidayService(r repository.HolidayRepository) *HolidayService { return &HolidayService{repo: r} }
+
+func (s *HolidayService) CreateHoliday(h *domain.Holiday) (int64, error) {
+	if err := validateHoliday(h); err != nil { return 0, err }
+	return s.repo.Create(h)
+}
+func (s *HolidayService) GetHoliday(id int64) (*domain.Holiday, error) { return s.repo.FindByID(id) }
+func (s *HolidayService) UpdateHoliday(h *domain.Holiday) error {
+	if h.ID == 0 { return domain.ErrInvalidInput }
+	if err := validateHoliday(h); err != nil { return err }
+	return s.repo.Update(h)
+}
+func (s *HolidayService) DeleteHoliday(id int64) error { return s.repo.Delete(id) }
+
+// ListHolidays mengembalikan hari libur setahun, atau sebulan jika month diisi.
+func (s *HolidayService) ListHolidays(year int, month *int) ([]domain.Holiday, error) {
+	if year < 1900 || year > 2100 { return nil, fmt.Errorf("%w: year tidak valid", domain.ErrInvalidInput) }
+	from, _ := calendar.MonthRange(year, 1)
+	_, to := calendar.MonthRange(year, 12)
+	if month != nil {
+		if *month < 1 || *month > 12 { return nil, fmt.Errorf("%w: month tidak valid", domain.ErrInvalidInput) }
+		from, to = calendar.MonthRange(year, *month)
+	}
+	return s.repo.Between(from, to)
+}
+
+// ImportICS memasukkan semua event dari file .ics; tanggal yang sudah ada ditimpa.
+func (s *HolidayService) ImportICS(r io.Reader, kind domain.HolidayKind) (int, error) {
+	if kind == "" { kind = domain.HolidayNational }
+	if !kind.Valid() { return 0, fmt.Errorf("%w: kind %q tidak dikenal", domain.ErrInvalidInput, kind) }
+	items, err := calendar.ParseICS(r, kind)
+	if err != nil { return 0, err }
+	for i := range items {
+		if err := validateHoliday(&items[i]); err != nil { return 0, err }
+	}
+	if len(items) == 0 { return 0, fmt.Errorf("%w: tidak ada VEVENT di file", domain.ErrInvalidInput) }
+	return s.repo.Upsert(items)
+}
+
+// WorkingDays menghitung hari kerja sebulan: Senin–Jumat dikurangi hari libur.
+func (s *HolidayService) WorkingDays(year, month int) (int, error) {
+	hs, err := s.ListHolidays(year, &month)
+	if err != nil { return 0, err }
+	return calendar.WorkingDays(year, month, hs), nil
+}
+
+func validateHoliday(h *domain.Holiday) error {
+	h.Name = strings.TrimSpace(h.Name)
+	if h.Name == "" || h.Date.IsZero() {
+		return fmt.Errorf("%w: date dan name wajib diisi", domain.ErrInvalidInput)
+	}
+	if h.Kind == "" { h.Kind = domain.HolidayNational }
+	if !h.Kind.Valid() { return fmt.Errorf("%w: kind %q tidak dikenal", domain.ErrInvalidInput, h.Kind) }
+	return nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// ImportService mengimpor entry dari file (lihat package importer). Tiap baris
+// divalidasi dengan aturan yang sama dengan TimesheetService.AddEntry, dan
+// timesheet bulanan yang belum ada dibuat otomatis.
+type ImportService struct {
+	repo   repository.ImportRepository
+	sheets *TimesheetService
+}
+
+func NewImportService(r repository.ImportRepository, ts *TimesheetService) *ImportService {
+	return &ImportService{repo: r, sheets: ts}
+}
+
+type importKey struct {
+	employeeID  int64
+	year, month int
+}
+
+// importSheet: timesheet tujuan satu karyawan & bulan. err diisi jika
+// timesheet-nya tidak bisa dipakai (akses/status), jadi semua barisnya gagal.
+type importSheet struct {
+	ts    *domain.Timesheet
+	audit *domain.AuditEvent
+	dates map[string]bool // tanggal yang sudah diterima dari file
+	used  bool
+	err   error
+}
+
+type importState struct {
+	employees map[string]int64
+	sheets    map[importKey]*importSheet
+	items     []domain.ImportItem
+}
+
+// Import memvalidasi semua baris, diurutkan per tanggal supaya batas lembur
+// mingguan ikut menghitung baris sebelumnya di file. dryRun → hanya laporan;
+// selain itu semua baris valid ditulis dalam satu transaksi dan baris yang
+// tidak valid dilewati (tetap dilaporkan).
+func (s *ImportService) Import(ctx context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportResult, error) {
+	res := &domain.ImportResult{DryRun: dryRun, Rows: len(rows), Errors: []domain.ImportError{}}
+	order := append([]domain.ImportRow(nil), rows...)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].Entry.WorkDate.Before(order[j].Entry.WorkDate) })
+
+	st := &importState{employees: map[string]int64{}, sheets: map[importKey]*importSheet{}}
+	svc := *s.sheets
+	svc.repo = &importWeekHours{TimesheetRepository: s.sheets.repo, state: st}
+	for i := range order {
+		r := &order[i]
+		if r.Invalid != nil {
+			res.Errors = append(res.Errors, *r.Invalid)
+			continue
+		}
+		err := s.importRow(ctx, &svc, st, r)
+		if err == nil { continue }
+		if !rowError(err) { return nil, err }
+		res.Errors = append(res.Errors, domain.ImportError{Line: r.Line, Message: err.Error()})
+	}
+	sort.SliceStable(res.Errors, func(i, j int) bool { return res.Errors[i].Line < res.Errors[j].Line })
+
+	res.Valid = len(st.items)
+	for _, sh := range st.sheets {
+		if sh.used && sh.ts.ID == 0 { res.NewTimesheets++ }
+	}
+	if dryRun || len(st.items) == 0 { return res, nil }
+	if err := s.repo.Import(ctx, st.items); err != nil { return nil, err }
+	res.Imported = len(st.items)
+	return res, nil
+}
+
+func (s *ImportService) importRow(ctx context.Context, svc *TimesheetService, st *importState, r *domain.ImportRow) error {
+	sh, err := s.sheet(ctx, st, r)
+	if err != nil { return err }
+	if sh.err != nil { return sh.err }
+
+	e := r.Entry
+	e.TimesheetID = sh.ts.ID
+	day := e.WorkDate.Format("2006-01-02")
+	if sh.dates[day] {
+		return fmt.Errorf("%w: %s muncul lebih dari sekali untuk karyawan ini", domain.ErrDuplicate, day)
+	}
+	if err := uniqueDate(sh.ts, &e); err != nil { return err }
+	if err := validateEntryType(&e); err != nil { return err }
+	if err := svc.computeHours(ctx, sh.ts, &e); err != nil { return err }
+	if err := svc.allocate(&e); err != nil { return err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, domain.AuditCreate, sh.ts.ID, 0, nil, entrySnapshot(&e))
+	if err != nil { return err }
+
+	sh.dates[day], sh.used = true, true
+	st.items = append(st.items, domain.ImportItem{Timesheet: sh.ts, TimesheetAudit: sh.audit, Entry: &e, Audit: ev})
+	return nil
+}
+
+// sheet mencari (atau menyiapkan) timesheet bulanan karyawan baris r.
+func (s *ImportService) sheet(ctx context.Context, st *importState, r *domain.ImportRow) (*importSheet, error) {
+	empID, err := s.employee(st, r)
+	if err != nil { return nil, err }
+	key := importKey{employeeID: empID, year: r.Entry.WorkDate.Year(), month: int(r.Entry.WorkDate.Month())}
+	if sh, ok := st.sheets[key]; ok { return sh, nil }
+
+	sh := &importSheet{dates: map[string]bool{}}
+	items, err := s.sheets.repo.List(ctx, repository.Filter{EmployeeID: &key.employeeID, Month: &key.month, Year: &key.year})
+	if err != nil { return nil, err }
+	if len(items) > 0 {
+		sh.ts, sh.err = s.sheets.editable(ctx, items[0].ID)
+	} else {
+		sh.ts, sh.audit, sh.err = s.newSheet(ctx, key)
+	}
+	if sh.err != nil && !rowError(sh.err) { return nil, sh.err }
+	st.sheets[key] = sh
+	return sh, nil
+}
+
+// newSheet menyiapkan timesheet baru (belum disimpan) seperti CreateTimesheet.
+func (s *ImportService) newSheet(ctx context.Context, key importKey) (*domain.Timesheet, *domain.AuditEvent, error) {
+	ts := &domain.Timesheet{EmployeeID: key.employeeID, Month: key.month, Year: key.year, Status: domain.StatusDraft}
+	if err := s.sheets.resolveEmployee(ts); err != nil { return nil, nil, err }
+	if err := s.sheets.policy.Authorize(ctx, ActionCreate, ts); err != nil { return nil, nil, err }
+	if err := s.sheets.fillWorkingDays(ts); err != nil { return nil, nil, err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditCreate, 0, 0, nil, timesheetSnapshot(ts))
+	if err != nil { return nil, nil, err }
+	return ts, ev, nil
+}
+
+// employee: id karyawan dari employee_id, lalu employee_code, lalu employee_name.
+func (s *ImportService) employee(st *importState, r *domain.ImportRow) (int64, error) {
+	ref := fmt.Sprintf("%d|%s|%s", r.EmployeeID, r.EmployeeCode, r.EmployeeName)
+	if id, ok := st.employees[ref]; ok { return id, nil }
+
+	ts := &domain.Timesheet{EmployeeID: r.EmployeeID, EmployeeName: r.EmployeeName}
+	if ts.EmployeeID == 0 && r.EmployeeCode != "" {
+		emp, err := s.sheets.employees.FindByCode(r.EmployeeCode)
+		if err == domain.ErrNotFound {
+			return 0, fmt.Errorf("%w: employee_code %q tidak ditemukan", domain.ErrInvalidInput, r.EmployeeCode)
+		}
+		if err != nil { return 0, err }
+		ts.EmployeeID = emp.ID
+	}
+	if err := s.sheets.resolveEmployee(ts); err != nil { return 0, err }
+	st.employees[ref] = ts.EmployeeID
+	return ts.EmployeeID, nil
+}
+
+// rowError: error yang disebabkan isi baris/hak akses, bukan kegagalan sistem.
+func rowError(err error) bool {
+	for _, target := range []error{domain.ErrInvalidInput, domain.ErrDuplicate, domain.ErrForbidden, domain.ErrLocked, domain.ErrNotFound} {
+		if errors.Is(err, target) { return true }
+	}
+	return false
+}
+
+// importWeekHours menambahkan jam reguler baris file yang sudah diterima ke
+// WeekRegularHours, karena baris itu belum ada di database.
+type importWeekHours struct {
+	repository.TimesheetRepository
+	state *importState
+}
+
+func (w *importWeekHours) WeekRegularHours(ctx context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (float64, error) {
+	sum, err := w.TimesheetRepository.WeekRegularHours(ctx, employeeID, from, before, excludeEntryID)
+	if err != nil { return 0, err }
+	for _, it := range w.state.items {
+		e := it.Entry
+		if it.Timesheet.EmployeeID != employeeID || e.Overtime == nil || e.WorkDate.Before(from) || !e.WorkDate.Before(before) { continue }
+		sum += e.Overtime.RegularHours
+	}
+	return sum, nil
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/calendar"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// LeaveService mengelola jenis cuti, pengajuan cuti beserta approval-nya, dan
+// saldo cuti. Cuti yang di-approve ditulis sebagai entry bertipe leave di
+// timesheet bulanan karyawan (dibuat otomatis jika belum ada).
+type LeaveService struct {
+	repo       repository.LeaveRepository
+	timesheets repository.TimesheetRepository
+	employees  repository.EmployeeRepository
+	holidays   repository.HolidayRepository // nil = hanya akhir pekan yang libur
+	policy     *TimesheetPolicy
+}
+
+func NewLeaveService(r repository.LeaveRepository, tr repository.TimesheetRepository, er repository.EmployeeRepository,
+	dr repository.DepartmentRepository, hr repository.HolidayRepository) *LeaveService {
+	return &LeaveService{repo: r, timesheets: tr, employees: er, holidays: hr, policy: NewTimesheetPolicy(dr)}
+}
+
+// ====== Leave types ======
+
+func (s *LeaveService) CreateType(t *domain.LeaveType) (int64, error) {
+	if err := validateLeaveType(t); err != nil { return 0, err }
+	return s.repo.CreateType(t)
+}
+func (s *LeaveService) GetType(id int64) (*domain.LeaveType, error) { return s.repo.FindType(id) }
+func (s *LeaveService) ListTypes() ([]domain.LeaveType, error)      { return s.repo.ListTypes() }
+func (s *LeaveService) UpdateType(t *domain.LeaveType) error {
+	if t.ID == 0 { return domain.ErrInvalidInput }
+	if err := validateLeaveType(t); err != nil { return err }
+	return s.repo.UpdateType(t)
+}
+func (s *LeaveService) DeleteType(id int64) error { return s.repo.DeleteType(id) }
+
+func validateLeaveType(t *domain.LeaveType) error {
+	t.Code = strings.ToUpper(strings.TrimSpace(t.Code))
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Code == "" || t.Name == "" {
+		return fmt.Errorf("%w: code dan name wajib diisi", domain.ErrInvalidInput)
+	}
+	if t.AnnualQuota < 0 { return fmt.Errorf("%w: annual_quota tidak boleh negatif", domain.ErrInvalidInput) }
+	return nil
+}
+
+// ====== Leave requests ======
+
+// CreateRequest mengajukan cuti. Tanpa employee_id → cuti milik pemanggil sendiri.
+// Days dihitung dari hari kerja di rentang tanggal (akhir pekan & libur tidak dihitung).
+func (s *LeaveService) CreateRequest(ctx context.Context, lr *domain.LeaveRequest) (int64, error) {
+	if p := auth.PrincipalFrom(ctx); p != nil && p.EmployeeID != nil && lr.EmployeeID == 0 {
+		lr.EmployeeID = *p.EmployeeID
+	}
+	if lr.EmployeeID == 0 { return 0, fmt.Errorf("%w: employee_id wajib diisi", domain.ErrInvalidInput) }
+	emp, err := s.employee(lr.EmployeeID)
+	if err != nil { return 0, err }
+	if err := s.policy.AuthorizeEmployee(ctx, ActionCreate, emp.ID, emp.DepartmentID); err != nil { return 0, err }
+
+	if lr.StartDate.IsZero() || lr.EndDate.IsZero() {
+		return 0, fmt.Errorf("%w: start_date dan end_date wajib diisi", domain.ErrInvalidInput)
+	}
+	if lr.EndDate.Before(lr.StartDate) {
+		return 0, fmt.Errorf("%w: end_date sebelum start_date", domain.ErrInvalidInput)
+	}
+	if lr.StartDate.Year() != lr.EndDate.Year() {
+		return 0, fmt.Errorf("%w: pengajuan cuti tidak boleh melewati pergantian tahun, pisahkan per tahun", domain.ErrInvalidInput)
+	}
+	lt, err := s.repo.FindType(lr.LeaveTypeID)
+	if err == domain.ErrNotFound {
+		return 0, fmt.Errorf("%w: leave_type_id %d tidak ditemukan", domain.ErrInvalidInput, lr.LeaveTypeID)
+	}
+	if err != nil { return 0, err }
+
+	dates, err := s.workingDates(lr)
+	if err != nil { return 0, err }
+	if len(dates) == 0 { return 0, fmt.Errorf("%w: rentang tanggal tidak memuat hari kerja", domain.ErrInvalidInput) }
+	lr.Days = float64(len(dates))
+
+	// Cek awal saja; pemotongan saldo yang sebenarnya terjadi saat approve.
+	if lt.AnnualQuota > 0 {
+		bs, err := s.repo.Balances(lr.EmployeeID, lr.StartDate.Year())
+		if err != nil { return 0, err }
+		for _, b := range bs {
+			if b.LeaveTypeID == lt.ID && b.Remaining < lr.Days {
+				return 0, fmt.Errorf("%w: saldo %s tersisa %.1f hari, diajukan %.1f hari", domain.ErrInvalidInput, lt.Name, b.Remaining, lr.Days)
+			}
+		}
+	}
+	lr.Status = domain.LeavePending
+	return s.repo.CreateRequest(lr)
+}
+
+func (s *LeaveService) GetRequest(ctx context.Context, id int64) (*domain.LeaveRequest, error) {
+	return s.loadRequest(ctx, ActionView, id)
+}
+
+// ListRequests otomatis dibatasi seperti ListTimesheets.
+func (s *LeaveService) ListRequests(ctx context.Context, f repository.LeaveFilter) ([]domain.LeaveRequest, error) {
+	sc, err := s.policy.Scope(ctx)
+	if err != nil { return nil, err }
+	f.Scope = sc
+	return s.repo.ListRequests(f)
+}
+
+// Approve memotong saldo dan menulis hari-hari cuti ke timesheet karyawan.
+// Hari kerja dihitung ulang saat approve (kalender libur bisa berubah sejak
+// pengajuan); saldo dipotong sesuai hari yang benar-benar ditulis.
+func (s *LeaveService) Approve(ctx context.Context, id int64, note string) error {
+	lr, err := s.loadRequest(ctx, ActionApprove, id)
+	if err != nil { return err }
+	if lr.Status != domain.LeavePending { return domain.ErrInvalidTransition }
+
+	dates, err := s.workingDates(lr)
+	if err != nil { return err }
+	if len(dates) == 0 { return fmt.Errorf("%w: rentang tanggal tidak lagi memuat hari kerja", domain.ErrInvalidInput) }
+	d := &domain.LeaveDecision{RequestID: id, From: domain.LeavePending, To: domain.LeaveApproved, Note: note}
+	type sheet struct {
+		ts    *domain.Timesheet
+		audit *domain.AuditEvent
+	}
+	sheets := map[[2]int]sheet{}
+	for _, date := range dates {
+		key := [2]int{date.Year(), int(date.Month())}
+		sh, ok := sheets[key]
+		if !ok {
+			sh.ts, sh.audit, err = monthlyTimesheet(ctx, s.timesheets, s.employees, s.holidays, lr.EmployeeID, key[0], key[1])
+			if err != nil { return err }
+			sheets[key] = sh
+		}
+		d.Days = append(d.Days, domain.LeaveDay{Date: date, Timesheet: sh.ts, TimesheetAudit: sh.audit})
+	}
+	d.Audit, err = newAuditEvent(ctx, domain.AuditEntityEntry, "", 0, 0, nil, map[string]interface{}{
+		"entry_type":       domain.EntryLeave,
+		"leave_type_id":    lr.LeaveTypeID,
+		"leave_request_id": lr.ID,
+	})
+	if err != nil { return err }
+	return s.decide(ctx, d)
+}
+
+func (s *LeaveService) Reject(ctx context.Context, id int64, reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: alasan penolakan wajib diisi", domain.ErrInvalidInput)
+	}
+	if _, err := s.loadRequest(ctx, ActionApprove, id); err != nil { return err }
+	return s.decide(ctx, &domain.LeaveDecision{RequestID: id, From: domain.LeavePending, To: domain.LeaveRejected, Note: reason})
+}
+
+// Cancel hanya untuk pengajuan yang masih pending, oleh pemiliknya (atau HR).
+func (s *LeaveService) Cancel(ctx context.Context, id int64) error {
+	if _, err := s.loadRequest(ctx, ActionEdit, id); err != nil { return err }
+	return s.decide(ctx, &domain.LeaveDecision{RequestID: id, From: domain.LeavePending, To: domain.LeaveCancelled})
+}
+
+func (s *LeaveService) decide(ctx context.Context, d *domain.LeaveDecision) error {
+	d.Actor = auth.PrincipalFrom(ctx).Username
+	return s.repo.Decide(ctx, d)
+}
+
+// loadRequest mengambil pengajuan lalu mengecek hak pemanggil atasnya.
+func (s *LeaveService) loadRequest(ctx context.Context, action Action, id int64) (*domain.LeaveRequest, error) {
+	lr, err := s.repo.FindRequest(id)
+	if err != nil { return nil, err }
+	emp, err := s.employee(lr.EmployeeID)
+	if err != nil { return nil, err }
+	if err := s.policy.AuthorizeEmployee(ctx, action, emp.ID, emp.DepartmentID); err != nil { return nil, err }
+	return lr, nil
+}
+
+// workingDates: hari kerja di rentang pengajuan cuti.
+func (s *LeaveService) workingDates(lr *domain.LeaveRequest) ([]time.Time, error) {
+	var hs []domain.Holiday
+	if s.holidays != nil {
+		var err error
+		if hs, err = s.holidays.Between(lr.StartDate, lr.EndDate); err != nil { return nil, err }
+	}
+	return calendar.WorkingDates(lr.StartDate, lr.EndDate, hs), nil
+}
+
+func (s *LeaveService) employee(id int64) (*domain.Employee, error) {
+	emp, err := s.employees.FindByID(id)
+	if err == domain.ErrNotFound {
+		return nil, fmt.Errorf("%w: employee_id %d tidak ditemukan", domain.ErrInvalidInput, id)
+	}
+	return emp, err
+}
+
+// ====== Balances ======
+
+func (s *LeaveService) Balances(ctx context.Context, employeeID int64, year int) ([]domain.LeaveBalance, error) {
+	if p := auth.PrincipalFrom(ctx); p != nil && p.EmployeeID != nil && employeeID == 0 {
+		employeeID = *p.EmployeeID
+	}
+	if year < 1900 || year > 2100 { return nil, fmt.Errorf("%w: year tidak valid", domain.ErrInvalidInput) }
+	emp, err := s.employee(employeeID)
+	if err != nil { return nil, err }
+	if err := s.policy.AuthorizeEmployee(ctx, ActionView, emp.ID, emp.DepartmentID); err != nil { return nil, err }
+	return s.repo.Balances(employeeID, year)
+}
+
+// SetEntitlement mengatur jatah cuti setahun (mis. karyawan baru yang pro-rata).
+func (s *LeaveService) SetEntitlement(b *domain.LeaveBalance) error {
+	if b.EmployeeID == 0 || b.LeaveTypeID == 0 || b.Year < 1900 || b.Year > 2100 {
+		return fmt.Errorf("%w: employee_id, leave_type_id, dan year wajib diisi", domain.ErrInvalidInput)
+	}
+	if b.Entitled < 0 { return fmt.Errorf("%w: entitled tidak boleh negatif", domain.ErrInvalidInput) }
+	if _, err := s.employee(b.EmployeeID); err != nil { return err }
+	return s.repo.SetEntitlement(b)
+}
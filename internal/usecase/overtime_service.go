@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type OvertimePolicyService struct {
+	repo repository.OvertimePolicyRepository
+}
+
+func NewOvertimePolicyService(r repository.OvertimePolicyRepository) *OvertimePolicyService {
+	return &OvertimePolicyService{repo: r}
+}
+
+func (s *OvertimePolicyService) CreatePolicy(p *domain.OvertimePolicy) (int64, error) {
+	if err := validatePolicy(p); err != nil { return 0, err }
+	return s.repo.Create(p)
+}
+func (s *OvertimePolicyService) GetPolicy(id int64) (*domain.OvertimePolicy, error) { return s.repo.FindByID(id) }
+func (s *OvertimePolicyService) ListPolicies() ([]domain.OvertimePolicy, error)     { return s.repo.List() }
+func (s *OvertimePolicyService) UpdatePolicy(p *domain.OvertimePolicy) error {
+	if p.ID == 0 { return domain.ErrInvalidInput }
+	if err := validatePolicy(p); err != nil { return err }
+	return s.repo.Update(p)
+}
+func (s *OvertimePolicyService) DeletePolicy(id int64) error { return s.repo.Delete(id) }
+
+// validatePolicy: multiplier 0 dianggap 1 (tanpa pengali).
+func validatePolicy(p *domain.OvertimePolicy) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("%w: name wajib diisi", domain.ErrInvalidInput)
+	}
+	if p.DailyThresholdHours < 0 || p.DailyThresholdHours > 24 || p.WeeklyThresholdHours < 0 || p.WeeklyThresholdHours > 168 {
+		return fmt.Errorf("%w: threshold di luar rentang", domain.ErrInvalidInput)
+	}
+	if p.BreakMinutes < 0 || p.BreakAfterHours < 0 {
+		return fmt.Errorf("%w: break tidak boleh negatif", domain.ErrInvalidInput)
+	}
+	if p.NightStart == "" { p.NightStart = "22:00" }
+	if p.NightEnd == "" { p.NightEnd = "06:00" }
+	for _, v := range []*string{&p.NightStart, &p.NightEnd} {
+		t, err := ParseTime(*v)
+		if err != nil { return fmt.Errorf("%w: night_start/night_end harus HH:MM", domain.ErrInvalidInput) }
+		*v = t.Format("15:04")
+	}
+	if p.NightStart == p.NightEnd {
+		return fmt.Errorf("%w: night_start dan night_end tidak boleh sama", domain.ErrInvalidInput)
+	}
+	for _, m := range []*float64{&p.OvertimeMultiplier, &p.WeekendMultiplier, &p.HolidayMultiplier} {
+		if *m == 0 { *m = 1 }
+		if *m < 1 {
+			return fmt.Errorf("%w: multiplier minimal 1", domain.ErrInvalidInput)
+		}
+	}
+	return nil
+}
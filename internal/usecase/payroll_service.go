@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/payroll"
+	"timesheet-api/internal/repository"
+)
+
+// PayrollService menyusun export payroll bulanan dari timesheet: rekap hari &
+// jam seperti Stats, plus jam lembur per multiplier sesuai formula.
+type PayrollService struct {
+	timesheets repository.TimesheetRepository
+	employees  repository.EmployeeRepository
+	formula    payroll.Formula
+	columns    []payroll.Column
+}
+
+func NewPayrollService(tr repository.TimesheetRepository, er repository.EmployeeRepository, f payroll.Formula, cols []payroll.Column) *PayrollService {
+	return &PayrollService{timesheets: tr, employees: er, formula: f, columns: cols}
+}
+
+// Columns: pemetaan kolom export yang dikonfigurasi.
+func (s *PayrollService) Columns() []payroll.Column { return s.columns }
+
+// Export hanya untuk HR. f wajib berisi Month & Year; status kosong = semua status.
+func (s *PayrollService) Export(ctx context.Context, f repository.Filter, status domain.TimesheetStatus) ([]domain.PayrollRow, error) {
+	if pr := auth.PrincipalFrom(ctx); pr == nil || pr.Role != domain.RoleHRAdmin { return nil, domain.ErrForbidden }
+	if f.Month == nil || f.Year == nil || *f.Month < 1 || *f.Month > 12 {
+		return nil, fmt.Errorf("%w: month dan year wajib diisi", domain.ErrInvalidInput)
+	}
+	items, err := s.timesheets.List(ctx, f)
+	if err != nil { return nil, err }
+
+	rows := make([]domain.PayrollRow, 0, len(items))
+	for _, it := range items {
+		if status != "" && it.Status != status { continue }
+		ts, err := s.timesheets.FindByID(ctx, it.ID)
+		if err != nil { return nil, err }
+		st, err := s.timesheets.Stats(ctx, it.ID)
+		if err != nil { return nil, err }
+		emp, err := s.employees.FindByID(ts.EmployeeID)
+		if err != nil { return nil, err }
+		code := ""
+		if emp.Code != nil { code = *emp.Code }
+		rows = append(rows, payroll.Build(ts, st, code, s.formula))
+	}
+	return rows, nil
+}
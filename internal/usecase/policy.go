@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+type Action string
+
+const (
+	ActionView    Action = "view"
+	ActionCreate  Action = "create"
+	ActionEdit    Action = "edit" // termasuk entries & delete
+	ActionSubmit  Action = "submit"
+	ActionApprove Action = "approve" // termasuk reject
+	ActionReopen  Action = "reopen"
+)
+
+// TimesheetPolicy adalah aturan RBAC timesheet:
+//   - employee: hanya timesheet miliknya sendiri (lihat, ubah, submit)
+//   - manager:  seperti employee, plus lihat/approve/reject/reopen timesheet di
+//     departemen yang dia pimpin beserta sub-departemennya (bukan miliknya sendiri)
+//   - hr_admin: semua
+type TimesheetPolicy struct {
+	departments repository.DepartmentRepository
+}
+
+func NewTimesheetPolicy(dr repository.DepartmentRepository) *TimesheetPolicy {
+	return &TimesheetPolicy{departments: dr}
+}
+
+// Authorize mengembalikan ErrUnauthorized jika tidak ada principal dan
+// ErrForbidden jika principal tidak boleh melakukan action terhadap ts.
+func (p *TimesheetPolicy) Authorize(ctx context.Context, action Action, ts *domain.Timesheet) error {
+	return p.AuthorizeEmployee(ctx, action, ts.EmployeeID, ts.DepartmentID)
+}
+
+// AuthorizeEmployee menerapkan aturan yang sama terhadap data milik karyawan
+// employeeID di departmentID (mis. pengajuan cuti).
+func (p *TimesheetPolicy) AuthorizeEmployee(ctx context.Context, action Action, employeeID int64, departmentID *int64) error {
+	pr := auth.PrincipalFrom(ctx)
+	if pr == nil {
+		return domain.ErrUnauthorized
+	}
+	if pr.Role == domain.RoleHRAdmin {
+		return nil
+	}
+	own := pr.EmployeeID != nil && *pr.EmployeeID == employeeID
+
+	switch action {
+	case ActionCreate, ActionEdit, ActionSubmit:
+		if own { return nil }
+	case ActionView:
+		if own { return nil }
+		if ok, err := p.manages(pr, departmentID); err != nil || ok { return err }
+	case ActionApprove, ActionReopen:
+		if own { return domain.ErrForbidden } // tidak boleh approve milik sendiri
+		if ok, err := p.manages(pr, departmentID); err != nil || ok { return err }
+	}
+	return domain.ErrForbidden
+}
+
+// Scope mengembalikan batasan list untuk pemanggil; nil berarti tanpa batas.
+func (p *TimesheetPolicy) Scope(ctx context.Context) (*repository.Scope, error) {
+	pr := auth.PrincipalFrom(ctx)
+	if pr == nil {
+		return nil, domain.ErrUnauthorized
+	}
+	if pr.Role == domain.RoleHRAdmin {
+		return nil, nil
+	}
+	sc := &repository.Scope{EmployeeID: pr.EmployeeID}
+	if pr.Role == domain.RoleManager && pr.EmployeeID != nil {
+		ids, err := p.departments.ManagedBy(*pr.EmployeeID)
+		if err != nil { return nil, err }
+		sc.DepartmentIDs = ids
+	}
+	return sc, nil
+}
+
+// manages: principal manager dari departmentID (atau induknya).
+func (p *TimesheetPolicy) manages(pr *domain.Principal, departmentID *int64) (bool, error) {
+	if pr.Role != domain.RoleManager || pr.EmployeeID == nil || departmentID == nil {
+		return false, nil
+	}
+	ids, err := p.departments.ManagedBy(*pr.EmployeeID)
+	if err != nil { return false, err }
+	for _, id := range ids {
+		if id == *departmentID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
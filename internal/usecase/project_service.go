@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// ProjectService mengelola project & task serta rekap jam yang dialokasikan ke project.
+type ProjectService struct {
+	repo   repository.ProjectRepository
+	policy *TimesheetPolicy
+}
+
+func NewProjectService(r repository.ProjectRepository, dr repository.DepartmentRepository) *ProjectService {
+	return &ProjectService{repo: r, policy: NewTimesheetPolicy(dr)}
+}
+
+// ====== Projects ======
+
+func (s *ProjectService) CreateProject(p *domain.Project) (int64, error) {
+	if err := validateProject(p); err != nil { return 0, err }
+	return s.repo.Create(p)
+}
+func (s *ProjectService) GetProject(id int64) (*domain.Project, error) { return s.repo.FindByID(id) }
+func (s *ProjectService) ListProjects(activeOnly bool) ([]domain.Project, error) {
+	return s.repo.List(activeOnly)
+}
+func (s *ProjectService) UpdateProject(p *domain.Project) error {
+	if p.ID == 0 { return domain.ErrInvalidInput }
+	if err := validateProject(p); err != nil { return err }
+	return s.repo.Update(p)
+}
+func (s *ProjectService) DeleteProject(id int64) error { return s.repo.Delete(id) }
+
+func validateProject(p *domain.Project) error {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Code == "" || p.Name == "" {
+		return fmt.Errorf("%w: code dan name wajib diisi", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+// ====== Tasks ======
+
+func (s *ProjectService) CreateTask(t *domain.Task) (int64, error) {
+	if err := s.validateTask(t); err != nil { return 0, err }
+	return s.repo.CreateTask(t)
+}
+func (s *ProjectService) GetTask(id int64) (*domain.Task, error) { return s.repo.FindTask(id) }
+func (s *ProjectService) ListTasks(projectID int64) ([]domain.Task, error) {
+	if _, err := s.repo.FindByID(projectID); err != nil { return nil, err }
+	return s.repo.ListTasks(projectID)
+}
+
+// UpdateTask tidak memindahkan task ke project lain.
+func (s *ProjectService) UpdateTask(t *domain.Task) error {
+	if t.ID == 0 { return domain.ErrInvalidInput }
+	cur, err := s.repo.FindTask(t.ID)
+	if err != nil { return err }
+	t.ProjectID = cur.ProjectID
+	if err := s.validateTask(t); err != nil { return err }
+	return s.repo.UpdateTask(t)
+}
+func (s *ProjectService) DeleteTask(id int64) error { return s.repo.DeleteTask(id) }
+
+func (s *ProjectService) validateTask(t *domain.Task) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" { return fmt.Errorf("%w: name wajib diisi", domain.ErrInvalidInput) }
+	_, err := s.repo.FindByID(t.ProjectID)
+	if err == domain.ErrNotFound {
+		return fmt.Errorf("%w: project_id %d tidak ditemukan", domain.ErrInvalidInput, t.ProjectID)
+	}
+	return err
+}
+
+// ====== Report ======
+
+// Hours merekap jam project per karyawan per bulan, dibatasi ke karyawan yang
+// boleh dilihat pemanggil (HR: semua, manager: departemennya, karyawan: diri sendiri).
+func (s *ProjectService) Hours(ctx context.Context, projectID int64, f repository.ProjectHoursFilter) (*domain.ProjectHoursReport, error) {
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return nil, fmt.Errorf("%w: to sebelum from", domain.ErrInvalidInput)
+	}
+	p, err := s.repo.FindByID(projectID)
+	if err != nil { return nil, err }
+	sc, err := s.policy.Scope(ctx)
+	if err != nil { return nil, err }
+	f.Scope = sc
+	rows, err := s.repo.Hours(projectID, f)
+	if err != nil { return nil, err }
+
+	out := &domain.ProjectHoursReport{Project: *p, Rows: rows}
+	if out.Rows == nil { out.Rows = []domain.ProjectHours{} }
+	for _, r := range rows {
+		out.TotalHours += r.Hours
+	}
+	out.TotalHours = math.Round(out.TotalHours*100) / 100
+	return out, nil
+}
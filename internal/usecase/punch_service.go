@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// PunchRule mengatur penutupan otomatis punch in yang lupa di-punch out.
+type PunchRule struct {
+	// MaxOpen: punch in yang terbuka lebih lama dari ini dianggap lupa punch out.
+	MaxOpen time.Duration
+	// CloseAfter: jam selesai otomatis = punch in + CloseAfter.
+	CloseAfter time.Duration
+	// CloseAt ("17:00"), jika diisi dan setelah punch in, dipakai sebagai jam selesai otomatis.
+	CloseAt string
+}
+
+// PunchInput adalah data punch dari client; waktu selalu diambil dari server.
+type PunchInput struct {
+	EmployeeID int64 // 0 = diri sendiri
+	Latitude   *float64
+	Longitude  *float64
+	Device     string
+	IP         string
+}
+
+// PunchService mencatat punch in/out mentah lalu membangun entry harian di
+// timesheet bulanan karyawan (dibuat otomatis jika belum ada).
+//
+// Satu hari = satu entry: punch in pertama mengisi start_time, punch out
+// terakhir mengisi end_time, jadi jeda di antara punch ikut terhitung.
+type PunchService struct {
+	repo   repository.PunchRepository
+	sheets *TimesheetService
+	rule   PunchRule
+	loc    *time.Location
+	now    func() time.Time
+}
+
+func NewPunchService(r repository.PunchRepository, ts *TimesheetService, rule PunchRule, loc *time.Location) *PunchService {
+	if loc == nil { loc = time.Local }
+	if rule.CloseAfter <= 0 { rule.CloseAfter = 8 * time.Hour }
+	return &PunchService{repo: r, sheets: ts, rule: rule, loc: loc, now: time.Now}
+}
+
+// WithClock mengganti sumber waktu (untuk test).
+func (s *PunchService) WithClock(now func() time.Time) *PunchService {
+	s.now = now
+	return s
+}
+
+// PunchIn membuka entry hari ini. Punch in terbuka yang sudah melewati
+// rule.MaxOpen ditutup otomatis lebih dulu.
+func (s *PunchService) PunchIn(ctx context.Context, in PunchInput) (*domain.Punch, *domain.TimesheetEntry, error) {
+	p, err := s.newPunch(ctx, domain.PunchIn, in)
+	if err != nil { return nil, nil, err }
+
+	open, err := s.repo.LastOpen(p.EmployeeID)
+	if err != nil { return nil, nil, err }
+	if open != nil {
+		if p.PunchedAt.Sub(open.PunchedAt) <= s.rule.MaxOpen {
+			return nil, nil, fmt.Errorf("%w: masih punch in sejak %s", domain.ErrInvalidTransition, open.PunchedAt.In(s.loc).Format("2006-01-02 15:04"))
+		}
+		if err := s.autoClose(ctx, open); err != nil { return nil, nil, err }
+	}
+
+	date := workDate(p.PunchedAt)
+	tsID, err := ensureTimesheet(ctx, s.sheets.repo, s.sheets.employees, s.sheets.holidays, p.EmployeeID, date.Year(), int(date.Month()))
+	if err != nil { return nil, nil, err }
+	ts, err := s.sheets.editable(ctx, tsID)
+	if err != nil { return nil, nil, err }
+
+	e := &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: date, Type: domain.EntryWork}
+	var before map[string]interface{}
+	for i := range ts.Entries {
+		cur := ts.Entries[i]
+		if !cur.WorkDate.Equal(date) { continue }
+		if cur.Type != domain.EntryWork {
+			return nil, nil, fmt.Errorf("%w: %s tercatat %s", domain.ErrInvalidInput, date.Format("2006-01-02"), cur.Type)
+		}
+		if len(cur.Segments) > 0 {
+			return nil, nil, fmt.Errorf("%w: entry %s sudah dirinci per segmen, ubah lewat entries", domain.ErrInvalidInput, date.Format("2006-01-02"))
+		}
+		e, before = &cur, entrySnapshot(&cur)
+		break
+	}
+	if e.StartTime == nil {
+		e.StartTime = clockOf(p.PunchedAt)
+	}
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, "", 0, 0, before, entrySnapshot(e))
+	if err != nil { return nil, nil, err }
+	if err := s.repo.Record(ctx, p, e, ev); err != nil { return nil, nil, err }
+	return p, e, nil
+}
+
+// PunchOut menutup entry dari punch in terakhir dan menghitung jamnya.
+func (s *PunchService) PunchOut(ctx context.Context, in PunchInput) (*domain.Punch, *domain.TimesheetEntry, error) {
+	p, err := s.newPunch(ctx, domain.PunchOut, in)
+	if err != nil { return nil, nil, err }
+
+	open, err := s.repo.LastOpen(p.EmployeeID)
+	if err != nil { return nil, nil, err }
+	if open == nil { return nil, nil, fmt.Errorf("%w: belum punch in", domain.ErrInvalidTransition) }
+	if p.PunchedAt.Sub(open.PunchedAt) > s.rule.MaxOpen {
+		if err := s.autoClose(ctx, open); err != nil { return nil, nil, err }
+		return nil, nil, fmt.Errorf("%w: punch in %s sudah ditutup otomatis, minta koreksi entry ke atasan",
+			domain.ErrInvalidTransition, open.PunchedAt.In(s.loc).Format("2006-01-02 15:04"))
+	}
+	if open.EntryID == nil {
+		return nil, nil, fmt.Errorf("%w: entry punch in sudah dihapus", domain.ErrInvalidInput)
+	}
+
+	cur, err := s.sheets.repo.FindEntryByID(ctx, *open.EntryID)
+	if err != nil { return nil, nil, err }
+	ts, err := s.sheets.editable(ctx, cur.TimesheetID)
+	if err != nil { return nil, nil, err }
+	// Shift malam boleh selesai keesokan harinya, asal kurang dari 24 jam.
+	if !workDate(p.PunchedAt).Equal(cur.WorkDate) && (cur.StartTime == nil ||
+		!workDate(p.PunchedAt).Equal(cur.WorkDate.AddDate(0, 0, 1)) || !clockOf(p.PunchedAt).Before(*cur.StartTime)) {
+		return nil, nil, fmt.Errorf("%w: shift %s lebih dari 24 jam", domain.ErrInvalidInput, cur.WorkDate.Format("2006-01-02"))
+	}
+
+	e := *cur
+	e.EndTime, e.TotalHours = clockOf(p.PunchedAt), nil
+	if err := s.sheets.computeHours(ctx, ts, &e); err != nil { return nil, nil, err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, "", 0, 0, entrySnapshot(cur), entrySnapshot(&e))
+	if err != nil { return nil, nil, err }
+	if err := s.repo.Record(ctx, p, &e, ev); err != nil { return nil, nil, err }
+	return p, &e, nil
+}
+
+// AutoCloseStale menutup semua punch in yang sudah melewati rule.MaxOpen,
+// untuk dijalankan berkala (cron) oleh HR.
+func (s *PunchService) AutoCloseStale(ctx context.Context) (int, error) {
+	if pr := auth.PrincipalFrom(ctx); pr == nil || pr.Role != domain.RoleHRAdmin { return 0, domain.ErrForbidden }
+	open, err := s.repo.OpenBefore(s.now().Add(-s.rule.MaxOpen))
+	if err != nil { return 0, err }
+	for i := range open {
+		if err := s.autoClose(ctx, &open[i]); err != nil { return i, err }
+	}
+	return len(open), nil
+}
+
+// ListPunches mengembalikan punch mentah karyawan di [from, to).
+func (s *PunchService) ListPunches(ctx context.Context, employeeID int64, from, to time.Time) ([]domain.Punch, error) {
+	emp, err := s.employee(ctx, employeeID)
+	if err != nil { return nil, err }
+	if err := s.sheets.policy.AuthorizeEmployee(ctx, ActionView, emp.ID, emp.DepartmentID); err != nil { return nil, err }
+	return s.repo.List(emp.ID, from, to)
+}
+
+// autoClose mencatat punch out otomatis sesuai rule dan menandai entry-nya.
+// Entry di timesheet yang sudah terkunci tidak diubah; punch-nya tetap ditutup.
+func (s *PunchService) autoClose(ctx context.Context, open *domain.Punch) error {
+	end := s.closeTime(open.PunchedAt)
+	p := &domain.Punch{EmployeeID: open.EmployeeID, Kind: domain.PunchOut, PunchedAt: end, Auto: true, EntryID: open.EntryID}
+	if open.EntryID == nil { return s.repo.Record(ctx, p, nil, nil) }
+
+	cur, err := s.sheets.repo.FindEntryByID(ctx, *open.EntryID)
+	if err == domain.ErrNotFound { return s.repo.Record(ctx, p, nil, nil) }
+	if err != nil { return err }
+	ts, err := s.sheets.repo.FindByID(ctx, cur.TimesheetID)
+	if err != nil { return err }
+	if !ts.Status.Editable() { return s.repo.Record(ctx, p, nil, nil) }
+
+	e := *cur
+	e.EndTime, e.TotalHours = clockOf(end), nil
+	e.Flagged = true
+	e.FlagReason = fmt.Sprintf("punch out tidak tercatat, ditutup otomatis pukul %s", end.In(s.loc).Format("15:04"))
+	if err := s.sheets.computeHours(ctx, ts, &e); err != nil { return err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, "", 0, 0, entrySnapshot(cur), entrySnapshot(&e))
+	if err != nil { return err }
+	return s.repo.Record(ctx, p, &e, ev)
+}
+
+// closeTime: punch in + CloseAfter, atau CloseAt di hari yang sama jika diisi
+// dan setelah punch in. Boleh lewat tengah malam (shift malam), tapi < 24 jam.
+func (s *PunchService) closeTime(in time.Time) time.Time {
+	in = in.In(s.loc)
+	end := in.Add(s.rule.CloseAfter)
+	if at, err := ParseTime(s.rule.CloseAt); err == nil && at != nil {
+		t := time.Date(in.Year(), in.Month(), in.Day(), at.Hour(), at.Minute(), 0, 0, s.loc)
+		if t.After(in) { end = t }
+	}
+	if last := in.Add(24*time.Hour - time.Minute); end.After(last) {
+		end = last
+	}
+	return end
+}
+
+// newPunch menyiapkan punch dengan waktu server untuk karyawan yang boleh diubah pemanggil.
+func (s *PunchService) newPunch(ctx context.Context, kind domain.PunchKind, in PunchInput) (*domain.Punch, error) {
+	emp, err := s.employee(ctx, in.EmployeeID)
+	if err != nil { return nil, err }
+	if err := s.sheets.policy.AuthorizeEmployee(ctx, ActionEdit, emp.ID, emp.DepartmentID); err != nil { return nil, err }
+	if !emp.Active { return nil, fmt.Errorf("%w: karyawan tidak aktif", domain.ErrInvalidInput) }
+	if (in.Latitude == nil) != (in.Longitude == nil) ||
+		(in.Latitude != nil && (*in.Latitude < -90 || *in.Latitude > 90 || *in.Longitude < -180 || *in.Longitude > 180)) {
+		return nil, fmt.Errorf("%w: latitude/longitude tidak valid", domain.ErrInvalidInput)
+	}
+	return &domain.Punch{
+		EmployeeID: emp.ID, Kind: kind, PunchedAt: s.now().In(s.loc).Truncate(time.Second),
+		Latitude: in.Latitude, Longitude: in.Longitude, Device: in.Device, IP: in.IP,
+	}, nil
+}
+
+// employee: employeeID 0 = karyawan milik pemanggil.
+func (s *PunchService) employee(ctx context.Context, employeeID int64) (*domain.Employee, error) {
+	if pr := auth.PrincipalFrom(ctx); pr != nil && pr.EmployeeID != nil && employeeID == 0 {
+		employeeID = *pr.EmployeeID
+	}
+	if employeeID == 0 { return nil, fmt.Errorf("%w: employee_id wajib diisi", domain.ErrInvalidInput) }
+	emp, err := s.sheets.employees.FindByID(employeeID)
+	if err == domain.ErrNotFound {
+		return nil, fmt.Errorf("%w: employee_id %d tidak ditemukan", domain.ErrInvalidInput, employeeID)
+	}
+	return emp, err
+}
+
+// workDate: tanggal lokal t sebagai DATE (UTC tengah malam), sama seperti ParseDate.
+func workDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// clockOf: jam lokal t dalam representasi yang sama dengan ParseTime.
+func clockOf(t time.Time) *time.Time {
+	c := time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return &c
+}
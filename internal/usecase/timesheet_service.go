@@ -1,65 +1,683 @@
 package usecase
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/calendar"
 	"timesheet-api/internal/domain"
+	"timesheet-api/internal/overtime"
 	"timesheet-api/internal/repository"
 )
 
 type TimesheetService struct {
-	repo repository.TimesheetRepository
+	repo             repository.TimesheetRepository
+	employees        repository.EmployeeRepository
+	departments      repository.DepartmentRepository
+	overtimePolicies repository.OvertimePolicyRepository // nil = lembur selalu dari input client
+	holidays         repository.HolidayRepository        // nil = tanpa kalender libur
+	projects         repository.ProjectRepository        // nil = alokasi project tidak dicek
+	tx               repository.TxManager                // nil = tiap panggilan repo berdiri sendiri
+	policy           *TimesheetPolicy
 }
 
-func NewTimesheetService(r repository.TimesheetRepository) *TimesheetService {
-	return &TimesheetService{repo: r}
+func NewTimesheetService(r repository.TimesheetRepository, er repository.EmployeeRepository, dr repository.DepartmentRepository,
+	op repository.OvertimePolicyRepository, hr repository.HolidayRepository, pr repository.ProjectRepository) *TimesheetService {
+	return &TimesheetService{repo: r, employees: er, departments: dr, overtimePolicies: op, holidays: hr, projects: pr,
+		policy: NewTimesheetPolicy(dr)}
 }
 
-func (s *TimesheetService) CreateTimesheet(ts *domain.Timesheet) (int64, error) {
-	if ts.EmployeeName == "" || ts.Month < 1 || ts.Month > 12 || ts.Year < 1900 || ts.Year > 2100 {
+// WithTx memasang TxManager untuk operasi yang menulis beberapa kali (mis.
+// timesheet beserta entries-nya) supaya atomik.
+func (s *TimesheetService) WithTx(m repository.TxManager) *TimesheetService {
+	s.tx = m
+	return s
+}
+
+// inTx menjalankan fn dengan salinan service yang repository timesheet-nya
+// terikat satu transaksi. Tanpa TxManager fn berjalan langsung dengan s.
+func (s *TimesheetService) inTx(ctx context.Context, fn func(ctx context.Context, s *TimesheetService) error) error {
+	if s.tx == nil { return fn(ctx, s) }
+	return s.tx.WithinTx(ctx, func(ctx context.Context, repos repository.TxRepos) error {
+		cp := *s
+		cp.repo = repos.Timesheets
+		return fn(ctx, &cp)
+	})
+}
+
+// CreateTimesheet membuat timesheet; ts.Entries (opsional) divalidasi dengan
+// aturan AddEntry lalu ditulis sekaligus (AddEntries) dalam transaksi yang sama,
+// jadi satu entry gagal → timesheet pun batal dibuat.
+func (s *TimesheetService) CreateTimesheet(ctx context.Context, ts *domain.Timesheet) (int64, error) {
+	if ts.Month < 1 || ts.Month > 12 || ts.Year < 1900 || ts.Year > 2100 {
 		return 0, domain.ErrInvalidInput
 	}
-	return s.repo.Create(ts)
+	// Tanpa employee_id/employee_name → timesheet milik pemanggil sendiri.
+	if p := auth.PrincipalFrom(ctx); p != nil && p.EmployeeID != nil && ts.EmployeeID == 0 && ts.EmployeeName == "" {
+		ts.EmployeeID = *p.EmployeeID
+	}
+	if err := s.resolveEmployee(ts); err != nil { return 0, err }
+	if err := s.policy.Authorize(ctx, ActionCreate, ts); err != nil { return 0, err }
+	if err := s.fillWorkingDays(ts); err != nil { return 0, err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditCreate, 0, 0, nil, timesheetSnapshot(ts))
+	if err != nil { return 0, err }
+	if len(ts.Entries) == 0 { return s.repo.Create(ctx, ts, ev) }
+
+	entries := ts.Entries
+	ts.Entries = nil
+	err = s.inTx(ctx, func(ctx context.Context, tx *TimesheetService) error {
+		id, err := tx.repo.Create(ctx, ts, ev)
+		if err != nil { return err }
+		cur, err := tx.editable(ctx, id)
+		if err != nil { return err }
+		// Entries belum ada di database saat dihitung, jadi jam regulernya
+		// ditambahkan ke batas lembur mingguan entry berikutnya lewat pending.
+		pending := &pendingWeekHours{TimesheetRepository: tx.repo, employeeID: ts.EmployeeID}
+		calc := *tx
+		calc.repo = pending
+		items := make([]repository.NewEntry, len(entries))
+		for i := range entries {
+			e := &entries[i]
+			e.TimesheetID = id
+			if e.WorkDate.IsZero() { return fmt.Errorf("entries[%d]: %w", i, domain.ErrInvalidInput) }
+			aev, err := calc.prepareEntry(ctx, cur, e)
+			if err != nil { return fmt.Errorf("entries[%d]: %w", i, err) }
+			cur.Entries = append(cur.Entries, *e)
+			pending.entries = append(pending.entries, e)
+			items[i] = repository.NewEntry{Entry: e, Audit: aev}
+		}
+		return tx.repo.AddEntries(ctx, items)
+	})
+	ts.Entries = entries
+	if err != nil { return 0, err }
+	return ts.ID, nil
+}
+
+// pendingWeekHours menambahkan jam reguler entries yang sudah dihitung tapi
+// belum ditulis ke WeekRegularHours.
+type pendingWeekHours struct {
+	repository.TimesheetRepository
+	employeeID int64
+	entries    []*domain.TimesheetEntry
 }
-func (s *TimesheetService) GetTimesheet(id int64) (*domain.Timesheet, error) { return s.repo.FindByID(id) }
-func (s *TimesheetService) ListTimesheets(f repository.Filter) ([]domain.Timesheet, error) {
-	return s.repo.List(f)
+
+func (w *pendingWeekHours) WeekRegularHours(ctx context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (float64, error) {
+	sum, err := w.TimesheetRepository.WeekRegularHours(ctx, employeeID, from, before, excludeEntryID)
+	if err != nil { return 0, err }
+	if employeeID != w.employeeID { return sum, nil }
+	for _, e := range w.entries {
+		if e.Overtime == nil || e.WorkDate.Before(from) || !e.WorkDate.Before(before) { continue }
+		sum += e.Overtime.RegularHours
+	}
+	return sum, nil
+}
+func (s *TimesheetService) GetTimesheet(ctx context.Context, id int64) (*domain.Timesheet, error) {
+	return s.load(ctx, ActionView, id)
+}
+
+// ListTimesheets otomatis dibatasi ke timesheet yang boleh dilihat pemanggil.
+func (s *TimesheetService) ListTimesheets(ctx context.Context, f repository.Filter) ([]domain.Timesheet, error) {
+	sc, err := s.policy.Scope(ctx)
+	if err != nil { return nil, err }
+	f.Scope = sc
+	return s.repo.List(ctx, f)
+}
+
+// PageTimesheets: satu halaman ListTimesheets (sesuai paging di f) beserta
+// jumlah total hasil filter.
+func (s *TimesheetService) PageTimesheets(ctx context.Context, f repository.Filter) ([]domain.Timesheet, int64, error) {
+	sc, err := s.policy.Scope(ctx)
+	if err != nil { return nil, 0, err }
+	f.Scope = sc
+	items, err := s.repo.List(ctx, f)
+	if err != nil { return nil, 0, err }
+	total, err := s.repo.Count(ctx, f)
+	if err != nil { return nil, 0, err }
+	return items, total, nil
 }
-func (s *TimesheetService) UpdateTimesheet(ts *domain.Timesheet) error {
+
+// StreamTimesheets seperti ListTimesheets, tapi hasilnya dikirim satu per satu
+// ke fn selama dibaca dari database (untuk export besar).
+func (s *TimesheetService) StreamTimesheets(ctx context.Context, f repository.Filter, withEntries bool, fn func(*domain.Timesheet) error) error {
+	sc, err := s.policy.Scope(ctx)
+	if err != nil { return err }
+	f.Scope = sc
+	return s.repo.Stream(ctx, f, withEntries, fn)
+}
+
+// ExportTimesheets mengembalikan timesheet hasil filter lengkap dengan entries-nya,
+// untuk export batch (PDF gabungan / ZIP / XLSX). Entries dimuat per potongan
+// lewat Stream, bukan satu FindByID per timesheet.
+func (s *TimesheetService) ExportTimesheets(ctx context.Context, f repository.Filter) ([]domain.Timesheet, error) {
+	var out []domain.Timesheet
+	err := s.StreamTimesheets(ctx, f, true, func(ts *domain.Timesheet) error {
+		out = append(out, *ts)
+		return nil
+	})
+	if err != nil { return nil, err }
+	return out, nil
+}
+func (s *TimesheetService) UpdateTimesheet(ctx context.Context, ts *domain.Timesheet) error {
 	if ts.ID == 0 { return domain.ErrInvalidInput }
-	return s.repo.Update(ts)
+	cur, err := s.editable(ctx, ts.ID)
+	if err != nil { return err }
+	if err := s.resolveEmployee(ts); err != nil { return err }
+	// Pemindahan ke karyawan lain juga harus diizinkan untuk pemilik barunya.
+	if err := s.policy.Authorize(ctx, ActionEdit, ts); err != nil { return err }
+	if err := s.fillWorkingDays(ts); err != nil { return err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditUpdate, ts.ID, ts.ID, timesheetSnapshot(cur), timesheetSnapshot(ts))
+	if err != nil { return err }
+	return s.repo.Update(ctx, ts, ev)
 }
-func (s *TimesheetService) DeleteTimesheet(id int64) error { return s.repo.Delete(id) }
 
-func (s *TimesheetService) AddEntry(e *domain.TimesheetEntry) (int64, error) {
-	if e.TimesheetID == 0 || e.WorkDate.IsZero() { return 0, domain.ErrInvalidInput }
-	if e.TotalHours == nil && e.StartTime != nil && e.EndTime != nil {
-		dur := e.EndTime.Sub(*e.StartTime).Hours()
-		h := math.Round(dur*100) / 100
-		e.TotalHours = &h
+// fillWorkingDays: total_working_days yang tidak diisi client dihitung dari
+// kalender (Senin–Jumat dikurangi hari libur).
+func (s *TimesheetService) fillWorkingDays(ts *domain.Timesheet) error {
+	if ts.TotalWorkingDays != nil || s.holidays == nil { return nil }
+	n, err := workingDays(s.holidays, ts.Year, ts.Month)
+	if err != nil { return err }
+	ts.TotalWorkingDays = &n
+	return nil
+}
+
+func workingDays(hr repository.HolidayRepository, year, month int) (int, error) {
+	from, to := calendar.MonthRange(year, month)
+	hs, err := hr.Between(from, to)
+	if err != nil { return 0, err }
+	return calendar.WorkingDays(year, month, hs), nil
+}
+
+// ensureTimesheet mengembalikan id timesheet bulanan karyawan, membuatnya jika
+// belum ada (dipakai punch, yang menulis entry tanpa timesheet dari client).
+func ensureTimesheet(ctx context.Context, tr repository.TimesheetRepository, er repository.EmployeeRepository,
+	hr repository.HolidayRepository, employeeID int64, year, month int) (int64, error) {
+	ts, ev, err := monthlyTimesheet(ctx, tr, er, hr, employeeID, year, month)
+	if err != nil { return 0, err }
+	if ts.ID != 0 { return ts.ID, nil }
+	return tr.Create(ctx, ts, ev)
+}
+
+// monthlyTimesheet mengembalikan timesheet bulanan karyawan yang sudah ada, atau
+// timesheet baru (ID 0, belum disimpan) beserta audit create-nya.
+func monthlyTimesheet(ctx context.Context, tr repository.TimesheetRepository, er repository.EmployeeRepository,
+	hr repository.HolidayRepository, employeeID int64, year, month int) (*domain.Timesheet, *domain.AuditEvent, error) {
+	items, err := tr.List(ctx, repository.Filter{EmployeeID: &employeeID, Month: &month, Year: &year})
+	if err != nil { return nil, nil, err }
+	if len(items) > 0 { return &items[0], nil, nil }
+
+	emp, err := er.FindByID(employeeID)
+	if err != nil { return nil, nil, err }
+	ts := &domain.Timesheet{EmployeeID: employeeID, DepartmentID: emp.DepartmentID, Month: month, Year: year}
+	if hr != nil {
+		n, err := workingDays(hr, year, month)
+		if err != nil { return nil, nil, err }
+		ts.TotalWorkingDays = &n
 	}
-	return s.repo.AddEntry(e)
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditCreate, 0, 0, nil, timesheetSnapshot(ts))
+	if err != nil { return nil, nil, err }
+	return ts, ev, nil
 }
-func (s *TimesheetService) UpdateEntry(e *domain.TimesheetEntry) error {
-	if e.ID == 0 { return domain.ErrInvalidInput }
-	if e.TotalHours == nil && e.StartTime != nil && e.EndTime != nil {
-		dur := e.EndTime.Sub(*e.StartTime).Hours()
-		h := math.Round(dur*100) / 100
-		e.TotalHours = &h
+
+// resolveEmployee memastikan ts.EmployeeID valid. Untuk kompatibilitas dengan
+// client lama, employee_name masih diterima selama namanya tidak ambigu.
+func (s *TimesheetService) resolveEmployee(ts *domain.Timesheet) error {
+	if ts.EmployeeID == 0 {
+		if ts.EmployeeName == "" {
+			return fmt.Errorf("%w: employee_id wajib diisi", domain.ErrInvalidInput)
+		}
+		found, err := s.employees.FindByName(ts.EmployeeName)
+		if err != nil { return err }
+		switch len(found) {
+		case 0:
+			return fmt.Errorf("%w: karyawan %q tidak ditemukan", domain.ErrInvalidInput, ts.EmployeeName)
+		case 1:
+			ts.EmployeeID = found[0].ID
+		default:
+			return fmt.Errorf("%w: nama %q dimiliki lebih dari satu karyawan, gunakan employee_id", domain.ErrInvalidInput, ts.EmployeeName)
+		}
+	}
+	emp, err := s.employees.FindByID(ts.EmployeeID)
+	if err == domain.ErrNotFound {
+		return fmt.Errorf("%w: employee_id %d tidak ditemukan", domain.ErrInvalidInput, ts.EmployeeID)
+	}
+	if err != nil { return err }
+	ts.EmployeeName = emp.Name
+	return s.resolveDepartment(ts, emp)
+}
+
+// resolveDepartment: department_id default ke departemen karyawan saat ini.
+func (s *TimesheetService) resolveDepartment(ts *domain.Timesheet, emp *domain.Employee) error {
+	if ts.DepartmentID == nil {
+		ts.DepartmentID = emp.DepartmentID
+	}
+	if ts.DepartmentID == nil {
+		ts.Department = ""
+		return nil
+	}
+	d, err := s.departments.FindByID(*ts.DepartmentID)
+	if err == domain.ErrNotFound {
+		return fmt.Errorf("%w: department_id %d tidak ditemukan", domain.ErrInvalidInput, *ts.DepartmentID)
+	}
+	if err != nil { return err }
+	ts.Department = d.Name
+	return nil
+}
+func (s *TimesheetService) DeleteTimesheet(ctx context.Context, id int64) error {
+	cur, err := s.editable(ctx, id)
+	if err != nil { return err }
+	// Entries ikut terhapus (cascade), jadi ikut disimpan di snapshot before.
+	before := timesheetSnapshot(cur)
+	entries := make([]map[string]interface{}, 0, len(cur.Entries))
+	for i := range cur.Entries {
+		entries = append(entries, entrySnapshot(&cur.Entries[i]))
+	}
+	before["entries"] = entries
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditDelete, id, id, before, nil)
+	if err != nil { return err }
+	return s.repo.Delete(ctx, id, ev)
+}
+
+// ====== Workflow status ======
+
+func (s *TimesheetService) Submit(ctx context.Context, id int64) error {
+	return s.transition(ctx, ActionSubmit, id, domain.StatusSubmitted, "")
+}
+func (s *TimesheetService) Approve(ctx context.Context, id int64) error {
+	return s.transition(ctx, ActionApprove, id, domain.StatusApproved, "")
+}
+func (s *TimesheetService) Reject(ctx context.Context, id int64, reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("%w: alasan penolakan wajib diisi", domain.ErrInvalidInput)
+	}
+	return s.transition(ctx, ActionApprove, id, domain.StatusRejected, reason)
+}
+func (s *TimesheetService) Reopen(ctx context.Context, id int64, reason string) error {
+	return s.transition(ctx, ActionReopen, id, domain.StatusReopened, reason)
+}
+func (s *TimesheetService) StatusHistory(ctx context.Context, id int64) ([]domain.StatusChange, error) {
+	if _, err := s.load(ctx, ActionView, id); err != nil { return nil, err }
+	return s.repo.StatusHistory(ctx, id)
+}
+
+// History mengembalikan audit trail lengkap (timesheet, entries, status).
+func (s *TimesheetService) History(ctx context.Context, id int64) ([]domain.AuditEvent, error) {
+	if _, err := s.load(ctx, ActionView, id); err != nil { return nil, err }
+	return s.repo.History(ctx, id)
+}
+
+// transition mencatat principal pemanggil sebagai pelaku perubahan status.
+func (s *TimesheetService) transition(ctx context.Context, action Action, id int64, to domain.TimesheetStatus, reason string) error {
+	ts, err := s.load(ctx, action, id)
+	if err != nil { return err }
+	if !ts.Status.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s → %s", domain.ErrInvalidTransition, ts.Status, to)
+	}
+	ch := &domain.StatusChange{
+		TimesheetID: id,
+		FromStatus:  ts.Status,
+		ToStatus:    to,
+		Actor:       auth.PrincipalFrom(ctx).Username,
+		Reason:      strings.TrimSpace(reason),
+	}
+	after := map[string]interface{}{"status": to}
+	if ch.Reason != "" { after["reason"] = ch.Reason }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityTimesheet, domain.AuditStatus, id, id,
+		map[string]interface{}{"status": ts.Status}, after)
+	if err != nil { return err }
+	return s.repo.ChangeStatus(ctx, ch, ev)
+}
+
+// load memuat timesheet lalu memastikan pemanggil boleh melakukan action.
+func (s *TimesheetService) load(ctx context.Context, action Action, id int64) (*domain.Timesheet, error) {
+	ts, err := s.repo.FindByID(ctx, id)
+	if err != nil { return nil, err }
+	if err := s.policy.Authorize(ctx, action, ts); err != nil { return nil, err }
+	return ts, nil
+}
+
+// editable seperti load(ActionEdit), plus menolak jika statusnya submitted/approved.
+func (s *TimesheetService) editable(ctx context.Context, id int64) (*domain.Timesheet, error) {
+	ts, err := s.load(ctx, ActionEdit, id)
+	if err != nil { return nil, err }
+	if !ts.Status.Editable() {
+		return nil, fmt.Errorf("%w: status %s", domain.ErrLocked, ts.Status)
 	}
-	return s.repo.UpdateEntry(e)
+	return ts, nil
 }
 
-func (s *TimesheetService) DeleteEntry(id int64) error {
+// ====== Entries ======
+
+func (s *TimesheetService) AddEntry(ctx context.Context, e *domain.TimesheetEntry) (int64, error) {
+	if e.TimesheetID == 0 || e.WorkDate.IsZero() { return 0, domain.ErrInvalidInput }
+	ts, err := s.editable(ctx, e.TimesheetID)
+	if err != nil { return 0, err }
+	ev, err := s.prepareEntry(ctx, ts, e)
+	if err != nil { return 0, err }
+	return s.repo.AddEntry(ctx, e, ev)
+}
+
+// prepareEntry memvalidasi entry baru untuk ts, menghitung jam & alokasinya,
+// dan menyiapkan event audit create-nya.
+func (s *TimesheetService) prepareEntry(ctx context.Context, ts *domain.Timesheet, e *domain.TimesheetEntry) (*domain.AuditEvent, error) {
+	if err := uniqueDate(ts, e); err != nil { return nil, err }
+	if err := validateEntryType(e); err != nil { return nil, err }
+	if err := s.computeHours(ctx, ts, e); err != nil { return nil, err }
+	if err := s.allocate(e); err != nil { return nil, err }
+	return newAuditEvent(ctx, domain.AuditEntityEntry, domain.AuditCreate, e.TimesheetID, 0, nil, entrySnapshot(e))
+}
+// UpdateEntry adalah koreksi manual, jadi flag (mis. dari auto-close punch) ikut dihapus.
+func (s *TimesheetService) UpdateEntry(ctx context.Context, e *domain.TimesheetEntry) error {
+	if e.ID == 0 { return domain.ErrInvalidInput }
+	cur, err := s.repo.FindEntryByID(ctx, e.ID)
+	if err != nil { return err }
+	ts, err := s.editable(ctx, cur.TimesheetID)
+	if err != nil { return err }
+	e.TimesheetID = cur.TimesheetID
+	if e.WorkDate.IsZero() { e.WorkDate = cur.WorkDate }
+	if e.Type == "" { e.Type = cur.Type }
+	if e.Type == cur.Type && e.LeaveTypeID == nil { e.LeaveTypeID = cur.LeaveTypeID }
+	if err := uniqueDate(ts, e); err != nil { return err }
+	if err := validateEntryType(e); err != nil { return err }
+	if err := s.computeHours(ctx, ts, e); err != nil { return err }
+	if err := s.allocate(e); err != nil { return err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, domain.AuditUpdate, e.TimesheetID, e.ID, entrySnapshot(cur), entrySnapshot(e))
+	if err != nil { return err }
+	return s.repo.UpdateEntry(ctx, e, ev)
+}
+
+func (s *TimesheetService) DeleteEntry(ctx context.Context, id int64) error {
 	if id <= 0 {
 		return domain.ErrInvalidInput
 	}
-	return s.repo.DeleteEntry(id)
+	cur, err := s.repo.FindEntryByID(ctx, id)
+	if err != nil { return err }
+	if _, err := s.editable(ctx, cur.TimesheetID); err != nil { return err }
+	ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, domain.AuditDelete, cur.TimesheetID, id, entrySnapshot(cur), nil)
+	if err != nil { return err }
+	return s.repo.DeleteEntry(ctx, id, ev)
+}
+
+// RecalculateOvertime menghitung ulang lembur semua entry (urut tanggal), mis.
+// setelah policy diubah atau entry hari sebelumnya dikoreksi. Semua entry
+// diperbarui dalam satu transaksi.
+func (s *TimesheetService) RecalculateOvertime(ctx context.Context, id int64) error {
+	return s.inTx(ctx, func(ctx context.Context, s *TimesheetService) error {
+		ts, err := s.editable(ctx, id)
+		if err != nil { return err }
+		for i := range ts.Entries {
+			cur := ts.Entries[i]
+			e := cur
+			if e.StartTime != nil && e.EndTime != nil { e.TotalHours = nil } // hitung ulang dari jam mulai/selesai
+			if err := s.computeHours(ctx, ts, &e); err != nil { return err }
+			ev, err := newAuditEvent(ctx, domain.AuditEntityEntry, domain.AuditUpdate, id, e.ID, entrySnapshot(&cur), entrySnapshot(&e))
+			if err != nil { return err }
+			if err := s.repo.UpdateEntry(ctx, &e, ev); err != nil { return err }
+		}
+		return nil
+	})
+}
+
+// validateEntryType: default work; leave wajib punya leave_type_id.
+// Entry cuti/tidak hadir tidak punya jam kerja maupun lembur.
+func validateEntryType(e *domain.TimesheetEntry) error {
+	if e.Type == "" { e.Type = domain.EntryWork }
+	if !e.Type.Valid() {
+		return fmt.Errorf("%w: entry_type %q tidak dikenal", domain.ErrInvalidInput, e.Type)
+	}
+	switch e.Type {
+	case domain.EntryWork:
+		e.LeaveTypeID = nil
+	case domain.EntryLeave:
+		if e.LeaveTypeID == nil {
+			return fmt.Errorf("%w: leave_type_id wajib untuk entry cuti", domain.ErrInvalidInput)
+		}
+	case domain.EntryAbsent:
+		e.LeaveTypeID = nil
+	}
+	if e.Type != domain.EntryWork {
+		e.StartTime, e.EndTime, e.TotalHours, e.OvertimeHours, e.Segments, e.Allocations = nil, nil, nil, nil, nil, nil
+		e.Billable, e.ClientID = false, nil
+	}
+	return nil
+}
+
+// computeHours mengisi TotalHours dari jam mulai/selesai (jika kosong), lalu
+// menghitung lembur dengan policy departemen timesheet. Shift yang melewati
+// tengah malam (end ≤ start) dihitung utuh ke work_date.
+func (s *TimesheetService) computeHours(ctx context.Context, ts *domain.Timesheet, e *domain.TimesheetEntry) error {
+	var p *domain.OvertimePolicy
+	if s.overtimePolicies != nil {
+		var err error
+		if p, err = s.overtimePolicies.ForDepartment(ts.DepartmentID); err != nil { return err }
+	}
+
+	e.EndsNextDay, e.NightHours = false, nil
+	if len(e.Segments) > 0 {
+		if err := applySegments(e, nightWindow(p)); err != nil { return err }
+		// Istirahat sudah tercatat sebagai segmen, jadi tidak dipotong lagi oleh policy.
+		return s.applyOvertime(ctx, p, ts, e, false)
+	}
+	hasClock := e.StartTime != nil && e.EndTime != nil
+	if hasClock {
+		if e.StartTime.Equal(*e.EndTime) {
+			return fmt.Errorf("%w: start_time dan end_time tidak boleh sama", domain.ErrInvalidInput)
+		}
+		e.EndsNextDay = overtime.EndsNextDay(*e.StartTime, *e.EndTime)
+		night := overtime.NightHours(*e.StartTime, *e.EndTime, nightWindow(p))
+		e.NightHours = &night
+	}
+	fromClock := e.TotalHours == nil && hasClock
+	if fromClock {
+		h := overtime.ShiftHours(*e.StartTime, *e.EndTime)
+		e.TotalHours = &h
+	}
+	if err := s.applyOvertime(ctx, p, ts, e, fromClock); err != nil { return err }
+	if e.TotalHours != nil {
+		h := math.Round(*e.TotalHours*100) / 100
+		e.TotalHours = &h
+	}
+	return nil
+}
+
+// applySegments memvalidasi segmen (tidak boleh tumpang tindih, rentang total
+// maksimal 24 jam) lalu menurunkan start/end/total_hours/night_hours entry:
+// start = segmen pertama, end = segmen terakhir, total = semua segmen selain break.
+func applySegments(e *domain.TimesheetEntry, w overtime.Window) error {
+	spans := make([]overtime.Span, len(e.Segments))
+	for i := range e.Segments {
+		sg := &e.Segments[i]
+		if sg.Type == "" { sg.Type = domain.SegmentWork }
+		if !sg.Type.Valid() {
+			return fmt.Errorf("%w: tipe segmen %q tidak dikenal", domain.ErrInvalidInput, sg.Type)
+		}
+		if sg.StartTime.Equal(sg.EndTime) {
+			return fmt.Errorf("%w: segmen %s: start_time dan end_time tidak boleh sama", domain.ErrInvalidInput, sg.StartTime.Format("15:04"))
+		}
+		spans[i] = overtime.NewSpan(sg.StartTime, sg.EndTime, sg.StartsNextDay)
+	}
+	order := make([]int, len(spans))
+	for i := range order { order[i] = i }
+	sort.SliceStable(order, func(a, b int) bool { return spans[order[a]].From < spans[order[b]].From })
+
+	sorted := make([]domain.EntrySegment, 0, len(order))
+	var worked, night float64
+	first, last := spans[order[0]], spans[order[0]]
+	for n, i := range order {
+		sp, sg := spans[i], e.Segments[i]
+		if n > 0 && sp.Overlaps(last) {
+			return fmt.Errorf("%w: segmen %s–%s tumpang tindih dengan segmen sebelumnya", domain.ErrInvalidInput,
+				sg.StartTime.Format("15:04"), sg.EndTime.Format("15:04"))
+		}
+		if sp.To > last.To { last = sp }
+		if sg.Type != domain.SegmentBreak {
+			worked += sp.Hours()
+			night += sp.NightHours(w)
+		}
+		sorted = append(sorted, sg)
+	}
+	if last.To-first.From > 24*time.Hour {
+		return fmt.Errorf("%w: rentang segmen lebih dari 24 jam", domain.ErrInvalidInput)
+	}
+
+	start, end := overtime.Clock(first.From), overtime.Clock(last.To)
+	night = math.Round(night*100) / 100
+	e.Segments, e.StartTime, e.EndTime, e.TotalHours, e.NightHours = sorted, &start, &end, &worked, &night
+	e.EndsNextDay = last.EndsNextDay()
+	return nil
+}
+
+// allocate memvalidasi alokasi project entry. Entry bersegmen dialokasikan lewat
+// project_id/task_id tiap segmen (jamnya dijumlah per project/task); entry biasa
+// lewat Allocations, totalnya tidak boleh melebihi total_hours.
+func (s *TimesheetService) allocate(e *domain.TimesheetEntry) error {
+	if len(e.Segments) > 0 {
+		if len(e.Allocations) > 0 {
+			return fmt.Errorf("%w: entry bersegmen dialokasikan lewat project_id di tiap segmen", domain.ErrInvalidInput)
+		}
+		e.Allocations = segmentAllocations(e.Segments)
+	} else if len(e.Allocations) > 0 {
+		if e.TotalHours == nil {
+			return fmt.Errorf("%w: alokasi project butuh total_hours atau start_time/end_time", domain.ErrInvalidInput)
+		}
+		var sum float64
+		for i := range e.Allocations {
+			a := &e.Allocations[i]
+			a.Hours = math.Round(a.Hours*100) / 100
+			if a.Hours <= 0 {
+				return fmt.Errorf("%w: jam alokasi project harus > 0", domain.ErrInvalidInput)
+			}
+			sum += a.Hours
+		}
+		if sum > *e.TotalHours+0.005 {
+			return fmt.Errorf("%w: total alokasi %.2f jam melebihi total_hours %.2f", domain.ErrInvalidInput, sum, *e.TotalHours)
+		}
+	}
+	for _, sg := range e.Segments {
+		if sg.TaskID != nil && sg.ProjectID == nil {
+			return fmt.Errorf("%w: task_id segmen wajib disertai project_id", domain.ErrInvalidInput)
+		}
+		if sg.ProjectID != nil && sg.Type == domain.SegmentBreak {
+			return fmt.Errorf("%w: segmen break tidak bisa dialokasikan ke project", domain.ErrInvalidInput)
+		}
+	}
+	for _, a := range e.Allocations {
+		if err := s.checkProject(a.ProjectID, a.TaskID); err != nil { return err }
+	}
+	return s.resolveClient(e)
+}
+
+// resolveClient: entry billable wajib punya klien; jika kosong dipakai klien
+// project alokasi pertama yang punya klien.
+func (s *TimesheetService) resolveClient(e *domain.TimesheetEntry) error {
+	if !e.Billable || e.ClientID != nil { return nil }
+	if s.projects != nil {
+		for _, a := range e.Allocations {
+			p, err := s.projects.FindByID(a.ProjectID)
+			if err != nil { return err }
+			if p.ClientID != nil {
+				e.ClientID = p.ClientID
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: client_id wajib untuk entry billable", domain.ErrInvalidInput)
+}
+
+// segmentAllocations menjumlah jam segmen ber-project per project/task, urut kemunculan.
+func segmentAllocations(segments []domain.EntrySegment) []domain.EntryAllocation {
+	type key struct {
+		project int64
+		task    int64
+	}
+	var out []domain.EntryAllocation
+	idx := map[key]int{}
+	for _, sg := range segments {
+		if sg.ProjectID == nil || sg.Type == domain.SegmentBreak { continue }
+		k := key{project: *sg.ProjectID}
+		if sg.TaskID != nil { k.task = *sg.TaskID }
+		h := overtime.NewSpan(sg.StartTime, sg.EndTime, sg.StartsNextDay).Hours()
+		if i, ok := idx[k]; ok {
+			out[i].Hours += h
+			continue
+		}
+		idx[k] = len(out)
+		out = append(out, domain.EntryAllocation{ProjectID: k.project, TaskID: sg.TaskID, Hours: h})
+	}
+	for i := range out {
+		out[i].Hours = math.Round(out[i].Hours*100) / 100
+	}
+	return out
+}
+
+// checkProject: project harus ada & aktif, task (jika diisi) milik project itu dan aktif.
+func (s *TimesheetService) checkProject(projectID int64, taskID *int64) error {
+	if s.projects == nil { return nil }
+	p, err := s.projects.FindByID(projectID)
+	if err == domain.ErrNotFound {
+		return fmt.Errorf("%w: project_id %d tidak ditemukan", domain.ErrInvalidInput, projectID)
+	}
+	if err != nil { return err }
+	if !p.Active { return fmt.Errorf("%w: project %s sudah tidak aktif", domain.ErrInvalidInput, p.Code) }
+	if taskID == nil { return nil }
+	t, err := s.projects.FindTask(*taskID)
+	if err == domain.ErrNotFound || (err == nil && t.ProjectID != projectID) {
+		return fmt.Errorf("%w: task_id %d bukan bagian dari project %s", domain.ErrInvalidInput, *taskID, p.Code)
+	}
+	if err != nil { return err }
+	if !t.Active { return fmt.Errorf("%w: task %q sudah tidak aktif", domain.ErrInvalidInput, t.Name) }
+	return nil
+}
+
+// uniqueDate: satu entry per tanggal per timesheet.
+func uniqueDate(ts *domain.Timesheet, e *domain.TimesheetEntry) error {
+	day := e.WorkDate.Format("2006-01-02")
+	for _, x := range ts.Entries {
+		// x.ID 0: entry lain di batch yang sama, belum tersimpan (CreateTimesheet).
+		if (x.ID != e.ID || x.ID == 0) && x.WorkDate.Format("2006-01-02") == day {
+			return fmt.Errorf("%w: sudah ada entry untuk %s, tambahkan sebagai segmen", domain.ErrDuplicate, day)
+		}
+	}
+	return nil
+}
+
+// nightWindow: window malam dari policy, atau 22:00–06:00 jika tanpa policy.
+func nightWindow(p *domain.OvertimePolicy) overtime.Window {
+	if p == nil { return overtime.DefaultNightWindow }
+	start, err1 := ParseTime(p.NightStart)
+	end, err2 := ParseTime(p.NightEnd)
+	if err1 != nil || err2 != nil || start == nil || end == nil { return overtime.DefaultNightWindow }
+	return overtime.Window{Start: *start, End: *end}
+}
+
+// applyOvertime: tanpa policy (departemen maupun default), overtime_hours dari
+// client dipakai apa adanya. Istirahat hanya dipotong jika jam dihitung dari
+// jam mulai/selesai; total_hours manual dianggap sudah bersih.
+func (s *TimesheetService) applyOvertime(ctx context.Context, p *domain.OvertimePolicy, ts *domain.Timesheet, e *domain.TimesheetEntry, deductBreak bool) error {
+	e.Overtime = nil
+	if p == nil || e.TotalHours == nil { return nil }
+
+	week, err := s.repo.WeekRegularHours(ctx, ts.EmployeeID, overtime.WeekStart(e.WorkDate), e.WorkDate, e.ID)
+	if err != nil { return err }
+	holiday := false
+	if s.holidays != nil {
+		hs, err := s.holidays.Between(e.WorkDate, e.WorkDate)
+		if err != nil { return err }
+		holiday = len(hs) > 0
+	}
+	b := overtime.Calculate(*p, overtime.Day{Date: e.WorkDate, GrossHours: *e.TotalHours, DeductBreak: deductBreak, Holiday: holiday, WeekRegularHours: week})
+	net := b.GrossHours - b.BreakHours
+	e.TotalHours, e.OvertimeHours, e.Overtime = &net, &b.OvertimeHours, &b
+	return nil
 }
 
-func (s *TimesheetService) Stats(id int64) (int64, float64, float64, error) {
-	return s.repo.Stats(id)
+func (s *TimesheetService) Stats(ctx context.Context, id int64) (*domain.TimesheetStats, error) {
+	if _, err := s.load(ctx, ActionView, id); err != nil { return nil, err }
+	return s.repo.Stats(ctx, id)
 }
 
 func ParseDate(s string) (time.Time, error) { return time.Parse("2006-01-02", s) }
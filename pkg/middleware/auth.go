@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/resp"
+)
+
+const principalKey = "principal"
+
+// TokenVerifier memvalidasi access token dan mengembalikan principal-nya.
+type TokenVerifier interface {
+	Verify(token string) (*domain.Principal, error)
+}
+
+// Auth mewajibkan header "Authorization: Bearer <jwt>" kecuali untuk publicPaths.
+// Principal disimpan di gin context (lihat CurrentPrincipal) dan di context
+// request (auth.PrincipalFrom) supaya bisa dibaca layer usecase.
+func Auth(v TokenVerifier, publicPaths ...string) gin.HandlerFunc {
+	public := make(map[string]bool, len(publicPaths))
+	for _, p := range publicPaths {
+		public[p] = true
+	}
+	return func(c *gin.Context) {
+		if public[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		h := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(h, "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			resp.Unauthorized(c, "Missing bearer token")
+			c.Abort()
+			return
+		}
+		p, err := v.Verify(strings.TrimSpace(token))
+		if err != nil {
+			resp.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+		c.Set(principalKey, p)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+	}
+}
+
+// RequireRole membatasi route ke role tertentu. Pasang setelah Auth.
+func RequireRole(roles ...domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := CurrentPrincipal(c)
+		if p == nil {
+			resp.Unauthorized(c, "Unauthenticated")
+			c.Abort()
+			return
+		}
+		for _, r := range roles {
+			if p.Role == r {
+				c.Next()
+				return
+			}
+		}
+		resp.Forbidden(c, "Forbidden")
+		c.Abort()
+	}
+}
+
+// CurrentPrincipal mengembalikan principal yang diset Auth, nil jika anonim.
+func CurrentPrincipal(c *gin.Context) *domain.Principal {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	p, _ := v.(*domain.Principal)
+	return p
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/audit"
 )
 
 func RequestID() gin.HandlerFunc {
@@ -14,6 +16,7 @@ func RequestID() gin.HandlerFunc {
 			rid = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
 		c.Set("request_id", rid)
+		c.Request = c.Request.WithContext(audit.WithRequestID(c.Request.Context(), rid))
 		c.Writer.Header().Set("X-Request-ID", rid)
 		c.Next()
 	}
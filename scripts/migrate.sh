@@ -3,5 +3,7 @@ set -euo pipefail
 if [ -f ".env" ]; then
   export $(grep -v '^#' .env | xargs)
 fi
-# Migration dijalankan otomatis saat start aplikasi.
-echo "Migrations are executed on app start via internal/db/migrate.go"
+# Contoh: scripts/migrate.sh status | up [N] | down [N] | redo | create <name>
+#         scripts/migrate.sh --dry-run up
+# API juga menjalankan migrasi tertunda saat start kecuali AUTO_MIGRATE=false.
+go run ./cmd/migrate "$@"
@@ -0,0 +1,97 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+)
+
+const secret = "0123456789abcdef0123456789abcdef"
+
+func TestHS256RoundTrip(t *testing.T) {
+	tm, err := auth.NewTokenManager(auth.TokenConfig{Algorithm: "HS256", Secret: secret, Issuer: "test", AccessTTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	emp := int64(7)
+	tok, err := tm.Issue(domain.Principal{UserID: 42, Username: "arif", Role: domain.RoleManager, EmployeeID: &emp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := tm.Verify(tok)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.UserID != 42 || p.Username != "arif" || p.Role != domain.RoleManager || p.EmployeeID == nil || *p.EmployeeID != 7 {
+		t.Errorf("principal = %+v", p)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	tm, _ := auth.NewTokenManager(auth.TokenConfig{Secret: secret, Issuer: "test", AccessTTL: time.Minute})
+	other, _ := auth.NewTokenManager(auth.TokenConfig{Secret: secret + "x", Issuer: "test", AccessTTL: time.Minute})
+
+	bad, _ := other.Issue(domain.Principal{UserID: 1, Role: domain.RoleEmployee})
+	old, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "1",
+		Issuer:    "test",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}).SignedString([]byte(secret))
+	for name, tok := range map[string]string{"signature": bad, "expired": old, "garbage": "a.b.c"} {
+		if _, err := tm.Verify(tok); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Errorf("%s: got %v, want ErrUnauthorized", name, err)
+		}
+	}
+}
+
+func TestRS256RoundTrip(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tm, err := auth.NewTokenManager(auth.TokenConfig{Algorithm: "RS256", PrivateKeyFile: path, AccessTTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := tm.Issue(domain.Principal{UserID: 3, Username: "hr", Role: domain.RoleHRAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := tm.Verify(tok); err != nil || p.UserID != 3 {
+		t.Fatalf("verify: %v %+v", err, p)
+	}
+
+	// Token HS256 tidak boleh lolos di manager RS256 (algorithm confusion).
+	hs, _ := auth.NewTokenManager(auth.TokenConfig{Secret: secret, AccessTTL: time.Minute})
+	hsTok, _ := hs.Issue(domain.Principal{UserID: 3, Role: domain.RoleHRAdmin})
+	if _, err := tm.Verify(hsTok); err == nil {
+		t.Error("token HS256 diterima oleh verifier RS256")
+	}
+}
+
+func TestRefreshTokenHash(t *testing.T) {
+	raw, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw == "" || len(hash) != 64 || auth.HashToken(raw) != hash {
+		t.Errorf("raw=%q hash=%q", raw, hash)
+	}
+}
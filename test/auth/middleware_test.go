@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/pkg/middleware"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tm, _ := auth.NewTokenManager(auth.TokenConfig{Secret: secret, AccessTTL: time.Minute})
+
+	r := gin.New()
+	r.Use(middleware.Auth(tm, "/health"))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/me", func(c *gin.Context) {
+		p := auth.PrincipalFrom(c.Request.Context())
+		if p == nil || middleware.CurrentPrincipal(c) != p {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, p.Username)
+	})
+	r.GET("/admin", middleware.RequireRole(domain.RoleHRAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tok, _ := tm.Issue(domain.Principal{UserID: 1, Username: "arif", Role: domain.RoleEmployee})
+	cases := []struct {
+		path, auth string
+		want       int
+	}{
+		{"/health", "", http.StatusOK},
+		{"/me", "", http.StatusUnauthorized},
+		{"/me", "Bearer nope", http.StatusUnauthorized},
+		{"/me", "Bearer " + tok, http.StatusOK},
+		{"/admin", "Bearer " + tok, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s (%q): got %d, want %d", tc.path, tc.auth, w.Code, tc.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package calendar_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/calendar"
+	"timesheet-api/internal/domain"
+)
+
+const sampleICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Hari Libur di Indonesia\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250331\r\n" +
+	"DTEND;VALUE=DATE:20250402\r\n" +
+	"SUMMARY:Hari Raya Idul Fitri\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20250404\r\n" +
+	"SUMMARY:Cuti Bersama Idul Fitri\\, hari ke-4\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20250418T000000Z\r\n" +
+	"SUMMARY:Wafat Yesus\r\n" +
+	"  Kristus\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	items, err := calendar.ParseICS(strings.NewReader(sampleICS), domain.HolidayNational)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		date, name string
+		kind       domain.HolidayKind
+	}{
+		{"2025-03-31", "Hari Raya Idul Fitri", domain.HolidayNational},
+		{"2025-04-01", "Hari Raya Idul Fitri", domain.HolidayNational},
+		{"2025-04-04", "Cuti Bersama Idul Fitri, hari ke-4", domain.HolidayCutiBersama},
+		{"2025-04-18", "Wafat Yesus Kristus", domain.HolidayNational},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d hari libur, want %d: %+v", len(items), len(want), items)
+	}
+	for i, w := range want {
+		got := items[i]
+		if got.Date.Format("2006-01-02") != w.date || got.Name != w.name || got.Kind != w.kind {
+			t.Errorf("[%d] = %s %q %s, want %s %q %s", i, got.Date.Format("2006-01-02"), got.Name, got.Kind, w.date, w.name, w.kind)
+		}
+	}
+}
+
+func TestParseICSRejectsMissingStart(t *testing.T) {
+	_, err := calendar.ParseICS(strings.NewReader("BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n"), domain.HolidayNational)
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestWorkingDays(t *testing.T) {
+	day := func(d int) domain.Holiday { return domain.Holiday{Date: time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC)} }
+	// April 2025: 22 hari Senin–Jumat.
+	if got := calendar.WorkingDays(2025, 4, nil); got != 22 {
+		t.Errorf("tanpa libur: got %d, want 22", got)
+	}
+	// Libur di hari kerja (1, 4, 18; duplikat dihitung sekali) dikurangi, yang jatuh di Minggu (6) tidak.
+	got := calendar.WorkingDays(2025, 4, []domain.Holiday{day(1), day(4), day(6), day(18), day(18)})
+	if got != 19 {
+		t.Errorf("dengan libur: got %d, want 19", got)
+	}
+}
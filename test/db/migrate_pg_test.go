@@ -0,0 +1,109 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"timesheet-api/internal/db"
+)
+
+// openSchema membuka TEST_DB_DSN dengan search_path ke schema baru yang kosong,
+// supaya migrasi bisa dijalankan dari awal tanpa mengganggu test lain.
+func openSchema(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN kosong, test migrasi Postgres dilewati")
+	}
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	conn, err := sql.Open("pgx", dsn+sep+"search_path="+schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestUniqueDateMigrationOnDuplicatedData(t *testing.T) {
+	conn := openSchema(t)
+	ctx := context.Background()
+	m, err := db.NewMigrator(conn, db.Source())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx, 12); err != nil {
+		t.Fatal(err)
+	}
+	var tsID int64
+	err = conn.QueryRow(`WITH e AS (INSERT INTO employees (name) VALUES ('Budi') RETURNING id)
+	                     INSERT INTO timesheets (employee_id, month, year) SELECT id, 7, 2025 FROM e RETURNING id`).Scan(&tsID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec(`INSERT INTO timesheet_entries (timesheet_id, work_date) VALUES ($1, '2025-07-01'), ($1, '2025-07-01')`, tsID); err != nil {
+		t.Fatal(err)
+	}
+	indexExists := func() bool {
+		var ok bool
+		if err := conn.QueryRow(`SELECT to_regclass('uq_entries_timesheet_date') IS NOT NULL`).Scan(&ok); err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+
+	// 0013 tercatat walau indeksnya dilewati karena tanggal dobel.
+	if done, err := m.Up(ctx, 1); err != nil || len(done) != 1 || done[0].Version != 13 {
+		t.Fatalf("up 0013 = %+v, %v", done, err)
+	}
+	if indexExists() {
+		t.Fatal("0013 membuat indeks walau ada tanggal dobel")
+	}
+
+	// Jalur API start: 0016 manual, jadi dilewati tanpa error dan dilaporkan tertunda.
+	pending, err := db.Migrate(conn)
+	if err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if len(pending) == 0 || pending[0].Version != 16 || !pending[0].Manual {
+		t.Fatalf("tertunda setelah auto migrate = %+v, want mulai dari 0016", pending)
+	}
+
+	// cmd/migrate up: 0016 gagal, tetap tertunda, dan error-nya menyebut datanya.
+	if _, err := m.Up(ctx, 0); err == nil || !strings.Contains(err.Error(), "dobel") {
+		t.Fatalf("up 0016 dengan data dobel: got %v, want error tanggal dobel", err)
+	}
+	if indexExists() {
+		t.Fatal("indeks dibuat walau migrasi gagal")
+	}
+
+	// Setelah data digabung, 0016 berhasil dan indeksnya ada.
+	if _, err := conn.Exec(`DELETE FROM timesheet_entries WHERE id = (SELECT MAX(id) FROM timesheet_entries)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("up 0016 setelah digabung: %v", err)
+	}
+	if !indexExists() {
+		t.Fatal("uq_entries_timesheet_date belum dibuat")
+	}
+}
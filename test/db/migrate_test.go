@@ -0,0 +1,109 @@
+package db_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"timesheet-api/internal/db"
+)
+
+func TestLoadPairsUpDownFiles(t *testing.T) {
+	src := fstest.MapFS{
+		"0002_add_note.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN note;")},
+		"0001_init.sql":          {Data: []byte("CREATE TABLE t (id INT);")},
+		"0002_add_note.up.sql":   {Data: []byte("ALTER TABLE t ADD COLUMN note TEXT;")},
+		"README.md":              {Data: []byte("bukan migrasi")},
+	}
+	ms, err := db.Load(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 || ms[0].Version != 1 || ms[0].Name != "init" || ms[0].Down != "" ||
+		ms[1].Version != 2 || ms[1].Name != "add_note" || !strings.Contains(ms[1].Down, "DROP COLUMN") {
+		t.Fatalf("migrations = %+v", ms)
+	}
+	if len(ms[0].Checksum) != 64 || ms[0].Checksum == ms[1].Checksum {
+		t.Errorf("checksum = %q / %q", ms[0].Checksum, ms[1].Checksum)
+	}
+}
+
+func TestChecksumCoversDownScript(t *testing.T) {
+	load := func(files fstest.MapFS) db.Migration {
+		t.Helper()
+		ms, err := db.Load(files)
+		if err != nil || len(ms) != 1 {
+			t.Fatalf("load = %+v, %v", ms, err)
+		}
+		return ms[0]
+	}
+	up := []byte("ALTER TABLE t ADD COLUMN note TEXT;")
+	upOnly := load(fstest.MapFS{"0002_add_note.sql": {Data: up}})
+	withDown := load(fstest.MapFS{"0002_add_note.sql": {Data: up}, "0002_add_note.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN note;")}})
+	changedDown := load(fstest.MapFS{"0002_add_note.sql": {Data: up}, "0002_add_note.down.sql": {Data: []byte("SELECT 1;")}})
+
+	sum := sha256.Sum256(up)
+	if upOnly.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("migrasi tanpa down harus tetap sha256 isi up: %s", upOnly.Checksum)
+	}
+	if withDown.Checksum == upOnly.Checksum || withDown.Checksum == changedDown.Checksum {
+		t.Errorf("checksum harus berubah jika file down berubah: %s / %s / %s", upOnly.Checksum, withDown.Checksum, changedDown.Checksum)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	for name, src := range map[string]fstest.MapFS{
+		"down tanpa up": {"0003_x.down.sql": {Data: []byte("SELECT 1;")}},
+		"versi ganda":   {"0003_x.sql": {Data: []byte("SELECT 1;")}, "0003_y.sql": {Data: []byte("SELECT 2;")}},
+		"tanpa versi":   {"init.sql": {Data: []byte("SELECT 1;")}},
+	} {
+		if _, err := db.Load(src); err == nil {
+			t.Errorf("%s: harus error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	ms, err := db.Load(db.Source())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) == 0 || ms[0].Version != 1 {
+		t.Fatalf("migrasi embed = %d file, versi pertama harus 1", len(ms))
+	}
+	for _, m := range ms {
+		if m.Version >= 9 && m.Down == "" {
+			t.Errorf("migrasi %d_%s belum punya file .down.sql", m.Version, m.Name)
+		}
+	}
+	for _, m := range ms {
+		if m.Manual != (m.Version == 16) {
+			t.Errorf("migrasi %d_%s: Manual = %v", m.Version, m.Name, m.Manual)
+		}
+	}
+}
+
+func TestCreateScaffoldsUpDownPair(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 17, 9, 30, 5, 0, time.UTC)
+	paths, err := db.Create(dir, "Add timesheet notes!", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "20261017093005_add_timesheet_notes.up.sql" ||
+		filepath.Base(paths[1]) != "20261017093005_add_timesheet_notes.down.sql" {
+		t.Fatalf("paths = %v", paths)
+	}
+	ms, err := db.Load(os.DirFS(dir))
+	if err != nil || len(ms) != 1 || ms[0].Version != 20261017093005 || ms[0].Down == "" {
+		t.Fatalf("load hasil create = %+v, %v", ms, err)
+	}
+	if _, err := db.Create(dir, "add timesheet notes", now); err == nil {
+		t.Error("create ulang dengan versi yang sama harus gagal")
+	}
+}
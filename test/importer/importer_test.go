@@ -0,0 +1,82 @@
+package importer_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/importer"
+)
+
+func TestReadCSVWithProfile(t *testing.T) {
+	src := "NIK;Tanggal;Masuk;Pulang;Keterangan\n" +
+		"E001;01/07/2025;08.30;17:00;rapat\n" +
+		";;;;\n" +
+		"E002;2025-07-02;08:00;17:00;\n" +
+		"E003;02/07/2025;25:00;17:00;\n"
+	p := importer.Profile{EmployeeCode: "nik", Date: "Tanggal", StartTime: "Masuk", EndTime: "Pulang", Remarks: "Keterangan",
+		DateFormat: "02/01/2006", Delimiter: ";"}
+	rows, err := importer.Read(importer.FormatCSV, strings.NewReader(src), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want 3 (baris kosong dilewati)", len(rows))
+	}
+
+	r := rows[0]
+	if r.Line != 2 || r.EmployeeCode != "E001" || r.Invalid != nil || !r.Entry.WorkDate.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) ||
+		r.Entry.StartTime.Format("15:04") != "08:30" || r.Entry.EndTime.Format("15:04") != "17:00" || r.Entry.Remarks != "rapat" {
+		t.Errorf("row 2 = %+v", r)
+	}
+	if rows[1].Line != 4 || rows[1].Invalid == nil || rows[1].Invalid.Field != "date" {
+		t.Errorf("row 4 harus invalid di date: %+v", rows[1])
+	}
+	if rows[2].Line != 5 || rows[2].Invalid == nil || rows[2].Invalid.Field != "start_time" {
+		t.Errorf("row 5 harus invalid di start_time: %+v", rows[2])
+	}
+}
+
+func TestReadRequiresEmployeeAndDateColumns(t *testing.T) {
+	_, err := importer.Read(importer.FormatCSV, strings.NewReader("nama,jam\nBudi,8\n"), importer.Profile{})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+	_, err = importer.Read(importer.FormatCSV, strings.NewReader("employee_id,tgl\n1,2025-07-01\n"), importer.Profile{})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("tanpa kolom date: err = %v", err)
+	}
+}
+
+func TestReadXLSXSerialDatesAndTimes(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	f.SetSheetRow(sheet, "A1", &[]interface{}{"employee_id", "date", "start_time", "end_time", "total_hours"})
+	f.SetSheetRow(sheet, "A2", &[]interface{}{1, time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC), 0.375, 0.75, nil})
+	f.SetSheetRow(sheet, "A3", &[]interface{}{1, "2025-07-15", "", "", "7,5"})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := importer.Read(importer.FormatOf("absensi.XLSX"), &buf, importer.Profile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d", len(rows))
+	}
+	e := rows[0].Entry
+	if rows[0].Invalid != nil || rows[0].EmployeeID != 1 || e.WorkDate.Format("2006-01-02") != "2025-07-14" ||
+		e.StartTime.Format("15:04") != "09:00" || e.EndTime.Format("15:04") != "18:00" {
+		t.Errorf("row 2 = %+v invalid=%+v", e, rows[0].Invalid)
+	}
+	if e := rows[1].Entry; rows[1].Invalid != nil || e.TotalHours == nil || *e.TotalHours != 7.5 || e.StartTime != nil {
+		t.Errorf("row 3 = %+v", e)
+	}
+}
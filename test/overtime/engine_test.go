@@ -0,0 +1,53 @@
+package overtime_test
+
+import (
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/overtime"
+)
+
+var standard = domain.OvertimePolicy{
+	Name: "Standar", DailyThresholdHours: 8, WeeklyThresholdHours: 40,
+	OvertimeMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 3,
+	BreakMinutes: 60, BreakAfterHours: 6,
+}
+
+func TestCalculate(t *testing.T) {
+	mon := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	sat := time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name                       string
+		day                        overtime.Day
+		regular, daily, weekly, ot float64
+		weighted                   float64
+		dayType                    string
+	}{
+		{"8 jam pas, potong istirahat", overtime.Day{Date: mon, GrossHours: 9, DeductBreak: true}, 8, 0, 0, 0, 0, domain.DayTypeWorkday},
+		{"lembur harian", overtime.Day{Date: mon, GrossHours: 11, DeductBreak: true}, 8, 2, 0, 2, 3, domain.DayTypeWorkday},
+		{"jam manual tidak dipotong", overtime.Day{Date: mon, GrossHours: 9}, 8, 1, 0, 1, 1.5, domain.DayTypeWorkday},
+		{"durasi pendek tanpa istirahat", overtime.Day{Date: mon, GrossHours: 5, DeductBreak: true}, 5, 0, 0, 0, 0, domain.DayTypeWorkday},
+		{"lewat batas mingguan", overtime.Day{Date: mon, GrossHours: 8, WeekRegularHours: 36}, 4, 0, 4, 4, 6, domain.DayTypeWorkday},
+		{"sudah lewat batas mingguan", overtime.Day{Date: mon, GrossHours: 10, WeekRegularHours: 40}, 0, 2, 8, 10, 15, domain.DayTypeWorkday},
+		{"weekend", overtime.Day{Date: sat, GrossHours: 4}, 0, 0, 0, 4, 8, domain.DayTypeWeekend},
+		{"libur menang atas weekend", overtime.Day{Date: sat, GrossHours: 4, Holiday: true}, 0, 0, 0, 4, 12, domain.DayTypeHoliday},
+	}
+	for _, tc := range cases {
+		b := overtime.Calculate(standard, tc.day)
+		if b.RegularHours != tc.regular || b.DailyOvertime != tc.daily || b.WeeklyOvertime != tc.weekly ||
+			b.OvertimeHours != tc.ot || b.WeightedHours != tc.weighted || b.DayType != tc.dayType {
+			t.Errorf("%s: got %+v", tc.name, b)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	for _, d := range []int{7, 9, 13} { // Senin, Rabu, Minggu
+		got := overtime.WeekStart(time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC))
+		if got.Day() != 7 || got.Weekday() != time.Monday {
+			t.Errorf("WeekStart(2025-07-%02d) = %s, want 2025-07-07", d, got.Format("2006-01-02"))
+		}
+	}
+}
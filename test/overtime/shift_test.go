@@ -0,0 +1,41 @@
+package overtime_test
+
+import (
+	"testing"
+	"time"
+
+	"timesheet-api/internal/overtime"
+)
+
+func hm(h, m int) time.Time { return time.Date(0, 1, 1, h, m, 0, 0, time.UTC) }
+
+func TestShiftAndNightHours(t *testing.T) {
+	cases := []struct {
+		name        string
+		start, end  time.Time
+		nextDay     bool
+		hours       float64
+		nightHours  float64
+		nightWindow overtime.Window
+	}{
+		{"shift pagi", hm(8, 0), hm(17, 0), false, 9, 0, overtime.DefaultNightWindow},
+		{"shift malam penuh", hm(22, 0), hm(6, 0), true, 8, 8, overtime.DefaultNightWindow},
+		{"shift sore lewat tengah malam", hm(16, 0), hm(0, 30), true, 8.5, 2.5, overtime.DefaultNightWindow},
+		{"dini hari", hm(4, 0), hm(12, 0), false, 8, 2, overtime.DefaultNightWindow},
+		{"window policy 21:00-05:00", hm(20, 0), hm(4, 0), true, 8, 7, overtime.Window{Start: hm(21, 0), End: hm(5, 0)}},
+		{"window tidak lintas hari", hm(20, 0), hm(4, 0), true, 8, 1, overtime.Window{Start: hm(0, 0), End: hm(1, 0)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := overtime.EndsNextDay(c.start, c.end); got != c.nextDay {
+				t.Errorf("EndsNextDay = %v, want %v", got, c.nextDay)
+			}
+			if got := overtime.ShiftHours(c.start, c.end); got != c.hours {
+				t.Errorf("ShiftHours = %v, want %v", got, c.hours)
+			}
+			if got := overtime.NightHours(c.start, c.end, c.nightWindow); got != c.nightHours {
+				t.Errorf("NightHours = %v, want %v", got, c.nightHours)
+			}
+		})
+	}
+}
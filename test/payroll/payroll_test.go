@@ -0,0 +1,101 @@
+package payroll_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/payroll"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func entry(date string, total, ot float64, holiday string) domain.TimesheetEntry {
+	d, _ := time.Parse("2006-01-02", date)
+	return domain.TimesheetEntry{WorkDate: d, Type: domain.EntryWork, TotalHours: ptr(total), OvertimeHours: ptr(ot), Holiday: holiday}
+}
+
+func TestKepmen102Tiers(t *testing.T) {
+	f5, _ := payroll.Kepmen102(5)
+	f6, _ := payroll.Kepmen102(6)
+	cases := []struct {
+		name  string
+		tiers []payroll.Tier
+		hours float64
+		want  map[float64]float64
+	}{
+		{"hari kerja 1 jam", f5.Workday, 1, map[float64]float64{1.5: 1}},
+		{"hari kerja 3 jam", f5.Workday, 3, map[float64]float64{1.5: 1, 2: 2}},
+		{"istirahat 5 hari 11 jam", f5.RestDay, 11, map[float64]float64{2: 8, 3: 1, 4: 2}},
+		{"istirahat 6 hari 8 jam", f6.RestDay, 8, map[float64]float64{2: 7, 3: 1}},
+	}
+	for _, c := range cases {
+		got := payroll.Split(c.tiers, c.hours)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for m, h := range c.want {
+			if got[m] != h {
+				t.Errorf("%s: %v× = %v, want %v", c.name, m, got[m], h)
+			}
+		}
+	}
+	if _, err := payroll.Kepmen102(4); err == nil {
+		t.Error("work week 4 hari harus ditolak")
+	}
+}
+
+func TestBuildAppliesTiersPerDay(t *testing.T) {
+	f, _ := payroll.Kepmen102(5)
+	ts := &domain.Timesheet{EmployeeID: 1, EmployeeName: "Andi", Month: 8, Year: 2025, Status: domain.StatusApproved,
+		Entries: []domain.TimesheetEntry{
+			entry("2025-08-04", 10, 2, ""),            // Senin: 1×1,5 + 1×2
+			entry("2025-08-05", 9, 1, ""),             // Selasa: 1×1,5
+			entry("2025-08-09", 9, 9, ""),             // Sabtu: 8×2 + 1×3
+			entry("2025-08-18", 4, 4, "Cuti Bersama"), // libur: 4×2
+			{WorkDate: time.Date(2025, 8, 6, 0, 0, 0, 0, time.UTC), Type: domain.EntryLeave},
+		}}
+	r := payroll.Build(ts, &domain.TimesheetStats{WorkedDays: 4, LeaveDays: 1, TotalHours: 32, OvertimeHours: 16}, "EMP-1", f)
+
+	if r.WorkdayOvertimeHours != 3 || r.RestDayHours != 13 {
+		t.Errorf("workday_ot=%v rest=%v", r.WorkdayOvertimeHours, r.RestDayHours)
+	}
+	want := map[string]float64{"1.5": 2, "2": 13, "3": 1, "4": 0}
+	for k, h := range want {
+		if r.OvertimeByMultiplier[k] != h {
+			t.Errorf("%s× = %v, want %v", k, r.OvertimeByMultiplier[k], h)
+		}
+	}
+	// 2×1,5 + 13×2 + 1×3
+	if r.OvertimeUnits != 32 {
+		t.Errorf("units = %v, want 32", r.OvertimeUnits)
+	}
+}
+
+func TestColumnMapping(t *testing.T) {
+	f, _ := payroll.Kepmen102(5)
+	cols, err := payroll.ParseColumns("NIK:employee_code, Nama:employee_name, Lembur 1.5x:ot_1_5x, overtime_units", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := []domain.PayrollRow{{EmployeeCode: "EMP-1", EmployeeName: "Andi", OvertimeByMultiplier: map[string]float64{"1.5": 2}, OvertimeUnits: 7.5}}
+
+	var b bytes.Buffer
+	if err := payroll.WriteCSV(&b, rows, cols); err != nil {
+		t.Fatal(err)
+	}
+	want := "NIK,Nama,Lembur 1.5x,overtime_units\nEMP-1,Andi,2.00,7.50\n"
+	if b.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", b.String(), want)
+	}
+	if rec := payroll.Records(rows, cols)[0]; rec["NIK"] != "EMP-1" || rec["Lembur 1.5x"] != 2.0 {
+		t.Errorf("json record = %v", rec)
+	}
+
+	if _, err := payroll.ParseColumns("Gaji:salary", f); err == nil || !strings.Contains(err.Error(), "salary") {
+		t.Errorf("field tidak dikenal: err = %v", err)
+	}
+}
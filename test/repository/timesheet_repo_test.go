@@ -0,0 +1,426 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	appdb "timesheet-api/internal/db"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/repository/postgres"
+)
+
+// Suite yang sama dijalankan untuk TimesheetRepoPG (database/sql) dan
+// TimesheetRepoPGX (pgxpool). Butuh database Postgres khusus test di
+// TEST_DB_DSN; tanpa itu dilewati.
+func testDSN(t *testing.T) string {
+	dsn := os.Getenv("TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DB_DSN kosong, test repository Postgres dilewati")
+	}
+	return dsn
+}
+
+func openTestDB(t *testing.T, dsn string) *sql.DB {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := appdb.NewMigrator(db, appdb.Source())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTimesheetRepoPG(t *testing.T) {
+	db := openTestDB(t, testDSN(t))
+	runTimesheetRepoSuite(t, db, postgres.NewTimesheetRepoPG(db), postgres.NewTxManagerPG(db))
+}
+
+func TestTimesheetRepoPGX(t *testing.T) {
+	dsn := testDSN(t)
+	db := openTestDB(t, dsn)
+	for _, cache := range []int{0, 64} {
+		t.Run(fmt.Sprintf("statement_cache=%d", cache), func(t *testing.T) {
+			pool, err := postgres.OpenPool(context.Background(), dsn, postgres.PoolConfig{MaxConns: 4, StatementCache: cache})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pool.Close()
+			runTimesheetRepoSuite(t, db, postgres.NewTimesheetRepoPGX(pool), postgres.NewTxManagerPGX(pool))
+		})
+	}
+}
+
+func runTimesheetRepoSuite(t *testing.T, db *sql.DB, repo repository.TimesheetRepository, txm repository.TxManager) {
+	ctx := context.Background()
+	emps := postgres.NewEmployeeRepoPG(db)
+	// newEmployee: karyawan baru per subtest, jadi subtest tidak saling bertabrakan
+	// di unique (employee_id, month, year) walau database dipakai ulang.
+	newEmployee := func(t *testing.T) int64 {
+		id, err := emps.Create(&domain.Employee{Name: fmt.Sprintf("%s %d", t.Name(), time.Now().UnixNano()), Active: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	newSheet := func(t *testing.T, emp int64, month int) int64 {
+		id, err := repo.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: month, Year: 2031}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	clock := func(s string) time.Time {
+		c, _ := time.Parse("15:04", s)
+		return c
+	}
+	hours := func(h float64) *float64 { return &h }
+	event := func(tsID int64) *domain.AuditEvent {
+		return &domain.AuditEvent{TimesheetID: tsID, Entity: domain.AuditEntityEntry, Action: domain.AuditCreate, Actor: "test",
+			After: json.RawMessage(`{"ok":true}`)}
+	}
+
+	t.Run("timesheet CRUD", func(t *testing.T) {
+		emp := newEmployee(t)
+		ev := &domain.AuditEvent{Entity: domain.AuditEntityTimesheet, Action: domain.AuditCreate, Actor: "test"}
+		ts := &domain.Timesheet{EmployeeID: emp, Month: 1, Year: 2031}
+		id, err := repo.Create(ctx, ts, ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id == 0 || ts.Status != domain.StatusDraft || ts.CreatedAt.IsZero() || ev.EntityID != id || ev.ID == 0 {
+			t.Fatalf("create: id=%d ts=%+v ev=%+v", id, ts, ev)
+		}
+		if _, err := repo.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: 1, Year: 2031}, nil); !errors.Is(err, domain.ErrDuplicate) {
+			t.Errorf("periode dobel: got %v, want ErrDuplicate", err)
+		}
+
+		ts.Month = 2
+		if err := repo.Update(ctx, ts, nil); err != nil {
+			t.Fatal(err)
+		}
+		got, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Month != 2 || got.EmployeeID != emp || got.EmployeeName == "" {
+			t.Errorf("find = %+v", got)
+		}
+
+		if err := repo.Delete(ctx, id, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FindByID(ctx, id); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("find setelah delete: got %v", err)
+		}
+		if err := repo.Delete(ctx, id, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("delete ulang: got %v", err)
+		}
+		if err := repo.Update(ctx, ts, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("update setelah delete: got %v", err)
+		}
+	})
+
+	t.Run("entries with segments", func(t *testing.T) {
+		tsID := newSheet(t, newEmployee(t), 3)
+		st, et := clock("08:00"), clock("17:00")
+		e := &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: time.Date(2031, 3, 4, 0, 0, 0, 0, time.UTC), Type: domain.EntryWork,
+			StartTime: &st, EndTime: &et, TotalHours: hours(8), Remarks: "pagi",
+			Overtime: &domain.OvertimeBreakdown{GrossHours: 8, RegularHours: 8},
+			Segments: []domain.EntrySegment{
+				{Type: domain.SegmentWork, StartTime: clock("08:00"), EndTime: clock("12:00")},
+				{Type: domain.SegmentBreak, StartTime: clock("12:00"), EndTime: clock("13:00"), Remarks: "makan"},
+				{Type: domain.SegmentWork, StartTime: clock("13:00"), EndTime: clock("17:00")},
+			}}
+		id, err := repo.AddEntry(ctx, e, event(tsID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := repo.FindEntryByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.StartTime == nil || got.StartTime.Format("15:04") != "08:00" || got.Overtime == nil || got.Overtime.RegularHours != 8 ||
+			len(got.Segments) != 3 || got.Segments[1].Remarks != "makan" || got.Segments[2].StartTime.Format("15:04") != "13:00" {
+			t.Fatalf("entry = %+v", got)
+		}
+
+		dup := &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: e.WorkDate, Type: domain.EntryWork}
+		if _, err := repo.AddEntry(ctx, dup, nil); !errors.Is(err, domain.ErrDuplicate) {
+			t.Errorf("tanggal dobel: got %v, want ErrDuplicate", err)
+		}
+
+		e.Remarks, e.Segments = "koreksi", e.Segments[:1]
+		if err := repo.UpdateEntry(ctx, e, nil); err != nil {
+			t.Fatal(err)
+		}
+		got, _ = repo.FindEntryByID(ctx, id)
+		if got.Remarks != "koreksi" || len(got.Segments) != 1 {
+			t.Errorf("update = %+v", got)
+		}
+
+		if err := repo.DeleteEntry(ctx, id, nil); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FindEntryByID(ctx, id); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("find setelah delete: got %v", err)
+		}
+		if err := repo.DeleteEntry(ctx, id, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("delete ulang: got %v", err)
+		}
+	})
+
+	t.Run("AddEntries", func(t *testing.T) {
+		tsID := newSheet(t, newEmployee(t), 4)
+		day := func(d int) time.Time { return time.Date(2031, 4, d, 0, 0, 0, 0, time.UTC) }
+		st, et := clock("22:00"), clock("06:00")
+		items := []repository.NewEntry{
+			{Entry: &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: day(1), Type: domain.EntryWork, TotalHours: hours(8)}, Audit: event(tsID)},
+			{Entry: &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: day(2), Type: domain.EntryWork, StartTime: &st, EndTime: &et,
+				EndsNextDay: true, TotalHours: hours(8), FlagReason: "cek", Flagged: true,
+				Segments: []domain.EntrySegment{{Type: domain.SegmentWork, StartTime: st, EndTime: clock("02:00")},
+					{Type: domain.SegmentWork, StartTime: clock("02:00"), EndTime: et, StartsNextDay: true}}}, Audit: event(tsID)},
+			{Entry: &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: day(3), Type: domain.EntryAbsent}},
+		}
+		if err := repo.AddEntries(ctx, items); err != nil {
+			t.Fatal(err)
+		}
+		for i, it := range items {
+			if it.Entry.ID == 0 || it.Entry.CreatedAt.IsZero() || (it.Audit != nil && (it.Audit.ID == 0 || it.Audit.EntityID != it.Entry.ID)) {
+				t.Errorf("items[%d]: entry=%+v audit=%+v", i, it.Entry, it.Audit)
+			}
+		}
+		ts, err := repo.FindByID(ctx, tsID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ts.Entries) != 3 {
+			t.Fatalf("entries = %d, want 3", len(ts.Entries))
+		}
+		night := ts.Entries[1]
+		if night.ID != items[1].Entry.ID || night.FlagReason != "cek" || !night.EndsNextDay || night.EndTime.Format("15:04") != "06:00" ||
+			len(night.Segments) != 2 || !night.Segments[1].StartsNextDay || night.Segments[1].ID != items[1].Entry.Segments[1].ID {
+			t.Errorf("entry malam = %+v", night)
+		}
+		if ts.Entries[0].Remarks != "" || ts.Entries[2].Type != domain.EntryAbsent {
+			t.Errorf("entries = %+v", ts.Entries)
+		}
+		hist, err := repo.History(ctx, tsID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hist) != 2 || hist[0].EntityID != items[0].Entry.ID || string(hist[1].After) != `{"ok": true}` || hist[0].RequestID != "" {
+			t.Errorf("history = %+v", hist)
+		}
+
+		// Satu baris gagal → tidak ada yang tertulis.
+		bad := []repository.NewEntry{
+			{Entry: &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: day(10), Type: domain.EntryWork}, Audit: event(tsID)},
+			{Entry: &domain.TimesheetEntry{TimesheetID: tsID, WorkDate: day(1), Type: domain.EntryWork}, Audit: event(tsID)},
+		}
+		if err := repo.AddEntries(ctx, bad); !errors.Is(err, domain.ErrDuplicate) {
+			t.Fatalf("batch dengan tanggal dobel: got %v, want ErrDuplicate", err)
+		}
+		ts, _ = repo.FindByID(ctx, tsID)
+		hist, _ = repo.History(ctx, tsID)
+		if len(ts.Entries) != 3 || len(hist) != 2 {
+			t.Errorf("setelah batch gagal: %d entries, %d events", len(ts.Entries), len(hist))
+		}
+	})
+
+	t.Run("List, Count, Stream", func(t *testing.T) {
+		emp := newEmployee(t)
+		for m := 1; m <= 3; m++ {
+			id := newSheet(t, emp, m)
+			if _, err := repo.AddEntry(ctx, &domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2031, time.Month(m), 6, 0, 0, 0, 0, time.UTC),
+				Type: domain.EntryWork, TotalHours: hours(8)}, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		f := repository.Filter{EmployeeID: &emp}
+		months := func(items []domain.Timesheet) (out []int) {
+			for _, ts := range items {
+				out = append(out, ts.Month)
+			}
+			return out
+		}
+
+		all, err := repo.List(ctx, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(months(all)) != "[3 2 1]" {
+			t.Errorf("list = %v, want [3 2 1]", months(all))
+		}
+		if n, err := repo.Count(ctx, f); err != nil || n != 3 {
+			t.Errorf("count = %d, %v", n, err)
+		}
+
+		page := f
+		page.PageSize, page.Page = 2, 2
+		if got, _ := repo.List(ctx, page); fmt.Sprint(months(got)) != "[1]" {
+			t.Errorf("halaman 2 = %v, want [1]", months(got))
+		}
+		page = f
+		page.PageSize, page.Cursor = 2, &repository.Cursor{Year: all[0].Year, Month: all[0].Month, ID: all[0].ID}
+		if got, _ := repo.List(ctx, page); fmt.Sprint(months(got)) != "[2 1]" {
+			t.Errorf("setelah cursor = %v, want [2 1]", months(got))
+		}
+		page = f
+		page.Sort = []repository.SortField{{Field: "month"}}
+		if got, _ := repo.List(ctx, page); fmt.Sprint(months(got)) != "[1 2 3]" {
+			t.Errorf("sort month = %v, want [1 2 3]", months(got))
+		}
+
+		var streamed []domain.Timesheet
+		err = repo.Stream(ctx, f, true, func(ts *domain.Timesheet) error {
+			streamed = append(streamed, *ts)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(months(streamed)) != "[3 2 1]" || len(streamed[0].Entries) != 1 || streamed[0].Entries[0].TimesheetID != streamed[0].ID {
+			t.Errorf("stream = %+v", streamed)
+		}
+		stop := errors.New("stop")
+		if err := repo.Stream(ctx, f, false, func(*domain.Timesheet) error { return stop }); err != stop {
+			t.Errorf("error dari fn: got %v", err)
+		}
+	})
+
+	t.Run("ChangeStatus", func(t *testing.T) {
+		id := newSheet(t, newEmployee(t), 5)
+		ch := &domain.StatusChange{TimesheetID: id, FromStatus: domain.StatusDraft, ToStatus: domain.StatusSubmitted, Actor: "test"}
+		if err := repo.ChangeStatus(ctx, ch, nil); err != nil {
+			t.Fatal(err)
+		}
+		again := *ch
+		if err := repo.ChangeStatus(ctx, &again, nil); !errors.Is(err, domain.ErrInvalidTransition) {
+			t.Errorf("status sudah berubah: got %v, want ErrInvalidTransition", err)
+		}
+		missing := *ch
+		missing.TimesheetID = -1
+		if err := repo.ChangeStatus(ctx, &missing, nil); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("timesheet tidak ada: got %v, want ErrNotFound", err)
+		}
+		hist, err := repo.StatusHistory(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hist) != 1 || hist[0].ID != ch.ID || hist[0].ToStatus != domain.StatusSubmitted || hist[0].Reason != "" {
+			t.Errorf("status history = %+v", hist)
+		}
+		if ts, _ := repo.FindByID(ctx, id); ts.Status != domain.StatusSubmitted || ts.StatusChangedBy == nil {
+			t.Errorf("timesheet = %+v", ts)
+		}
+	})
+
+	t.Run("WeekRegularHours and Stats", func(t *testing.T) {
+		emp := newEmployee(t)
+		id := newSheet(t, emp, 6)
+		// Senin 2–Rabu 4 Juni 2031; entry tanpa breakdown: reguler = total - lembur.
+		var ids []int64
+		for d := 2; d <= 3; d++ {
+			eid, err := repo.AddEntry(ctx, &domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2031, 6, d, 0, 0, 0, 0, time.UTC),
+				Type: domain.EntryWork, TotalHours: hours(9), OvertimeHours: hours(1)}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, eid)
+		}
+		from, before := time.Date(2031, 6, 2, 0, 0, 0, 0, time.UTC), time.Date(2031, 6, 4, 0, 0, 0, 0, time.UTC)
+		if h, err := repo.WeekRegularHours(ctx, emp, from, before, 0); err != nil || h != 16 {
+			t.Errorf("week = %v, %v; want 16", h, err)
+		}
+		if h, _ := repo.WeekRegularHours(ctx, emp, from, before, ids[0]); h != 8 {
+			t.Errorf("week tanpa entry pertama = %v, want 8", h)
+		}
+
+		st, err := repo.Stats(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.DaysFilled != 2 || st.WorkedDays != 2 || st.TotalHours != 18 || st.OvertimeHours != 2 {
+			t.Errorf("stats = %+v", st)
+		}
+		if _, err := repo.Stats(ctx, -1); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("stats timesheet tidak ada: got %v", err)
+		}
+	})
+
+	t.Run("TxManager", func(t *testing.T) {
+		emp := newEmployee(t)
+		fail := errors.New("gagal")
+		var rolledBack int64
+		err := txm.WithinTx(ctx, func(ctx context.Context, tx repository.TxRepos) error {
+			id, err := tx.Timesheets.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: 7, Year: 2031}, nil)
+			rolledBack = id
+			if err != nil {
+				return err
+			}
+			return fail
+		})
+		if err != fail {
+			t.Fatalf("WithinTx: got %v", err)
+		}
+		if _, err := repo.FindByID(ctx, rolledBack); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("setelah rollback: got %v", err)
+		}
+
+		var outer, inner int64
+		err = txm.WithinTx(ctx, func(ctx context.Context, tx repository.TxRepos) error {
+			var err error
+			if outer, err = tx.Timesheets.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: 8, Year: 2031}, nil); err != nil {
+				return err
+			}
+			// Error repository di dalam transaksi hanya membatalkan savepoint-nya.
+			if _, err := tx.Timesheets.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: 8, Year: 2031}, nil); !errors.Is(err, domain.ErrDuplicate) {
+				t.Errorf("duplikat di dalam tx: got %v", err)
+			}
+			err = txm.WithinTx(ctx, func(ctx context.Context, tx repository.TxRepos) error {
+				inner, _ = tx.Timesheets.Create(ctx, &domain.Timesheet{EmployeeID: emp, Month: 9, Year: 2031}, nil)
+				return fail
+			})
+			if err != fail {
+				t.Errorf("WithinTx bersarang: got %v", err)
+			}
+			return tx.Timesheets.AddEntries(ctx, []repository.NewEntry{{Entry: &domain.TimesheetEntry{TimesheetID: outer,
+				WorkDate: time.Date(2031, 8, 1, 0, 0, 0, 0, time.UTC), Type: domain.EntryWork}}})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts, err := repo.FindByID(ctx, outer); err != nil || len(ts.Entries) != 1 {
+			t.Errorf("transaksi luar harus commit: %+v, %v", ts, err)
+		}
+		if _, err := repo.FindByID(ctx, inner); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("savepoint dalam harus rollback: got %v", err)
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+		defer cancel()
+		if _, err := repo.FindByID(expired, 1); !errors.Is(err, domain.ErrTimeout) {
+			t.Errorf("FindByID: got %v, want ErrTimeout", err)
+		}
+		if _, err := repo.Create(expired, &domain.Timesheet{EmployeeID: 1, Month: 1, Year: 2031}, nil); !errors.Is(err, domain.ErrTimeout) {
+			t.Errorf("Create: got %v, want ErrTimeout", err)
+		}
+	})
+}
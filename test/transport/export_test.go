@@ -0,0 +1,84 @@
+package transport_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	transport "timesheet-api/internal/transport/http"
+	"timesheet-api/internal/usecase"
+)
+
+// exportRepo hanya menyediakan Stream; FindByID per timesheet (N+1) akan panic
+// lewat interface embedded yang nil.
+type exportRepo struct {
+	repository.TimesheetRepository
+	items []domain.Timesheet
+}
+
+func (r exportRepo) Stream(_ context.Context, _ repository.Filter, withEntries bool, fn func(*domain.Timesheet) error) error {
+	for i := range r.items {
+		ts := r.items[i]
+		if !withEntries {
+			ts.Entries = nil
+		}
+		if err := fn(&ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportZIPHasOnePDFPerEmployee(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	repo := exportRepo{items: []domain.Timesheet{
+		{ID: 1, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 7},
+		{ID: 2, EmployeeID: 9, EmployeeName: "Sari", Year: 2025, Month: 7},
+		{ID: 3, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 6},
+	}}
+	svc := usecase.NewTimesheetService(repo, nil, nil, nil, nil, nil)
+	transport.NewTimesheetHandler(svc).Register(r)
+
+	ctx := auth.WithPrincipal(context.Background(), &domain.Principal{UserID: 1, Username: "hr", Role: domain.RoleHRAdmin})
+	req := httptest.NewRequest(http.MethodGet, "/timesheets/export.zip", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d (%s)", w.Code, w.Body.String())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	pages := map[string]int{}
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		b.ReadFrom(rc)
+		rc.Close()
+		pages[f.Name] = bytes.Count(b.Bytes(), []byte("/Type /Page\n"))
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "timesheet_budi_santoso_7.pdf" || names[1] != "timesheet_sari_9.pdf" {
+		t.Fatalf("isi ZIP = %v, want satu PDF per karyawan", names)
+	}
+	if pages["timesheet_budi_santoso_7.pdf"] != 2 || pages["timesheet_sari_9.pdf"] != 1 {
+		t.Errorf("halaman per PDF = %v, want semua timesheet karyawan di PDF-nya", pages)
+	}
+}
@@ -0,0 +1,72 @@
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	transport "timesheet-api/internal/transport/http"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	for _, rt := range []transport.Routes{
+		transport.NewTimesheetHandler(nil),
+		transport.NewEmployeeHandler(nil),
+		transport.NewDepartmentHandler(nil),
+		transport.NewOvertimePolicyHandler(nil),
+		transport.NewHolidayHandler(nil),
+		transport.NewLeaveHandler(nil),
+		transport.NewPunchHandler(nil),
+		transport.NewProjectHandler(nil),
+		transport.NewBillingHandler(nil),
+		transport.NewPayrollHandler(nil),
+		transport.NewImportHandler(nil),
+		transport.NewAuthHandler(nil),
+	} {
+		rt.Register(r)
+	}
+
+	want := map[string]bool{
+		"GET /timesheets/:id/export.pdf":            false,
+		"GET /timesheets/export.pdf":                false,
+		"GET /timesheets/export.zip":                false,
+		"GET /timesheets/:id/export.xlsx":           false,
+		"GET /timesheets/export.xlsx":               false,
+		"POST /timesheets/:id/submit":               false,
+		"POST /timesheets/:id/reject":               false,
+		"GET /timesheets/:id/history":               false,
+		"GET /employees/:id":                        false,
+		"GET /departments/:id/subtree":              false,
+		"PUT /overtime-policies/:id":                false,
+		"POST /holidays/import":                     false,
+		"GET /holidays/working-days":                false,
+		"POST /timesheets/:id/recalculate-overtime": false,
+		"POST /leave-requests/:id/approve":          false,
+		"GET /leave-balances":                       false,
+		"POST /punch/in":                            false,
+		"POST /punch/out":                           false,
+		"POST /punch/auto-close":                    false,
+		"POST /projects/:id/tasks":                  false,
+		"PUT /tasks/:id":                            false,
+		"GET /reports/projects/:id/hours":           false,
+		"PUT /rate-cards/:id":                       false,
+		"GET /invoices/preview":                     false,
+		"GET /payroll/export":                       false,
+		"POST /timesheets/import":                   false,
+		"POST /auth/login":                          false,
+		"POST /auth/refresh":                        false,
+	}
+	for _, ri := range r.Routes() {
+		k := ri.Method + " " + ri.Path
+		if _, ok := want[k]; ok {
+			want[k] = true
+		}
+	}
+	for k, found := range want {
+		if !found {
+			t.Errorf("route %s tidak terdaftar", k)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package transport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	transport "timesheet-api/internal/transport/http"
+	"timesheet-api/internal/usecase"
+)
+
+// listRepo: exportRepo ditambah List/Count untuk jawaban JSON per halaman.
+type listRepo struct{ exportRepo }
+
+func (r listRepo) List(context.Context, repository.Filter) ([]domain.Timesheet, error) {
+	out := make([]domain.Timesheet, len(r.items))
+	for i, ts := range r.items {
+		ts.Entries = nil
+		out[i] = ts
+	}
+	return out, nil
+}
+
+func (r listRepo) Count(context.Context, repository.Filter) (int64, error) {
+	return int64(len(r.items)), nil
+}
+
+func listTimesheets(t *testing.T, accept, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	start := time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC)
+	hours := 8.0
+	repo := listRepo{exportRepo{items: []domain.Timesheet{
+		{ID: 1, EmployeeID: 7, EmployeeName: "Budi", Year: 2025, Month: 7, Status: domain.StatusDraft,
+			Entries: []domain.TimesheetEntry{{ID: 10, TimesheetID: 1, WorkDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				Type: domain.EntryWork, StartTime: &start, TotalHours: &hours, Remarks: "kantor"}}},
+		{ID: 2, EmployeeID: 9, EmployeeName: "Sari", Year: 2025, Month: 7, Status: domain.StatusDraft},
+	}}}
+	svc := usecase.NewTimesheetService(repo, nil, nil, nil, nil, nil)
+	transport.NewTimesheetHandler(svc).Register(r)
+
+	ctx := auth.WithPrincipal(context.Background(), &domain.Principal{UserID: 1, Username: "hr", Role: domain.RoleHRAdmin})
+	req := httptest.NewRequest(http.MethodGet, "/timesheets"+query, nil).WithContext(ctx)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Accept %q: status %d (%s)", accept, w.Code, w.Body.String())
+	}
+	return w
+}
+
+func TestListTimesheetsJSONForUnmatchedAccept(t *testing.T) {
+	for _, accept := range []string{"", "application/json", "application/xml", "text/html", "*/*"} {
+		w := listTimesheets(t, accept, "")
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("Accept %q: Content-Type = %q, want JSON", accept, ct)
+		}
+		var body struct {
+			Data []domain.Timesheet `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Data) != 2 {
+			t.Errorf("Accept %q: body = %s (%v)", accept, w.Body.String(), err)
+		}
+	}
+}
+
+func TestListTimesheetsCSV(t *testing.T) {
+	w := listTimesheets(t, "text/csv", "?year=2025")
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="timesheets_2025.csv"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != 9 || rows[0][0] != "timesheet_id" || rows[1][0] != "1" || rows[2][2] != "Sari" {
+		t.Fatalf("rows = %q", rows)
+	}
+}
+
+func TestListTimesheetsCSVWithEntries(t *testing.T) {
+	w := listTimesheets(t, "text/csv", "?include=entries")
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != 22 || rows[0][9] != "entry_id" {
+		t.Fatalf("rows = %q", rows)
+	}
+	entry := rows[1]
+	if entry[0] != "1" || entry[9] != "10" || entry[10] != "2025-07-01" || entry[12] != "08:00:00" || entry[15] != "8" || entry[21] != "kantor" {
+		t.Errorf("baris entry = %q", entry)
+	}
+	// Timesheet tanpa entry tetap satu baris, kolom entry kosong.
+	empty := rows[2]
+	if empty[0] != "2" {
+		t.Errorf("baris timesheet tanpa entry = %q", empty)
+	}
+	for i, v := range empty[9:] {
+		if v != "" {
+			t.Errorf("kolom entry %s harus kosong, got %q", rows[0][9+i], v)
+		}
+	}
+}
+
+func TestListTimesheetsNDJSON(t *testing.T) {
+	for _, query := range []string{"", "?include=entries"} {
+		w := listTimesheets(t, "application/x-ndjson", query)
+		if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson; charset=utf-8" {
+			t.Errorf("%q: Content-Type = %q", query, ct)
+		}
+		if cd := w.Header().Get("Content-Disposition"); cd != "" {
+			t.Errorf("%q: Content-Disposition = %q, want kosong", query, cd)
+		}
+		var lines []map[string]interface{}
+		sc := bufio.NewScanner(w.Body)
+		for sc.Scan() {
+			var line map[string]interface{}
+			if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+				t.Fatalf("%q: baris %q: %v", query, sc.Text(), err)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) != 2 || lines[0]["id"] != float64(1) || lines[1]["id"] != float64(2) {
+			t.Fatalf("%q: lines = %v", query, lines)
+		}
+		entries, _ := lines[0]["entries"].([]interface{})
+		if query == "" && entries != nil {
+			t.Errorf("tanpa include=entries, entries = %v", entries)
+		}
+		if query != "" {
+			if len(entries) != 1 || entries[0].(map[string]interface{})["date"] != "2025-07-01" {
+				t.Errorf("entries = %v", lines[0]["entries"])
+			}
+			if _, ok := lines[1]["entries"]; ok {
+				t.Errorf("timesheet tanpa entry: entries = %v", lines[1]["entries"])
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	transport "timesheet-api/internal/transport/http"
+	"timesheet-api/internal/usecase"
+)
+
+// slowRepo menunggu sampai ctx query habis, seperti query yang lambat.
+type slowRepo struct{ repository.TimesheetRepository }
+
+func (slowRepo) FindByID(ctx context.Context, id int64) (*domain.Timesheet, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %v", domain.ErrTimeout, ctx.Err())
+	case <-time.After(2 * time.Second):
+		return nil, errors.New("ctx request tidak sampai ke repository")
+	}
+}
+
+func TestQueryTimeoutIs504(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := usecase.NewTimesheetService(slowRepo{}, nil, nil, nil, nil, nil)
+	transport.NewTimesheetHandler(svc).Register(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/timesheets/1", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status: got %d, want 504 (%s)", w.Code, w.Body.String())
+	}
+}
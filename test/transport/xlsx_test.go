@@ -0,0 +1,144 @@
+package transport_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	transport "timesheet-api/internal/transport/http"
+	"timesheet-api/internal/usecase"
+)
+
+func exportXLSX(t *testing.T, items []domain.Timesheet) *excelize.File {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := usecase.NewTimesheetService(exportRepo{items: items}, nil, nil, nil, nil, nil)
+	transport.NewTimesheetHandler(svc).Register(r)
+
+	ctx := auth.WithPrincipal(context.Background(), &domain.Principal{UserID: 1, Username: "hr", Role: domain.RoleHRAdmin})
+	req := httptest.NewRequest(http.MethodGet, "/timesheets/export.xlsx", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status: got %d (%s)", w.Code, w.Body.String())
+	}
+	f, err := excelize.OpenReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func cellFill(t *testing.T, f *excelize.File, sheet, cell string) []string {
+	t.Helper()
+	id, err := f.GetCellStyle(sheet, cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := f.GetStyle(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st.Fill.Color
+}
+
+func TestExportXLSXSheetNames(t *testing.T) {
+	long := "Raden Mas Bagus/Setiawan Wicaksono Putra"
+	f := exportXLSX(t, []domain.Timesheet{
+		{ID: 1, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 7},
+		{ID: 2, EmployeeID: 8, EmployeeName: "Budi Santoso", Year: 2025, Month: 7},
+		{ID: 3, EmployeeID: 9, EmployeeName: long, Year: 2025, Month: 7},
+		{ID: 4, EmployeeID: 10, EmployeeName: long, Year: 2025, Month: 7},
+		{ID: 5, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 8},
+	})
+	want := []string{"Budi Santoso", "Budi Santoso (2)", "Raden Mas Bagus Setiawan Wicaks", "Raden Mas Bagus Setiawan Wi (2)"}
+	got := f.GetSheetList()
+	if len(got) != len(want) {
+		t.Fatalf("sheet = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sheet %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	// Timesheet kedua karyawan 7 masuk ke sheet yang sama, di bawah yang pertama.
+	if v, _ := f.GetCellValue("Budi Santoso", "D15"); v != ": Agustus 2025" {
+		t.Errorf("periode timesheet kedua = %q", v)
+	}
+}
+
+func TestExportXLSXTotalsAndHolidayRows(t *testing.T) {
+	clock := func(h int) *time.Time { v := time.Date(0, 1, 1, h, 0, 0, 0, time.UTC); return &v }
+	hours := func(v float64) *float64 { return &v }
+	f := exportXLSX(t, []domain.Timesheet{
+		{ID: 1, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 8, Entries: []domain.TimesheetEntry{
+			{WorkDate: time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), Type: domain.EntryWork, StartTime: clock(7), EndTime: clock(10),
+				TotalHours: hours(3), Holiday: "Hari Kemerdekaan", Remarks: "upacara"},
+			{WorkDate: time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), Type: domain.EntryWork, StartTime: clock(22), EndTime: clock(6),
+				EndsNextDay: true, TotalHours: hours(8), OvertimeHours: hours(1)},
+		}},
+		{ID: 2, EmployeeID: 7, EmployeeName: "Budi Santoso", Year: 2025, Month: 9},
+	})
+	const sheet = "Budi Santoso"
+	get := func(cell string) string {
+		v, err := f.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	// Judul baris 1, info baris 3-5, header baris 7, entry baris 8-9, TOTAL baris 10.
+	if v := get("A7"); v != "Tanggal / Date" {
+		t.Errorf("header A7 = %q", v)
+	}
+	if v := get("A10"); v != "TOTAL" {
+		t.Fatalf("A10 = %q, want TOTAL", v)
+	}
+	for _, col := range []string{"E", "F"} {
+		if got, _ := f.GetCellFormula(sheet, col+"10"); got != "SUM("+col+"8:"+col+"9)" {
+			t.Errorf("formula %s10 = %q", col, got)
+		}
+	}
+
+	// Hari libur: keterangan diawali nama liburnya dan barisnya diwarnai.
+	if v := get("G8"); v != "Libur: Hari Kemerdekaan. upacara" {
+		t.Errorf("keterangan libur = %q", v)
+	}
+	for _, cell := range []string{"A8", "E8", "G8"} {
+		if fill := cellFill(t, f, sheet, cell); len(fill) != 1 || fill[0] != "FDE2E2" {
+			t.Errorf("fill %s = %v, want FDE2E2", cell, fill)
+		}
+	}
+	if fill := cellFill(t, f, sheet, "A9"); len(fill) != 0 {
+		t.Errorf("fill A9 (bukan libur) = %v", fill)
+	}
+
+	// Shift lewat tengah malam: Selesai disimpan +1 hari.
+	if end, err := strconv.ParseFloat(get("D9"), 64); err != nil || end < 1.24 || end > 1.26 {
+		t.Errorf("D9 = %q, want 1+06:00", get("D9"))
+	}
+
+	// Timesheet tanpa entry: header baris 19, TOTAL langsung di baris 20 berisi 0.
+	if v := get("A20"); v != "TOTAL" {
+		t.Fatalf("A20 = %q, want TOTAL", v)
+	}
+	for _, col := range []string{"E", "F"} {
+		if got, _ := f.GetCellFormula(sheet, col+"20"); got != "" {
+			t.Errorf("formula %s20 = %q, want kosong", col, got)
+		}
+		if v := get(col + "20"); v != "0" {
+			t.Errorf("%s20 = %q, want 0", col, v)
+		}
+	}
+}
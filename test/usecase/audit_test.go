@@ -0,0 +1,48 @@
+package usecase_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/audit"
+	"timesheet-api/internal/domain"
+)
+
+func TestEntryChangesAreAudited(t *testing.T) {
+	repo, svc := newWorkflowService()
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := audit.WithRequestID(as(domain.RoleEmployee, ownerID), "req-42")
+
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), TotalHours: ptr(8.0), Remarks: "WFO"}
+	entryID, err := svc.AddEntry(ctx, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdateEntry(ctx, &domain.TimesheetEntry{ID: entryID, TotalHours: ptr(6.5), Remarks: "WFO"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.events) != 2 {
+		t.Fatalf("got %d audit event, want 2", len(repo.events))
+	}
+	created, updated := repo.events[0], repo.events[1]
+	if created.Action != domain.AuditCreate || created.EntityID != entryID || created.Before != nil {
+		t.Errorf("create event = %+v", created)
+	}
+	if updated.Action != domain.AuditUpdate || updated.Actor != "employee" || updated.RequestID != "req-42" || updated.TimesheetID != id {
+		t.Errorf("update event = %+v", updated)
+	}
+
+	// Hanya field yang berubah yang masuk diff.
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(updated.Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(updated.After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != 1 || after["total_hours"] != 6.5 || before["total_hours"] != 8.0 {
+		t.Errorf("diff = %v → %v, want hanya total_hours 8 → 6.5", before, after)
+	}
+}
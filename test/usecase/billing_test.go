@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/usecase"
+)
+
+type fakeBillingRepo struct {
+	repository.BillingRepository
+	client *domain.Client
+	rates  []domain.RateCard
+	items  []domain.BillableItem
+}
+
+func (f *fakeBillingRepo) FindClient(id int64) (*domain.Client, error) {
+	if f.client == nil || f.client.ID != id {
+		return nil, domain.ErrNotFound
+	}
+	return f.client, nil
+}
+
+func (f *fakeBillingRepo) RatesFor(clientID int64, from, to time.Time) ([]domain.RateCard, error) {
+	return f.rates, nil
+}
+
+func (f *fakeBillingRepo) BillableItems(clientID int64, from, to time.Time) ([]domain.BillableItem, error) {
+	return f.items, nil
+}
+
+func TestInvoicePreviewPicksMostSpecificEffectiveRate(t *testing.T) {
+	const client = 7
+	erp := int64(1)
+	to := date("2025-06-30")
+	repo := &fakeBillingRepo{
+		client: &domain.Client{ID: client, Code: "ACME", Name: "Acme", Currency: "IDR"},
+		rates: []domain.RateCard{
+			{ID: 1, HourlyRate: 100, EffectiveFrom: date("2025-01-01")}, // default semua klien
+			{ID: 2, ClientID: ptr(int64(client)), HourlyRate: 200, EffectiveFrom: date("2025-01-01"), EffectiveTo: &to},
+			{ID: 3, ClientID: ptr(int64(client)), HourlyRate: 250, EffectiveFrom: date("2025-07-01")}, // naik mulai Juli
+			{ID: 4, ClientID: ptr(int64(client)), Position: "Senior Developer", HourlyRate: 400, EffectiveFrom: date("2025-01-01")},
+			{ID: 5, ProjectID: &erp, HourlyRate: 500, EffectiveFrom: date("2025-01-01")},
+			{ID: 6, ClientID: ptr(int64(99)), HourlyRate: 999, EffectiveFrom: date("2025-01-01")}, // klien lain
+		},
+		items: []domain.BillableItem{
+			{EntryID: 1, WorkDate: date("2025-06-30"), EmployeeID: 1, EmployeeName: "Andi", Hours: 8},
+			{EntryID: 2, WorkDate: date("2025-07-01"), EmployeeID: 1, EmployeeName: "Andi", Hours: 8},
+			{EntryID: 3, WorkDate: date("2025-07-01"), EmployeeID: 2, EmployeeName: "Budi", Position: "senior developer", Hours: 4},
+			{EntryID: 3, WorkDate: date("2025-07-01"), EmployeeID: 2, EmployeeName: "Budi", Position: "senior developer",
+				ProjectID: &erp, ProjectCode: "ERP", Hours: 4},
+		},
+	}
+	inv, err := usecase.NewBillingService(repo).InvoicePreview(client, date("2025-06-01"), date("2025-07-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{"Andi/2": 1600, "Andi/3": 2000, "Budi/4": 1600, "Budi/5": 2000}
+	if len(inv.Lines) != len(want) {
+		t.Fatalf("lines = %+v", inv.Lines)
+	}
+	for _, ln := range inv.Lines {
+		k := fmt.Sprintf("%s/%d", ln.EmployeeName, *ln.RateCardID)
+		if amount, ok := want[k]; !ok || ln.Amount != amount {
+			t.Errorf("line %s: amount = %v, want %v", k, ln.Amount, amount)
+		}
+	}
+	if inv.TotalHours != 24 || inv.TotalAmount != 7200 || inv.UnratedHours != 0 {
+		t.Errorf("total hours=%v amount=%v unrated=%v", inv.TotalHours, inv.TotalAmount, inv.UnratedHours)
+	}
+}
+
+func TestInvoicePreviewReportsUnratedHours(t *testing.T) {
+	repo := &fakeBillingRepo{
+		client: &domain.Client{ID: 1, Code: "ACME", Currency: "IDR"},
+		items:  []domain.BillableItem{{EntryID: 1, WorkDate: date("2025-07-01"), EmployeeID: 1, EmployeeName: "Andi", Hours: 2.5}},
+	}
+	svc := usecase.NewBillingService(repo)
+	inv, err := svc.InvoicePreview(1, date("2025-07-01"), date("2025-07-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.UnratedHours != 2.5 || inv.TotalAmount != 0 || inv.Lines[0].RateCardID != nil {
+		t.Errorf("preview = %+v", inv)
+	}
+	if _, err := svc.InvoicePreview(1, date("2025-07-31"), date("2025-07-01")); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("to < from: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestBillableEntryNeedsClient(t *testing.T) {
+	repo := newFakeRepo()
+	projects := newFakeProjects()
+	projects.projects[1].ClientID = ptr(int64(7))
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, nil, nil, projects)
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: date("2025-07-07"), TotalHours: ptr(8.0), Billable: true}
+	if _, err := svc.AddEntry(ctx, &e); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("billable tanpa klien: err = %v, want ErrInvalidInput", err)
+	}
+
+	e.Allocations = []domain.EntryAllocation{{ProjectID: 1, Hours: 8}}
+	if _, err := svc.AddEntry(ctx, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ClientID == nil || *e.ClientID != 7 {
+		t.Errorf("client_id = %v, want 7 dari project", e.ClientID)
+	}
+}
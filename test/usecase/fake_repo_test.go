@@ -0,0 +1,212 @@
+package usecase_test
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"timesheet-api/internal/auth"
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+// fakeTimesheetRepo menyimpan data di memori. Method yang tidak di-override
+// akan panic lewat interface embedded yang nil.
+type fakeTimesheetRepo struct {
+	repository.TimesheetRepository
+
+	sheets  map[int64]*domain.Timesheet
+	entries map[int64]*domain.TimesheetEntry
+	changes []domain.StatusChange
+	events  []domain.AuditEvent
+	nextID  int64
+}
+
+func newFakeRepo() *fakeTimesheetRepo {
+	return &fakeTimesheetRepo{sheets: map[int64]*domain.Timesheet{}, entries: map[int64]*domain.TimesheetEntry{}}
+}
+
+func (f *fakeTimesheetRepo) put(ts domain.Timesheet) int64 {
+	f.nextID++
+	ts.ID = f.nextID
+	if ts.Status == "" {
+		ts.Status = domain.StatusDraft
+	}
+	f.sheets[ts.ID] = &ts
+	return ts.ID
+}
+
+func (f *fakeTimesheetRepo) Create(_ context.Context, ts *domain.Timesheet, ev *domain.AuditEvent) (int64, error) {
+	id := f.put(*ts)
+	ts.ID = id
+	if ev != nil {
+		ev.TimesheetID, ev.EntityID = id, id
+	}
+	f.audit(ev)
+	return id, nil
+}
+
+func (f *fakeTimesheetRepo) FindByID(_ context.Context, id int64) (*domain.Timesheet, error) {
+	ts, ok := f.sheets[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *ts
+	if len(f.entries) > 0 {
+		cp.Entries = nil
+		for _, e := range f.entries {
+			if e.TimesheetID == id {
+				cp.Entries = append(cp.Entries, *e)
+			}
+		}
+		sort.Slice(cp.Entries, func(i, j int) bool { return cp.Entries[i].WorkDate.Before(cp.Entries[j].WorkDate) })
+	}
+	return &cp, nil
+}
+
+func (f *fakeTimesheetRepo) audit(ev *domain.AuditEvent) {
+	if ev != nil {
+		f.events = append(f.events, *ev)
+	}
+}
+
+func (f *fakeTimesheetRepo) ChangeStatus(_ context.Context, ch *domain.StatusChange, ev *domain.AuditEvent) error {
+	ts, ok := f.sheets[ch.TimesheetID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if ts.Status != ch.FromStatus {
+		return domain.ErrInvalidTransition
+	}
+	ts.Status = ch.ToStatus
+	f.changes = append(f.changes, *ch)
+	f.audit(ev)
+	return nil
+}
+
+func (f *fakeTimesheetRepo) FindEntryByID(_ context.Context, id int64) (*domain.TimesheetEntry, error) {
+	e, ok := f.entries[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (f *fakeTimesheetRepo) AddEntry(_ context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) (int64, error) {
+	f.nextID++
+	e.ID = f.nextID
+	cp := *e
+	f.entries[e.ID] = &cp
+	if ev != nil {
+		ev.EntityID = e.ID
+	}
+	f.audit(ev)
+	return e.ID, nil
+}
+
+func (f *fakeTimesheetRepo) AddEntries(ctx context.Context, items []repository.NewEntry) error {
+	for _, it := range items {
+		if _, err := f.AddEntry(ctx, it.Entry, it.Audit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeTimesheetRepo) UpdateEntry(_ context.Context, e *domain.TimesheetEntry, ev *domain.AuditEvent) error {
+	if _, ok := f.entries[e.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	cp := *e
+	f.entries[e.ID] = &cp
+	f.audit(ev)
+	return nil
+}
+
+func (f *fakeTimesheetRepo) List(_ context.Context, flt repository.Filter) ([]domain.Timesheet, error) {
+	var out []domain.Timesheet
+	for _, ts := range f.sheets {
+		if (flt.EmployeeID != nil && *flt.EmployeeID != ts.EmployeeID) ||
+			(flt.Month != nil && *flt.Month != ts.Month) || (flt.Year != nil && *flt.Year != ts.Year) {
+			continue
+		}
+		if sc := flt.Scope; sc != nil {
+			own := sc.EmployeeID != nil && *sc.EmployeeID == ts.EmployeeID
+			inDept := false
+			for _, d := range sc.DepartmentIDs {
+				if ts.DepartmentID != nil && *ts.DepartmentID == d {
+					inDept = true
+				}
+			}
+			if !own && !inDept {
+				continue
+			}
+		}
+		out = append(out, *ts)
+	}
+	return out, nil
+}
+
+func (f *fakeTimesheetRepo) Count(ctx context.Context, flt repository.Filter) (int64, error) {
+	items, _ := f.List(ctx, flt)
+	return int64(len(items)), nil
+}
+
+func (f *fakeTimesheetRepo) Stream(ctx context.Context, flt repository.Filter, withEntries bool, fn func(*domain.Timesheet) error) error {
+	items, _ := f.List(ctx, flt)
+	for i := range items {
+		ts := &items[i]
+		if withEntries {
+			ts, _ = f.FindByID(ctx, ts.ID)
+		}
+		if err := fn(ts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeDepartmentRepo: managed[employeeID] = departemen (sudah termasuk turunan).
+type fakeDepartmentRepo struct {
+	repository.DepartmentRepository
+	managed map[int64][]int64
+}
+
+func (f *fakeDepartmentRepo) ManagedBy(employeeID int64) ([]int64, error) {
+	return f.managed[employeeID], nil
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func as(role domain.Role, employeeID int64) context.Context {
+	p := &domain.Principal{UserID: employeeID, Username: string(role), Role: role}
+	if employeeID != 0 {
+		p.EmployeeID = &employeeID
+	}
+	return auth.WithPrincipal(context.Background(), p)
+}
+
+func (f *fakeTimesheetRepo) WeekRegularHours(_ context.Context, employeeID int64, from, before time.Time, excludeEntryID int64) (float64, error) {
+	var sum float64
+	for _, e := range f.entries {
+		ts := f.sheets[e.TimesheetID]
+		if ts == nil || ts.EmployeeID != employeeID || e.ID == excludeEntryID || e.WorkDate.Before(from) || !e.WorkDate.Before(before) {
+			continue
+		}
+		if e.Overtime != nil {
+			sum += e.Overtime.RegularHours
+		}
+	}
+	return sum, nil
+}
+
+// fakeOvertimeRepo mengembalikan policy yang sama untuk semua departemen.
+type fakeOvertimeRepo struct {
+	repository.OvertimePolicyRepository
+	policy *domain.OvertimePolicy
+}
+
+func (f *fakeOvertimeRepo) ForDepartment(*int64) (*domain.OvertimePolicy, error) {
+	return f.policy, nil
+}
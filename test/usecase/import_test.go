@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/usecase"
+)
+
+// fakeImportRepo menulis item lewat fakeTimesheetRepo supaya hasilnya bisa dicek.
+type fakeImportRepo struct{ sheets *fakeTimesheetRepo }
+
+func (f *fakeImportRepo) Import(ctx context.Context, items []domain.ImportItem) error {
+	for _, it := range items {
+		if it.Timesheet.ID == 0 {
+			if _, err := f.sheets.Create(ctx, it.Timesheet, it.TimesheetAudit); err != nil {
+				return err
+			}
+		}
+		it.Entry.TimesheetID = it.Timesheet.ID
+		if _, err := f.sheets.AddEntry(ctx, it.Entry, it.Audit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeEmployeeRepo) FindByCode(code string) (*domain.Employee, error) {
+	for _, e := range f.emps {
+		if e.Code != nil && *e.Code == code {
+			return e, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func newImportService(op *fakeOvertimeRepo) (*usecase.ImportService, *fakeTimesheetRepo) {
+	sheets := newFakeRepo()
+	emps := &fakeEmployeeRepo{emps: map[int64]*domain.Employee{
+		1: {ID: 1, Code: ptr("E001"), Name: "Budi"},
+		2: {ID: 2, Code: ptr("E002"), Name: "Sari"},
+	}}
+	if op == nil {
+		op = &fakeOvertimeRepo{}
+	}
+	ts := usecase.NewTimesheetService(sheets, emps, &fakeDepartmentRepo{}, op, nil, nil)
+	return usecase.NewImportService(&fakeImportRepo{sheets: sheets}, ts), sheets
+}
+
+func importRow(line int, code, day, start, end string) domain.ImportRow {
+	st, _ := usecase.ParseTime(start)
+	et, _ := usecase.ParseTime(end)
+	return domain.ImportRow{Line: line, EmployeeCode: code, Entry: domain.TimesheetEntry{WorkDate: date(day), StartTime: st, EndTime: et}}
+}
+
+func TestImportDryRunThenCommit(t *testing.T) {
+	svc, sheets := newImportService(nil)
+	july := sheets.put(domain.Timesheet{EmployeeID: 1, Month: 7, Year: 2025})
+	sheets.AddEntry(context.Background(), &domain.TimesheetEntry{TimesheetID: july, WorkDate: date("2025-07-01"), Type: domain.EntryWork}, nil)
+	sheets.put(domain.Timesheet{EmployeeID: 2, Month: 8, Year: 2025, Status: domain.StatusSubmitted})
+
+	leave := importRow(9, "E001", "2025-07-03", "", "")
+	leave.Entry.Type = domain.EntryLeave
+	byID := importRow(3, "", "2025-07-01", "08:00", "17:00")
+	byID.EmployeeID = 1
+	rows := []domain.ImportRow{
+		importRow(2, "E001", "2025-07-02", "08:00", "17:00"),
+		byID, // sudah ada entry di tanggal itu
+		importRow(4, "E001", "2025-08-04", "08:00", "17:00"), // timesheet Agustus belum ada
+		importRow(5, "E001", "2025-08-04", "09:00", "12:00"), // duplikat di file
+		importRow(6, "E999", "2025-07-02", "08:00", "17:00"),
+		importRow(7, "E002", "2025-08-05", "08:00", "17:00"), // timesheet sudah submitted
+		{Line: 8, Invalid: &domain.ImportError{Line: 8, Field: "date", Message: "format tanggal harus 2006-01-02"}},
+		leave, // cuti tanpa leave_type_id
+	}
+	ctx := as(domain.RoleHRAdmin, 0)
+
+	res, err := svc.Import(ctx, rows, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []int
+	for _, e := range res.Errors {
+		lines = append(lines, e.Line)
+	}
+	if res.Rows != 8 || res.Valid != 2 || res.Imported != 0 || res.NewTimesheets != 1 || len(lines) != 6 ||
+		lines[0] != 3 || lines[1] != 5 || lines[2] != 6 || lines[3] != 7 || lines[4] != 8 || lines[5] != 9 {
+		t.Fatalf("dry run = %+v, error lines %v", res, lines)
+	}
+	if len(sheets.sheets) != 2 || len(sheets.entries) != 1 || len(sheets.events) != 0 {
+		t.Fatalf("dry run menulis data: %d sheets, %d entries, %d events", len(sheets.sheets), len(sheets.entries), len(sheets.events))
+	}
+
+	res, err = svc.Import(ctx, rows, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 2 || res.NewTimesheets != 1 || len(res.Errors) != 6 {
+		t.Fatalf("commit = %+v", res)
+	}
+	if len(sheets.sheets) != 3 || len(sheets.entries) != 3 || len(sheets.events) != 3 {
+		t.Fatalf("commit: %d sheets, %d entries, %d events", len(sheets.sheets), len(sheets.entries), len(sheets.events))
+	}
+	for _, e := range sheets.entries {
+		if e.WorkDate.Equal(date("2025-08-04")) {
+			ts := sheets.sheets[e.TimesheetID]
+			if ts.EmployeeID != 1 || ts.Month != 8 || *e.TotalHours != 9 {
+				t.Errorf("entry agustus = %+v di timesheet %+v", *e, *ts)
+			}
+		}
+	}
+}
+
+func TestImportWeeklyOvertimeCountsEarlierRows(t *testing.T) {
+	policy := &domain.OvertimePolicy{ID: 1, DailyThresholdHours: 12, WeeklyThresholdHours: 40, OvertimeMultiplier: 1.5,
+		WeekendMultiplier: 2, HolidayMultiplier: 2}
+	svc, sheets := newImportService(&fakeOvertimeRepo{policy: policy})
+	// Senin–Jumat 10 jam dengan urutan file terbalik: Jumat tetap dihitung terakhir.
+	var rows []domain.ImportRow
+	for d := 11; d >= 7; d-- {
+		rows = append(rows, importRow(13-d, "E001", fmt.Sprintf("2025-07-%02d", d), "08:00", "18:00"))
+	}
+	res, err := svc.Import(as(domain.RoleHRAdmin, 0), rows, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 5 || len(res.Errors) != 0 {
+		t.Fatalf("commit = %+v", res)
+	}
+	for _, e := range sheets.entries {
+		want := 0.0
+		if e.WorkDate.Equal(date("2025-07-11")) {
+			want = 10
+		}
+		if *e.OvertimeHours != want {
+			t.Errorf("%s: overtime = %v, want %v", e.WorkDate.Format("2006-01-02"), *e.OvertimeHours, want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/usecase"
+)
+
+type fakeLeaveRepo struct {
+	repository.LeaveRepository
+	types     map[int64]*domain.LeaveType
+	requests  map[int64]*domain.LeaveRequest
+	decisions []domain.LeaveDecision
+	decideErr error // mis. saldo tidak cukup saat approve
+}
+
+func (f *fakeLeaveRepo) FindType(id int64) (*domain.LeaveType, error) {
+	t, ok := f.types[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeLeaveRepo) Balances(employeeID int64, year int) ([]domain.LeaveBalance, error) {
+	var out []domain.LeaveBalance
+	for _, t := range f.types {
+		if t.AnnualQuota > 0 {
+			out = append(out, domain.LeaveBalance{EmployeeID: employeeID, LeaveTypeID: t.ID, Year: year, Entitled: t.AnnualQuota, Remaining: t.AnnualQuota})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeLeaveRepo) CreateRequest(lr *domain.LeaveRequest) (int64, error) {
+	lr.ID = int64(len(f.requests) + 1)
+	cp := *lr
+	f.requests[lr.ID] = &cp
+	return lr.ID, nil
+}
+
+func (f *fakeLeaveRepo) FindRequest(id int64) (*domain.LeaveRequest, error) {
+	lr, ok := f.requests[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	cp := *lr
+	return &cp, nil
+}
+
+func (f *fakeLeaveRepo) Decide(_ context.Context, d *domain.LeaveDecision) error {
+	lr, ok := f.requests[d.RequestID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if lr.Status != d.From {
+		return domain.ErrInvalidTransition
+	}
+	if f.decideErr != nil {
+		return f.decideErr
+	}
+	lr.Status = d.To
+	f.decisions = append(f.decisions, *d)
+	return nil
+}
+
+type fakeEmployeeRepo struct {
+	repository.EmployeeRepository
+	emps map[int64]*domain.Employee
+}
+
+func (f *fakeEmployeeRepo) FindByID(id int64) (*domain.Employee, error) {
+	e, ok := f.emps[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return e, nil
+}
+
+type fakeHolidayRepo struct {
+	repository.HolidayRepository
+	days []domain.Holiday
+}
+
+func (f *fakeHolidayRepo) Between(from, to time.Time) ([]domain.Holiday, error) {
+	var out []domain.Holiday
+	for _, h := range f.days {
+		if !h.Date.Before(from) && !h.Date.After(to) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func date(s string) time.Time { d, _ := usecase.ParseDate(s); return d }
+
+func newLeaveService() (*usecase.LeaveService, *fakeLeaveRepo, *fakeTimesheetRepo) {
+	leaves := &fakeLeaveRepo{
+		types:    map[int64]*domain.LeaveType{1: {ID: 1, Code: "ANNUAL", Name: "Cuti Tahunan", AnnualQuota: 12}},
+		requests: map[int64]*domain.LeaveRequest{},
+	}
+	sheets := newFakeRepo()
+	emps := &fakeEmployeeRepo{emps: map[int64]*domain.Employee{
+		ownerID:   {ID: ownerID, Name: "Owner", DepartmentID: ptr(int64(deptID))},
+		managerID: {ID: managerID, Name: "Manager"},
+	}}
+	depts := &fakeDepartmentRepo{managed: map[int64][]int64{managerID: {deptID}}}
+	hols := &fakeHolidayRepo{days: []domain.Holiday{{Date: date("2025-08-18"), Name: "Cuti bersama HUT RI"}}}
+	return usecase.NewLeaveService(leaves, sheets, emps, depts, hols), leaves, sheets
+}
+
+func TestLeaveRequestCountsWorkingDaysOnly(t *testing.T) {
+	svc, _, _ := newLeaveService()
+	// Jum 15, Sab 16, Min 17, Sen 18 (libur), Sel 19 → 2 hari kerja.
+	lr := &domain.LeaveRequest{LeaveTypeID: 1, StartDate: date("2025-08-15"), EndDate: date("2025-08-19")}
+	if _, err := svc.CreateRequest(as(domain.RoleEmployee, ownerID), lr); err != nil {
+		t.Fatal(err)
+	}
+	if lr.EmployeeID != ownerID || lr.Days != 2 || lr.Status != domain.LeavePending {
+		t.Fatalf("request = %+v", lr)
+	}
+}
+
+func TestLeaveRequestRejectsOverQuota(t *testing.T) {
+	svc, _, _ := newLeaveService()
+	lr := &domain.LeaveRequest{LeaveTypeID: 1, StartDate: date("2025-09-01"), EndDate: date("2025-09-30")}
+	_, err := svc.CreateRequest(as(domain.RoleEmployee, ownerID), lr)
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestLeaveApprovalWritesDaysIntoMonthlyTimesheets(t *testing.T) {
+	svc, leaves, sheets := newLeaveService()
+	lr := &domain.LeaveRequest{LeaveTypeID: 1, StartDate: date("2025-07-31"), EndDate: date("2025-08-01")}
+	id, err := svc.CreateRequest(as(domain.RoleEmployee, ownerID), lr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Approve(as(domain.RoleEmployee, ownerID), id, ""); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("self-approve err = %v, want ErrForbidden", err)
+	}
+	if err := svc.Approve(as(domain.RoleManager, managerID), id, "ok"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(leaves.decisions) != 1 {
+		t.Fatalf("decisions = %d", len(leaves.decisions))
+	}
+	d := leaves.decisions[0]
+	if d.To != domain.LeaveApproved || d.Actor != string(domain.RoleManager) || len(d.Days) != 2 {
+		t.Fatalf("decision = %+v", d)
+	}
+	if d.Days[0].Timesheet == d.Days[1].Timesheet {
+		t.Fatal("Juli dan Agustus harus masuk timesheet berbeda")
+	}
+	// Timesheet baru dibuat repository di transaksi Decide, bukan sebelumnya.
+	if len(sheets.sheets) != 0 {
+		t.Fatalf("timesheet dibuat di luar Decide: %d", len(sheets.sheets))
+	}
+	for _, day := range d.Days {
+		ts := day.Timesheet
+		if ts == nil || ts.ID != 0 || ts.EmployeeID != ownerID || ts.Month != int(day.Date.Month()) || day.TimesheetAudit == nil {
+			t.Fatalf("timesheet untuk %s = %+v", day.Date.Format("2006-01-02"), ts)
+		}
+	}
+	if d.Audit == nil || d.Audit.Entity != domain.AuditEntityEntry {
+		t.Fatalf("audit template = %+v", d.Audit)
+	}
+
+	if err := svc.Approve(as(domain.RoleHRAdmin, 0), id, ""); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("approve ulang err = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestLeaveApprovalReusesExistingTimesheetAndRecountsDays(t *testing.T) {
+	svc, leaves, sheets := newLeaveService()
+	july := sheets.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	lr := &domain.LeaveRequest{LeaveTypeID: 1, StartDate: date("2025-07-29"), EndDate: date("2025-07-31")}
+	id, err := svc.CreateRequest(as(domain.RoleEmployee, ownerID), lr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Kalender libur berubah setelah pengajuan: 30 Juli jadi libur.
+	svc = usecase.NewLeaveService(leaves, sheets, &fakeEmployeeRepo{emps: map[int64]*domain.Employee{
+		ownerID: {ID: ownerID, Name: "Owner", DepartmentID: ptr(int64(deptID))}}}, &fakeDepartmentRepo{},
+		&fakeHolidayRepo{days: []domain.Holiday{{Date: date("2025-07-30"), Name: "Libur baru"}}})
+
+	leaves.decideErr = errors.New("saldo tidak cukup")
+	if err := svc.Approve(as(domain.RoleHRAdmin, 0), id, ""); err == nil {
+		t.Fatal("approve harus gagal")
+	}
+	if len(sheets.sheets) != 1 {
+		t.Fatalf("approve gagal tidak boleh meninggalkan timesheet: %d", len(sheets.sheets))
+	}
+
+	leaves.decideErr = nil
+	if err := svc.Approve(as(domain.RoleHRAdmin, 0), id, ""); err != nil {
+		t.Fatal(err)
+	}
+	d := leaves.decisions[0]
+	if len(d.Days) != 2 {
+		t.Fatalf("hari cuti = %d, want 2 (hari libur baru tidak dihitung)", len(d.Days))
+	}
+	for _, day := range d.Days {
+		if day.Timesheet.ID != july || day.TimesheetAudit != nil {
+			t.Fatalf("hari %s harus masuk timesheet Juli yang sudah ada: %+v", day.Date.Format("2006-01-02"), day.Timesheet)
+		}
+	}
+}
+
+func TestLeaveCancelOnlyByOwner(t *testing.T) {
+	svc, _, _ := newLeaveService()
+	lr := &domain.LeaveRequest{LeaveTypeID: 1, StartDate: date("2025-08-20"), EndDate: date("2025-08-20")}
+	id, err := svc.CreateRequest(as(domain.RoleEmployee, ownerID), lr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Cancel(as(domain.RoleEmployee, 99), id); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("err = %v, want ErrForbidden", err)
+	}
+	if err := svc.Cancel(as(domain.RoleEmployee, ownerID), id); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/usecase"
+)
+
+func TestAddEntryComputesOvertime(t *testing.T) {
+	repo := newFakeRepo()
+	policy := &domain.OvertimePolicy{ID: 1, Name: "Standar", DailyThresholdHours: 8, WeeklyThresholdHours: 40,
+		OvertimeMultiplier: 1.5, WeekendMultiplier: 2, HolidayMultiplier: 2, BreakMinutes: 60, BreakAfterHours: 6}
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, &fakeOvertimeRepo{policy: policy}, nil, nil)
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	clock := func(h int) *time.Time { t := time.Date(0, 1, 1, h, 0, 0, 0, time.UTC); return &t }
+	// Senin–Kamis 08:00–17:00 (8 jam bersih), Jumat 08:00–20:00.
+	var last domain.TimesheetEntry
+	for d := 7; d <= 11; d++ {
+		end := 17
+		if d == 11 {
+			end = 20
+		}
+		last = domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC),
+			StartTime: clock(8), EndTime: clock(end), OvertimeHours: ptr(99.0)}
+		if _, err := svc.AddEntry(ctx, &last); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := last.Overtime
+	if b == nil {
+		t.Fatal("breakdown tidak terisi")
+	}
+	// Jumat: 12 jam - 1 jam istirahat = 11; 3 jam lewat batas harian, 8 jam sisanya reguler (total minggu 40).
+	if *last.TotalHours != 11 || *last.OvertimeHours != 3 || b.DailyOvertime != 3 || b.WeeklyOvertime != 0 || b.RegularHours != 8 {
+		t.Errorf("jumat: total=%v ot=%v breakdown=%+v", *last.TotalHours, *last.OvertimeHours, *b)
+	}
+
+	sat := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC), TotalHours: ptr(4.0)}
+	if _, err := svc.AddEntry(ctx, &sat); err != nil {
+		t.Fatal(err)
+	}
+	if *sat.OvertimeHours != 4 || sat.Overtime.WeightedHours != 8 {
+		t.Errorf("sabtu: ot=%v breakdown=%+v", *sat.OvertimeHours, *sat.Overtime)
+	}
+}
+
+func TestAddEntryOvernightShift(t *testing.T) {
+	repo := newFakeRepo()
+	policy := &domain.OvertimePolicy{ID: 1, Name: "Shift", DailyThresholdHours: 8, OvertimeMultiplier: 1.5,
+		WeekendMultiplier: 2, HolidayMultiplier: 2, NightStart: "22:00", NightEnd: "06:00"}
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, &fakeOvertimeRepo{policy: policy}, nil, nil)
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	start, _ := usecase.ParseTime("21:00")
+	end, _ := usecase.ParseTime("07:00")
+	// Kamis 31 Juli 21:00 – Jumat 1 Agustus 07:00: tetap milik work_date 31 Juli.
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC), StartTime: start, EndTime: end}
+	if _, err := svc.AddEntry(ctx, &e); err != nil {
+		t.Fatal(err)
+	}
+	if !e.EndsNextDay || *e.TotalHours != 10 || *e.OvertimeHours != 2 || *e.NightHours != 8 {
+		t.Errorf("entry = next_day=%v total=%v ot=%v night=%v", e.EndsNextDay, *e.TotalHours, *e.OvertimeHours, *e.NightHours)
+	}
+	if e.Overtime.DayType != domain.DayTypeWorkday {
+		t.Errorf("day_type = %s, want workday (ikut work_date)", e.Overtime.DayType)
+	}
+
+	same := domain.TimesheetEntry{TimesheetID: id, WorkDate: e.WorkDate, StartTime: start, EndTime: start}
+	if _, err := svc.AddEntry(ctx, &same); err == nil {
+		t.Error("start_time = end_time harus ditolak")
+	}
+}
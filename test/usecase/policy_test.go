@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+)
+
+func TestPolicyAccess(t *testing.T) {
+	repo, svc := newWorkflowService()
+	own := repo.put(domain.Timesheet{EmployeeID: ownerID, DepartmentID: ptr(int64(deptID)), Month: 7, Year: 2025})
+	other := repo.put(domain.Timesheet{EmployeeID: 99, DepartmentID: ptr(int64(77)), Month: 7, Year: 2025})
+	mgrOwn := repo.put(domain.Timesheet{EmployeeID: managerID, DepartmentID: ptr(int64(deptID)), Month: 7, Year: 2025})
+
+	owner, manager, hr := as(domain.RoleEmployee, ownerID), as(domain.RoleManager, managerID), as(domain.RoleHRAdmin, 0)
+
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"employee lihat miliknya", get(svc.GetTimesheet, owner, own), nil},
+		{"employee lihat milik orang lain", get(svc.GetTimesheet, owner, other), domain.ErrForbidden},
+		{"manager lihat departemennya", get(svc.GetTimesheet, manager, own), nil},
+		{"manager lihat departemen lain", get(svc.GetTimesheet, manager, other), domain.ErrForbidden},
+		{"hr lihat apa saja", get(svc.GetTimesheet, hr, other), nil},
+		{"anonim", get(svc.GetTimesheet, context.Background(), own), domain.ErrUnauthorized},
+		{"manager ubah entry bawahan", addEntry(svc, manager, own), domain.ErrForbidden},
+		{"employee submit milik orang lain", svc.Submit(owner, other), domain.ErrForbidden},
+	}
+	for _, tc := range cases {
+		if !errors.Is(tc.err, tc.want) || (tc.want == nil && tc.err != nil) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.err, tc.want)
+		}
+	}
+
+	// Manager tidak boleh approve timesheet miliknya sendiri.
+	if err := svc.Submit(manager, mgrOwn); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Approve(manager, mgrOwn); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("manager approve milik sendiri: got %v, want ErrForbidden", err)
+	}
+	// Employee tidak boleh approve sama sekali.
+	if err := svc.Submit(owner, own); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Approve(owner, own); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("employee approve: got %v, want ErrForbidden", err)
+	}
+}
+
+func TestListScopedToCaller(t *testing.T) {
+	repo, svc := newWorkflowService()
+	repo.put(domain.Timesheet{EmployeeID: ownerID, DepartmentID: ptr(int64(deptID)), Month: 7, Year: 2025})
+	repo.put(domain.Timesheet{EmployeeID: 99, DepartmentID: ptr(int64(77)), Month: 7, Year: 2025})
+	repo.put(domain.Timesheet{EmployeeID: 98, DepartmentID: ptr(int64(deptID)), Month: 7, Year: 2025})
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want int
+	}{
+		"employee": {as(domain.RoleEmployee, ownerID), 1},
+		"manager":  {as(domain.RoleManager, managerID), 2},
+		"hr_admin": {as(domain.RoleHRAdmin, 0), 3},
+	} {
+		items, err := svc.ListTimesheets(tc.ctx, repository.Filter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != tc.want {
+			t.Errorf("%s: got %d timesheet, want %d", name, len(items), tc.want)
+		}
+		n := 0
+		if err := svc.StreamTimesheets(tc.ctx, repository.Filter{}, true, func(*domain.Timesheet) error { n++; return nil }); err != nil {
+			t.Fatal(err)
+		}
+		if n != tc.want {
+			t.Errorf("%s: stream %d timesheet, want %d", name, n, tc.want)
+		}
+		if _, total, err := svc.PageTimesheets(tc.ctx, repository.Filter{PageSize: 1}); err != nil || total != int64(tc.want) {
+			t.Errorf("%s: page total = %d (%v), want %d", name, total, err, tc.want)
+		}
+	}
+}
+
+func get(fn func(context.Context, int64) (*domain.Timesheet, error), ctx context.Context, id int64) error {
+	_, err := fn(ctx, id)
+	return err
+}
+
+func addEntry(svc interface {
+	AddEntry(context.Context, *domain.TimesheetEntry) (int64, error)
+}, ctx context.Context, id int64) error {
+	_, err := svc.AddEntry(ctx, &domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)})
+	return err
+}
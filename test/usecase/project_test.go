@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/usecase"
+)
+
+type fakeProjectRepo struct {
+	repository.ProjectRepository
+	projects map[int64]*domain.Project
+	tasks    map[int64]*domain.Task
+	hours    []domain.ProjectHours
+	filter   repository.ProjectHoursFilter
+}
+
+func (f *fakeProjectRepo) FindByID(id int64) (*domain.Project, error) {
+	p, ok := f.projects[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (f *fakeProjectRepo) FindTask(id int64) (*domain.Task, error) {
+	t, ok := f.tasks[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeProjectRepo) Hours(projectID int64, flt repository.ProjectHoursFilter) ([]domain.ProjectHours, error) {
+	f.filter = flt
+	return f.hours, nil
+}
+
+func newFakeProjects() *fakeProjectRepo {
+	return &fakeProjectRepo{
+		projects: map[int64]*domain.Project{
+			1: {ID: 1, Code: "ERP", Name: "ERP Rollout", Active: true},
+			2: {ID: 2, Code: "OLD", Name: "Legacy", Active: false},
+		},
+		tasks: map[int64]*domain.Task{
+			10: {ID: 10, ProjectID: 1, Name: "Development", Active: true},
+			20: {ID: 20, ProjectID: 2, Name: "Support", Active: true},
+		},
+	}
+}
+
+func TestEntryAllocationsValidated(t *testing.T) {
+	repo := newFakeRepo()
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, nil, nil, newFakeProjects())
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	cases := map[string][]domain.EntryAllocation{
+		"melebihi total_hours": {{ProjectID: 1, Hours: 5}, {ProjectID: 1, TaskID: ptr(int64(10)), Hours: 4}},
+		"project tidak aktif":  {{ProjectID: 2, Hours: 1}},
+		"project tidak ada":    {{ProjectID: 99, Hours: 1}},
+		"task project lain":    {{ProjectID: 1, TaskID: ptr(int64(20)), Hours: 1}},
+		"jam nol":              {{ProjectID: 1, Hours: 0}},
+	}
+	for name, allocs := range cases {
+		e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC), TotalHours: ptr(8.0), Allocations: allocs}
+		if _, err := svc.AddEntry(ctx, &e); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC), TotalHours: ptr(8.0),
+		Allocations: []domain.EntryAllocation{{ProjectID: 1, TaskID: ptr(int64(10)), Hours: 6}, {ProjectID: 1, Hours: 2}}}
+	if _, err := svc.AddEntry(ctx, &e); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSegmentAllocationsSummedPerTask(t *testing.T) {
+	repo := newFakeRepo()
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, nil, nil, newFakeProjects())
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	dev := func(s domain.EntrySegment) domain.EntrySegment {
+		s.ProjectID, s.TaskID = ptr(int64(1)), ptr(int64(10))
+		return s
+	}
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC),
+		Segments: []domain.EntrySegment{
+			dev(seg(domain.SegmentWork, "08:00", "12:00")),
+			seg(domain.SegmentBreak, "12:00", "12:30"),
+			dev(seg(domain.SegmentWork, "12:30", "15:00")),
+			seg(domain.SegmentWork, "15:00", "17:00"), // tanpa project
+		}}
+	if _, err := svc.AddEntry(ctx, &e); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Allocations) != 1 || e.Allocations[0].Hours != 6.5 || *e.Allocations[0].TaskID != 10 {
+		t.Errorf("allocations = %+v, want satu baris 6,5 jam task 10", e.Allocations)
+	}
+
+	withBoth := e
+	withBoth.Allocations = []domain.EntryAllocation{{ProjectID: 1, Hours: 1}}
+	if err := svc.UpdateEntry(ctx, &withBoth); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("alokasi entry + segmen: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestProjectHoursReportScopedToCaller(t *testing.T) {
+	projects := newFakeProjects()
+	projects.hours = []domain.ProjectHours{
+		{EmployeeID: ownerID, EmployeeName: "Andi", Year: 2025, Month: 7, Hours: 40.25},
+		{EmployeeID: ownerID, EmployeeName: "Andi", Year: 2025, Month: 8, Hours: 12.5},
+	}
+	svc := usecase.NewProjectService(projects, &fakeDepartmentRepo{})
+
+	r, err := svc.Hours(as(domain.RoleEmployee, ownerID), 1, repository.ProjectHoursFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.TotalHours != 52.75 || r.Project.Code != "ERP" {
+		t.Errorf("report = %+v", r)
+	}
+	if projects.filter.Scope == nil || *projects.filter.Scope.EmployeeID != ownerID {
+		t.Errorf("karyawan harus dibatasi ke jamnya sendiri, scope = %+v", projects.filter.Scope)
+	}
+
+	if _, err := svc.Hours(as(domain.RoleHRAdmin, 0), 99, repository.ProjectHoursFilter{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("project tidak ada: err = %v, want ErrNotFound", err)
+	}
+}
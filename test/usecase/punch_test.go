@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/usecase"
+)
+
+// fakePunchRepo menulis entry lewat fakeTimesheetRepo supaya hasilnya bisa dicek.
+type fakePunchRepo struct {
+	repository.PunchRepository
+	sheets *fakeTimesheetRepo
+	events []domain.Punch
+}
+
+func (f *fakePunchRepo) last(employeeID int64) *domain.Punch {
+	for i := len(f.events) - 1; i >= 0; i-- {
+		if f.events[i].EmployeeID == employeeID {
+			return &f.events[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakePunchRepo) LastOpen(employeeID int64) (*domain.Punch, error) {
+	if p := f.last(employeeID); p != nil && p.Kind == domain.PunchIn {
+		cp := *p
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (f *fakePunchRepo) Record(_ context.Context, p *domain.Punch, e *domain.TimesheetEntry, ev *domain.AuditEvent) error {
+	last := f.last(p.EmployeeID)
+	if (p.Kind == domain.PunchIn) == (last != nil && last.Kind == domain.PunchIn) {
+		return domain.ErrInvalidTransition
+	}
+	if e != nil {
+		var err error
+		if e.ID == 0 {
+			_, err = f.sheets.AddEntry(context.Background(), e, ev)
+		} else {
+			err = f.sheets.UpdateEntry(context.Background(), e, ev)
+		}
+		if err != nil {
+			return err
+		}
+		p.EntryID = &e.ID
+	}
+	p.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, *p)
+	return nil
+}
+
+var wib = time.FixedZone("WIB", 7*3600)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+func (c *fakeClock) set(s string) {
+	c.t, _ = time.ParseInLocation("2006-01-02 15:04", s, wib)
+}
+
+func newPunchService(rule usecase.PunchRule) (*usecase.PunchService, *fakeTimesheetRepo, *fakeClock) {
+	sheets := newFakeRepo()
+	emps := &fakeEmployeeRepo{emps: map[int64]*domain.Employee{
+		ownerID: {ID: ownerID, Name: "Owner", DepartmentID: ptr(int64(deptID)), Active: true},
+	}}
+	ts := usecase.NewTimesheetService(sheets, emps, &fakeDepartmentRepo{}, nil, &fakeHolidayRepo{}, nil)
+	clk := &fakeClock{}
+	svc := usecase.NewPunchService(&fakePunchRepo{sheets: sheets}, ts, rule, wib).WithClock(clk.now)
+	return svc, sheets, clk
+}
+
+var defaultRule = usecase.PunchRule{MaxOpen: 16 * time.Hour, CloseAfter: 8 * time.Hour}
+
+func TestPunchInOutBuildsEntry(t *testing.T) {
+	svc, sheets, clk := newPunchService(defaultRule)
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	clk.set("2025-08-04 08:01")
+	p, e, err := svc.PunchIn(ctx, usecase.PunchInput{Latitude: ptr(-6.2), Longitude: ptr(106.8), Device: "android"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.EmployeeID != ownerID || p.Kind != domain.PunchIn || p.EntryID == nil || *p.EntryID != e.ID {
+		t.Fatalf("punch = %+v", p)
+	}
+	ts := sheets.sheets[e.TimesheetID]
+	if ts == nil || ts.Month != 8 || ts.Year != 2025 || ts.EmployeeID != ownerID {
+		t.Fatalf("timesheet bulanan tidak dibuat: %+v", ts)
+	}
+	if got := e.StartTime.Format("15:04"); got != "08:01" || e.WorkDate.Format("2006-01-02") != "2025-08-04" {
+		t.Fatalf("entry = %s %s", e.WorkDate.Format("2006-01-02"), got)
+	}
+
+	if _, _, err := svc.PunchIn(ctx, usecase.PunchInput{}); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("punch in ganda err = %v, want ErrInvalidTransition", err)
+	}
+
+	clk.set("2025-08-04 17:31")
+	_, e, err = svc.PunchOut(ctx, usecase.PunchInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.EndTime.Format("15:04") != "17:31" || e.TotalHours == nil || *e.TotalHours != 9.5 || e.Flagged {
+		t.Fatalf("entry setelah punch out = %+v", e)
+	}
+	if len(sheets.entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(sheets.entries))
+	}
+}
+
+func TestPunchForgottenOutIsAutoClosedAndFlagged(t *testing.T) {
+	svc, sheets, clk := newPunchService(usecase.PunchRule{MaxOpen: 16 * time.Hour, CloseAfter: 8 * time.Hour, CloseAt: "17:00"})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	clk.set("2025-08-04 08:00")
+	_, first, err := svc.PunchIn(ctx, usecase.PunchInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk.set("2025-08-05 07:55")
+	_, second, err := svc.PunchIn(ctx, usecase.PunchInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID == first.ID {
+		t.Fatal("hari berikutnya harus entry baru")
+	}
+
+	closed := sheets.entries[first.ID]
+	if !closed.Flagged || closed.FlagReason == "" || closed.EndTime.Format("15:04") != "17:00" || *closed.TotalHours != 9 {
+		t.Fatalf("entry auto-close = %+v", closed)
+	}
+}
+
+func TestPunchOutWithoutInAndForOthers(t *testing.T) {
+	svc, _, clk := newPunchService(defaultRule)
+	clk.set("2025-08-04 17:00")
+	if _, _, err := svc.PunchOut(as(domain.RoleEmployee, ownerID), usecase.PunchInput{}); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("err = %v, want ErrInvalidTransition", err)
+	}
+	if _, _, err := svc.PunchIn(as(domain.RoleEmployee, 99), usecase.PunchInput{EmployeeID: ownerID}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("err = %v, want ErrForbidden", err)
+	}
+}
+
+func TestPunchOutNextMorningClosesNightShift(t *testing.T) {
+	svc, _, clk := newPunchService(defaultRule)
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	clk.set("2025-08-04 22:00")
+	if _, _, err := svc.PunchIn(ctx, usecase.PunchInput{}); err != nil {
+		t.Fatal(err)
+	}
+	clk.set("2025-08-05 06:15")
+	_, e, err := svc.PunchOut(ctx, usecase.PunchInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.WorkDate.Format("2006-01-02") != "2025-08-04" || !e.EndsNextDay || *e.TotalHours != 8.25 || *e.NightHours != 8 {
+		t.Fatalf("entry = %+v", e)
+	}
+}
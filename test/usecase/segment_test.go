@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/usecase"
+)
+
+func seg(typ domain.SegmentType, start, end string) domain.EntrySegment {
+	st, _ := usecase.ParseTime(start)
+	et, _ := usecase.ParseTime(end)
+	return domain.EntrySegment{Type: typ, StartTime: *st, EndTime: *et}
+}
+
+func TestEntryTotalsDerivedFromSegments(t *testing.T) {
+	repo := newFakeRepo()
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, nil, nil, nil)
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	callout := seg(domain.SegmentOnCall, "00:30", "02:00")
+	callout.StartsNextDay = true
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC), TotalHours: ptr(99.0),
+		Segments: []domain.EntrySegment{
+			seg(domain.SegmentWork, "13:00", "17:00"),
+			seg(domain.SegmentWork, "08:00", "12:00"),
+			seg(domain.SegmentBreak, "12:00", "13:00"),
+			callout,
+		}}
+	if _, err := svc.AddEntry(ctx, &e); err != nil {
+		t.Fatal(err)
+	}
+	// 4 + 4 + 1,5 jam; istirahat tidak dihitung; panggilan 00:30–02:00 jatuh di jam malam.
+	if *e.TotalHours != 9.5 || *e.NightHours != 1.5 || !e.EndsNextDay {
+		t.Errorf("total=%v night=%v next_day=%v", *e.TotalHours, *e.NightHours, e.EndsNextDay)
+	}
+	if e.StartTime.Format("15:04") != "08:00" || e.EndTime.Format("15:04") != "02:00" {
+		t.Errorf("start=%s end=%s", e.StartTime.Format("15:04"), e.EndTime.Format("15:04"))
+	}
+	if e.Segments[0].StartTime.Format("15:04") != "08:00" || e.Segments[1].Type != domain.SegmentBreak {
+		t.Errorf("segmen tidak diurutkan: %+v", e.Segments)
+	}
+
+	dup := domain.TimesheetEntry{TimesheetID: id, WorkDate: e.WorkDate, TotalHours: ptr(1.0)}
+	if _, err := svc.AddEntry(ctx, &dup); !errors.Is(err, domain.ErrDuplicate) {
+		t.Errorf("entry kedua di tanggal sama: err = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestOverlappingSegmentsRejected(t *testing.T) {
+	repo := newFakeRepo()
+	svc := usecase.NewTimesheetService(repo, nil, &fakeDepartmentRepo{}, nil, nil, nil)
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	ctx := as(domain.RoleEmployee, ownerID)
+
+	cases := map[string][]domain.EntrySegment{
+		"bertumpuk":          {seg(domain.SegmentWork, "08:00", "12:00"), seg(domain.SegmentBreak, "11:30", "12:30")},
+		"di dalam segmen":    {seg(domain.SegmentWork, "08:00", "17:00"), seg(domain.SegmentWork, "09:00", "10:00"), seg(domain.SegmentWork, "11:00", "12:00")},
+		"malam lintas hari":  {seg(domain.SegmentWork, "22:00", "02:00"), seg(domain.SegmentWork, "23:00", "23:30")},
+		"tipe tidak dikenal": {seg("nap", "08:00", "09:00")},
+	}
+	for name, segs := range cases {
+		e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC), Segments: segs}
+		if _, err := svc.AddEntry(ctx, &e); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/usecase"
+)
+
+const (
+	ownerID   = 10
+	managerID = 20
+	deptID    = 5
+)
+
+func newWorkflowService() (*fakeTimesheetRepo, *usecase.TimesheetService) {
+	repo := newFakeRepo()
+	depts := &fakeDepartmentRepo{managed: map[int64][]int64{managerID: {deptID}}}
+	return repo, usecase.NewTimesheetService(repo, nil, depts, nil, nil, nil)
+}
+
+func TestWorkflowTransitions(t *testing.T) {
+	repo, svc := newWorkflowService()
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, DepartmentID: ptr(int64(deptID)), Month: 7, Year: 2025})
+	owner, manager, hr := as(domain.RoleEmployee, ownerID), as(domain.RoleManager, managerID), as(domain.RoleHRAdmin, 0)
+
+	if err := svc.Approve(manager, id); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("approve dari draft: got %v, want ErrInvalidTransition", err)
+	}
+	if err := svc.Submit(owner, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Reject(manager, id, ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("reject tanpa alasan: got %v, want ErrInvalidInput", err)
+	}
+	if err := svc.Reject(manager, id, "jam tanggal 3 salah"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Submit(owner, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Approve(manager, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Reopen(hr, id, "koreksi payroll"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		to    domain.TimesheetStatus
+		actor string
+	}{
+		{domain.StatusSubmitted, "employee"}, {domain.StatusRejected, "manager"}, {domain.StatusSubmitted, "employee"},
+		{domain.StatusApproved, "manager"}, {domain.StatusReopened, "hr_admin"},
+	}
+	if len(repo.changes) != len(want) {
+		t.Fatalf("riwayat: got %d perubahan, want %d", len(repo.changes), len(want))
+	}
+	for i, ch := range repo.changes {
+		if ch.ToStatus != want[i].to || ch.Actor != want[i].actor {
+			t.Errorf("riwayat[%d] = %s oleh %q, want %s oleh %q", i, ch.ToStatus, ch.Actor, want[i].to, want[i].actor)
+		}
+	}
+}
+
+func TestEntriesLockedAfterSubmit(t *testing.T) {
+	repo, svc := newWorkflowService()
+	id := repo.put(domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025})
+	owner := as(domain.RoleEmployee, ownerID)
+
+	e := domain.TimesheetEntry{TimesheetID: id, WorkDate: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)}
+	entryID, err := svc.AddEntry(owner, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Submit(owner, id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.AddEntry(owner, &domain.TimesheetEntry{TimesheetID: id, WorkDate: e.WorkDate}); !errors.Is(err, domain.ErrLocked) {
+		t.Errorf("AddEntry setelah submit: got %v, want ErrLocked", err)
+	}
+	if err := svc.UpdateEntry(owner, &domain.TimesheetEntry{ID: entryID, Remarks: "ubah"}); !errors.Is(err, domain.ErrLocked) {
+		t.Errorf("UpdateEntry setelah submit: got %v, want ErrLocked", err)
+	}
+	if err := svc.DeleteEntry(owner, entryID); !errors.Is(err, domain.ErrLocked) {
+		t.Errorf("DeleteEntry setelah submit: got %v, want ErrLocked", err)
+	}
+}
@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"timesheet-api/internal/domain"
+	"timesheet-api/internal/repository"
+	"timesheet-api/internal/usecase"
+)
+
+// fakeTxManager menyalin isi fakeTimesheetRepo sebelum fn dan mengembalikannya
+// jika fn gagal, meniru rollback.
+type fakeTxManager struct {
+	repo  *fakeTimesheetRepo
+	calls int
+}
+
+func (m *fakeTxManager) WithinTx(ctx context.Context, fn func(context.Context, repository.TxRepos) error) error {
+	m.calls++
+	r := m.repo
+	sheets, entries := map[int64]*domain.Timesheet{}, map[int64]*domain.TimesheetEntry{}
+	for k, v := range r.sheets {
+		sheets[k] = v
+	}
+	for k, v := range r.entries {
+		entries[k] = v
+	}
+	events, nextID := len(r.events), r.nextID
+	if err := fn(ctx, repository.TxRepos{Timesheets: r}); err != nil {
+		r.sheets, r.entries, r.events, r.nextID = sheets, entries, r.events[:events], nextID
+		return err
+	}
+	return nil
+}
+
+func TestCreateTimesheetWithEntriesIsAtomic(t *testing.T) {
+	repo := newFakeRepo()
+	emps := &fakeEmployeeRepo{emps: map[int64]*domain.Employee{ownerID: {ID: ownerID, Name: "Budi"}}}
+	tx := &fakeTxManager{repo: repo}
+	svc := usecase.NewTimesheetService(repo, emps, &fakeDepartmentRepo{}, nil, nil, nil).WithTx(tx)
+	ctx := as(domain.RoleHRAdmin, 0)
+	st, _ := usecase.ParseTime("08:00")
+	et, _ := usecase.ParseTime("17:00")
+	entry := func(day string) domain.TimesheetEntry {
+		return domain.TimesheetEntry{WorkDate: date(day), StartTime: st, EndTime: et}
+	}
+
+	ts := &domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025,
+		Entries: []domain.TimesheetEntry{entry("2025-07-01"), entry("2025-07-01")}}
+	if _, err := svc.CreateTimesheet(ctx, ts); !errors.Is(err, domain.ErrDuplicate) {
+		t.Fatalf("entry duplikat: got %v, want ErrDuplicate", err)
+	}
+	if len(repo.sheets) != 0 || len(repo.entries) != 0 || len(repo.events) != 0 {
+		t.Fatalf("rollback: %d sheets, %d entries, %d events", len(repo.sheets), len(repo.entries), len(repo.events))
+	}
+
+	ts = &domain.Timesheet{EmployeeID: ownerID, Month: 7, Year: 2025,
+		Entries: []domain.TimesheetEntry{entry("2025-07-01"), entry("2025-07-02")}}
+	id, err := svc.CreateTimesheet(ctx, ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.calls != 2 || len(repo.sheets) != 1 || len(repo.entries) != 2 || len(repo.events) != 3 {
+		t.Fatalf("commit: %d tx, %d sheets, %d entries, %d events", tx.calls, len(repo.sheets), len(repo.entries), len(repo.events))
+	}
+	for _, e := range repo.entries {
+		if e.TimesheetID != id || e.TotalHours == nil {
+			t.Errorf("entry = %+v", *e)
+		}
+	}
+}